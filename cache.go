@@ -0,0 +1,306 @@
+package gofred
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"encoding/gob"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+//==============================================================================
+// caching
+//==============================================================================
+
+// A cached response body.
+type CacheEntry struct {
+	Body    []byte
+	Stored  time.Time // when the response was received
+	Expires time.Time // zero if the entry never expires
+}
+
+// Whether the entry has expired at the given time.
+func (e CacheEntry) Expired(now time.Time) bool {
+	return !e.Expires.IsZero() && now.After(e.Expires)
+}
+
+// Storage for responses, keyed on the endpoint and canonical query of the
+// request with the API key removed.
+//
+// Implementations must be safe for concurrent use. Expiry is handled by the
+// client, so a cache is free to return expired entries.
+type Cache interface {
+	Get(key string) (CacheEntry, bool)
+	Set(key string, entry CacheEntry)
+	Delete(key string)
+}
+
+// Cache successful responses in the given cache for `ttl`.
+//
+// The TTL of individual endpoints can be changed with `WithCacheTTL`.
+func WithCache(cache Cache, ttl time.Duration) ClientOption {
+	return func(c *Client) error {
+		if cache == nil {
+			return fmt.Errorf("cache cannot be nil")
+		}
+		c.cache = cache
+		c.cache_ttl = ttl
+		return nil
+	}
+}
+
+// Override the cache TTL for a single endpoint, named by its path below the base
+// URL (e.g. "category", "series/observations").
+//
+// A TTL of zero disables caching for the endpoint.
+func WithCacheTTL(endpoint string, ttl time.Duration) ClientOption {
+	return func(c *Client) error {
+		ttls := make(map[string]time.Duration, len(c.cache_ttls)+1)
+		for k, v := range c.cache_ttls {
+			ttls[k] = v
+		}
+		ttls[strings.Trim(endpoint, "/")] = ttl
+		c.cache_ttls = ttls
+		return nil
+	}
+}
+
+// Key under which the time a series was last updated is kept in the cache.
+func series_update_key(id string) string {
+	return "gofred:series_updated:" + id
+}
+
+// Compute the cache key for the request URL, and the TTL of its endpoint.
+//
+// The key is the endpoint with the canonical query, less the API key.
+func (c Client) cache_key(req_url string) (string, time.Duration) {
+	u, err := url.Parse(req_url)
+	if err != nil {
+		return "", 0
+	}
+
+	endpoint := c.endpoint(u)
+	ttl := c.cache_ttl
+	if endpoint_ttl, exists := c.cache_ttls[endpoint]; exists {
+		ttl = endpoint_ttl
+	}
+
+	query := u.Query()
+	query.Del("api_key")
+	return endpoint + "?" + query.Encode(), ttl // sorted by key
+}
+
+// Find a fresh cached response for the key.
+//
+// Entries for a series which were stored before the series was last updated
+// (see `InvalidateSeries`) are treated as stale.
+func (c Client) cache_lookup(key string) ([]byte, bool) {
+	entry, hit := c.cache.Get(key)
+	if !hit {
+		return nil, false
+	}
+
+	if entry.Expired(time.Now()) {
+		c.cache.Delete(key)
+		return nil, false
+	}
+
+	if u, err := url.Parse(key); err == nil {
+		if id := u.Query().Get("series_id"); len(id) > 0 {
+			if marker, exists := c.cache.Get(series_update_key(id)); exists {
+				updated, err := time.Parse(time.RFC3339Nano, string(marker.Body))
+				if err == nil && updated.After(entry.Stored) {
+					c.cache.Delete(key)
+					return nil, false
+				}
+			}
+		}
+	}
+
+	return entry.Body, true
+}
+
+// Record when the series was last updated, so cached responses for it which
+// predate the update are no longer used.
+//
+// Called automatically by `Series`; call it with series obtained elsewhere
+// (searches, category listings) to get the same behavior.
+func (c Client) InvalidateSeries(series Series) {
+	updated := time.Time(series.LastUpdate)
+	if c.cache == nil || updated.IsZero() {
+		return
+	}
+
+	key := series_update_key(series.Id)
+	if marker, exists := c.cache.Get(key); exists {
+		known, err := time.Parse(time.RFC3339Nano, string(marker.Body))
+		if err == nil && !updated.After(known) {
+			return
+		}
+	}
+
+	c.cache.Set(key, CacheEntry{
+		Body:   []byte(updated.Format(time.RFC3339Nano)),
+		Stored: time.Now(),
+	})
+}
+
+//==============================================================================
+// in-memory LRU
+//==============================================================================
+
+// In-memory cache evicting the least recently used entry once full.
+type MemoryCache struct {
+	mu       sync.Mutex
+	capacity int
+	order    *list.List // front is most recently used
+	entries  map[string]*list.Element
+}
+
+type memoryCacheItem struct {
+	key   string
+	entry CacheEntry
+}
+
+// Create an in-memory cache holding at most `capacity` entries.
+func NewMemoryCache(capacity int) *MemoryCache {
+	if capacity < 1 {
+		capacity = 1
+	}
+
+	return &MemoryCache{
+		capacity: capacity,
+		order:    list.New(),
+		entries:  make(map[string]*list.Element),
+	}
+}
+
+// Satisfies the `Cache` interface.
+func (m *MemoryCache) Get(key string) (CacheEntry, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	elem, exists := m.entries[key]
+	if !exists {
+		return CacheEntry{}, false
+	}
+
+	m.order.MoveToFront(elem)
+	return elem.Value.(*memoryCacheItem).entry, true
+}
+
+// Satisfies the `Cache` interface.
+func (m *MemoryCache) Set(key string, entry CacheEntry) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if elem, exists := m.entries[key]; exists {
+		elem.Value.(*memoryCacheItem).entry = entry
+		m.order.MoveToFront(elem)
+		return
+	}
+
+	m.entries[key] = m.order.PushFront(&memoryCacheItem{key: key, entry: entry})
+
+	for m.order.Len() > m.capacity {
+		oldest := m.order.Back()
+		m.order.Remove(oldest)
+		delete(m.entries, oldest.Value.(*memoryCacheItem).key)
+	}
+}
+
+// Satisfies the `Cache` interface.
+func (m *MemoryCache) Delete(key string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if elem, exists := m.entries[key]; exists {
+		m.order.Remove(elem)
+		delete(m.entries, key)
+	}
+}
+
+// Number of entries currently held.
+func (m *MemoryCache) Len() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.order.Len()
+}
+
+//==============================================================================
+// on-disk
+//==============================================================================
+
+// Cache storing every entry as a file in a directory.
+//
+// Entries persist across processes; nothing is evicted besides stale entries
+// the client deletes.
+type DiskCache struct {
+	dir string
+}
+
+// Create a cache in the given directory, creating it if needed.
+func NewDiskCache(dir string) (*DiskCache, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("could not create cache directory: %v", err)
+	}
+
+	return &DiskCache{dir: dir}, nil
+}
+
+// File holding the entry for the key.
+func (d *DiskCache) path(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(d.dir, hex.EncodeToString(sum[:]))
+}
+
+// Satisfies the `Cache` interface.
+func (d *DiskCache) Get(key string) (CacheEntry, bool) {
+	f, err := os.Open(d.path(key))
+	if err != nil {
+		return CacheEntry{}, false
+	}
+	defer f.Close()
+
+	var entry CacheEntry
+	if err := gob.NewDecoder(f).Decode(&entry); err != nil {
+		return CacheEntry{}, false
+	}
+	return entry, true
+}
+
+// Satisfies the `Cache` interface.
+//
+// Failures to write are ignored, the entry is simply not cached.
+func (d *DiskCache) Set(key string, entry CacheEntry) {
+	tmp, err := ioutil.TempFile(d.dir, "tmp-")
+	if err != nil {
+		return
+	}
+
+	err = gob.NewEncoder(tmp).Encode(entry)
+	if close_err := tmp.Close(); err == nil {
+		err = close_err
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+		return
+	}
+
+	// rename is atomic, so concurrent readers never see a partial entry
+	if err := os.Rename(tmp.Name(), d.path(key)); err != nil {
+		os.Remove(tmp.Name())
+	}
+}
+
+// Satisfies the `Cache` interface.
+func (d *DiskCache) Delete(key string) {
+	os.Remove(d.path(key))
+}
@@ -0,0 +1,173 @@
+package gofred
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMemoryCache_Evicts(t *testing.T) {
+	cache := NewMemoryCache(2)
+	cache.Set("a", CacheEntry{Body: []byte("a")})
+	cache.Set("b", CacheEntry{Body: []byte("b")})
+	cache.Get("a") // b is now least recently used
+	cache.Set("c", CacheEntry{Body: []byte("c")})
+
+	if cache.Len() != 2 {
+		t.Errorf("expected %d entries, got %d", 2, cache.Len())
+	}
+	if _, hit := cache.Get("b"); hit {
+		t.Errorf("expected least recently used entry to be evicted")
+	}
+	for _, key := range []string{"a", "c"} {
+		if entry, hit := cache.Get(key); !hit || string(entry.Body) != key {
+			t.Errorf("expected entry %s to be cached, got: %+v", key, entry)
+		}
+	}
+
+	cache.Delete("a")
+	if _, hit := cache.Get("a"); hit {
+		t.Errorf("expected deleted entry to be gone")
+	}
+}
+
+func TestDiskCache_RoundTrip(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gofred-cache")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	cache, err := NewDiskCache(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	stored := time.Now().Round(0)
+	cache.Set("key", CacheEntry{Body: []byte("body"), Stored: stored})
+
+	// a second cache over the same directory sees the entry
+	reopened, err := NewDiskCache(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	entry, hit := reopened.Get("key")
+	if !hit {
+		t.Fatalf("expected entry to be persisted")
+	}
+	if string(entry.Body) != "body" || !entry.Stored.Equal(stored) {
+		t.Errorf("unexpected entry: %+v", entry)
+	}
+
+	reopened.Delete("key")
+	if _, hit := cache.Get("key"); hit {
+		t.Errorf("expected deleted entry to be gone")
+	}
+}
+
+// Serves `series` and `series/observations` for a single series, counting requests.
+type cacheTestServer struct {
+	*httptest.Server
+	hits    map[string]int
+	updated string
+}
+
+func new_cache_test_server() *cacheTestServer {
+	s := &cacheTestServer{hits: map[string]int{}, updated: "2017-01-01 08:00:00-05"}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		endpoint := strings.TrimPrefix(r.URL.Path, "/")
+		s.hits[endpoint]++
+
+		switch endpoint {
+		case "series":
+			fmt.Fprintf(w, `{"seriess":[{"id":"GNPCA","title":"Real Gross National Product",`+
+				`"frequency":"Annual","seasonal_adjustment":"Not Seasonally Adjusted",`+
+				`"realtime_start":"2017-01-01","realtime_end":"2017-01-01",`+
+				`"observation_start":"1929-01-01","observation_end":"2016-01-01",`+
+				`"last_updated":"%s"}]}`, s.updated)
+		case "series/observations":
+			w.Write([]byte(`{"units":"lin","observations":[{"date":"2016-01-01","value":"1.5"}]}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	return s
+}
+
+func TestCache_Client(t *testing.T) {
+	server := new_cache_test_server()
+	defer server.Close()
+
+	client, err := NewClient(API_KEY, JSON,
+		WithBaseURL(server.URL),
+		WithCache(NewMemoryCache(16), time.Hour),
+		WithCacheTTL("series", 0),
+	)
+	if err != nil {
+		t.Fatalf("could not create client: %v", err)
+	}
+
+	req := NewSeriesObservationsRequest("GNPCA", time.Time{}, time.Time{})
+	for i := 0; i < 3; i++ {
+		res, err := client.SeriesObservations(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(res.Observations) != 1 {
+			t.Fatalf("expected a single observation, got: %+v", res.Observations)
+		}
+	}
+	if hits := server.hits["series/observations"]; hits != 1 {
+		t.Errorf("expected observations to be fetched once, got %d", hits)
+	}
+
+	// series has caching disabled
+	for i := 0; i < 2; i++ {
+		if _, err := client.Series(NewSeriesRequest("GNPCA")); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if hits := server.hits["series"]; hits != 2 {
+		t.Errorf("expected series to be fetched every time, got %d", hits)
+	}
+
+	// the series was updated after the observations were cached
+	server.updated = time.Now().Add(time.Minute).Format(TIME_FORMAT)
+	if _, err := client.Series(NewSeriesRequest("GNPCA")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.SeriesObservations(req); err != nil {
+		t.Fatal(err)
+	}
+	if hits := server.hits["series/observations"]; hits != 2 {
+		t.Errorf("expected stale observations to be refetched, got %d fetches", hits)
+	}
+}
+
+func TestCache_KeyOmitsApiKey(t *testing.T) {
+	client, err := NewClient(API_KEY, JSON, WithCache(NewMemoryCache(1), time.Hour))
+	if err != nil {
+		t.Fatalf("could not create client: %v", err)
+	}
+
+	key, ttl := client.cache_key(API_URL + "/series?series_id=GNPCA&api_key=" + API_KEY + "&file_type=json")
+	if strings.Contains(key, API_KEY) {
+		t.Errorf("cache key contains the api key: %s", key)
+	}
+	if key != "series?file_type=json&series_id=GNPCA" {
+		t.Errorf("unexpected cache key: %s", key)
+	}
+	if ttl != time.Hour {
+		t.Errorf("expected default ttl, got: %v", ttl)
+	}
+
+	other, _ := client.cache_key(API_URL + "/series?file_type=json&api_key=zyxwvutsrqponmlkjihgfedcba012345&series_id=GNPCA")
+	if other != key {
+		t.Errorf("expected the same key whatever the api key and parameter order, got: %s and %s", key, other)
+	}
+}
@@ -1,6 +1,7 @@
 package gofred
 
 import (
+	"context"
 	"encoding/xml"
 	"fmt"
 	"net/url"
@@ -32,6 +33,11 @@ func (r categoryRequest) ToParams() url.Values {
 	return v
 }
 
+// Satisfies the `Request` interface.
+func (r categoryRequest) Validate() error {
+	return nil
+}
+
 // Response type which _should_ contain only one category.
 type categoryResponse struct {
 	Categories []Category `json:"categories" xml:"category"`
@@ -44,25 +50,32 @@ type categoryResponse struct {
 // Asserts there is only one `Category` object in the result, and returns it.
 //
 func (c Client) Category(category uint) (Category, Error) {
+	return c.CategoryContext(context.Background(), category)
+}
+
+// Same as `Category`, but the request is bound to the given context.
+func (c Client) CategoryContext(ctx context.Context, category uint) (Category, Error) {
 	cat_req := categoryRequest{
 		baseRequest: c.base_req,
 		category:    category,
 	}
-
 	req_url := c.base_url
 	req_url.RawQuery = cat_req.ToParams().Encode()
 	req_url.Path = fmt.Sprintf("%s/category", req_url.Path)
+	if err := cat_req.Validate(); err != nil {
+		return Category{}, c.invalid_request(req_url.String(), "category", err)
+	}
 
-	body, err := c.get("category", req_url.String())
+	body, err := c.get(ctx, "category", req_url.String())
 	if err != nil {
-		return Category{}, err.Prefixf("error getting category %d: %v", category, err)
+		return Category{}, err.Prefixf("error getting category %d:", category)
 	}
 
 	// parse the correct format
 	var result categoryResponse
-	parse_err := c.unmarshal_body(body, &result)
-	if parse_err != nil {
-		return Category{}, err.Prefixf("could not get category %d: %v", category, err)
+	err = c.unmarshal_body(req_url.String(), body, &result)
+	if err != nil {
+		return Category{}, err.Prefixf("could not get category %d:", category)
 	}
 
 	// pull out the singular category
@@ -103,6 +116,11 @@ func (r categoryChildrenRequest) ToParams() url.Values {
 	return v
 }
 
+// Satisfies the `Request` interface.
+func (r categoryChildrenRequest) Validate() error {
+	return r.DatedRequest.Validate()
+}
+
 // Internal type for parsing the response.
 //
 // For getter function, only the array is returned.
@@ -115,6 +133,11 @@ type categoryChildrenResponse struct {
 // Get the `Category` information for the children of the given category.
 //
 func (c Client) CategoryChildren(category uint, start, end time.Time) ([]Category, Error) {
+	return c.CategoryChildrenContext(context.Background(), category, start, end)
+}
+
+// Same as `CategoryChildren`, but the request is bound to the given context.
+func (c Client) CategoryChildrenContext(ctx context.Context, category uint, start, end time.Time) ([]Category, Error) {
 	cat_req := categoryChildrenRequest{
 		baseRequest: c.base_req,
 		DatedRequest: DatedRequest{
@@ -123,18 +146,20 @@ func (c Client) CategoryChildren(category uint, start, end time.Time) ([]Categor
 		},
 		category: category,
 	}
-
 	req_url := c.base_url
 	req_url.RawQuery = cat_req.ToParams().Encode()
 	req_url.Path = fmt.Sprintf("%s/category/children", req_url.Path)
+	if err := cat_req.Validate(); err != nil {
+		return nil, c.invalid_request(req_url.String(), "category children", err)
+	}
 
-	body, err := c.get("category children", req_url.String())
+	body, err := c.get(ctx, "category children", req_url.String())
 	if err != nil {
 		return nil, err.Prefixf("error getting category children %d", category)
 	}
 
 	var result categoryChildrenResponse
-	err = c.unmarshal_body(body, &result)
+	err = c.unmarshal_body(req_url.String(), body, &result)
 	return result.Categories, err
 }
 
@@ -160,6 +185,11 @@ func (r categoryRelatedRequest) ToParams() url.Values {
 	return v
 }
 
+// Satisfies the `Request` interface.
+func (r categoryRelatedRequest) Validate() error {
+	return r.DatedRequest.Validate()
+}
+
 // Internal type for parsing the response.
 //
 // For getter function, only the array is returned.
@@ -172,6 +202,11 @@ type categoryRelatedResponse struct {
 // Get the `Category` information for the categories related to the given category.
 //
 func (c Client) RelatedCategories(category uint, start, end time.Time) ([]Category, Error) {
+	return c.RelatedCategoriesContext(context.Background(), category, start, end)
+}
+
+// Same as `RelatedCategories`, but the request is bound to the given context.
+func (c Client) RelatedCategoriesContext(ctx context.Context, category uint, start, end time.Time) ([]Category, Error) {
 	cat_req := categoryRelatedRequest{
 		baseRequest: c.base_req,
 		DatedRequest: DatedRequest{
@@ -180,18 +215,20 @@ func (c Client) RelatedCategories(category uint, start, end time.Time) ([]Catego
 		},
 		category: category,
 	}
-
 	req_url := c.base_url
 	req_url.RawQuery = cat_req.ToParams().Encode()
 	req_url.Path = fmt.Sprintf("%s/category/related", req_url.Path)
+	if err := cat_req.Validate(); err != nil {
+		return nil, c.invalid_request(req_url.String(), "related categories", err)
+	}
 
-	body, err := c.get("related categories", req_url.String())
+	body, err := c.get(ctx, "related categories", req_url.String())
 	if err != nil {
 		return nil, err.Prefixf("error getting categories related to %d", category)
 	}
 
 	var result categoryRelatedResponse
-	err = c.unmarshal_body(body, &result)
+	err = c.unmarshal_body(req_url.String(), body, &result)
 	return result.Categories, err
 }
 
@@ -233,6 +270,17 @@ func (r CategorySeriesRequest) ToParams() url.Values {
 	return v
 }
 
+// Satisfies the `Request` interface.
+func (r CategorySeriesRequest) Validate() error {
+	return validate_all(
+		r.DatedRequest.Validate(),
+		r.PagedRequest.Validate(),
+		r.OrderedRequest.Validate(),
+		r.FilteredRequest.Validate(),
+		r.TaggedRequest.Validate(),
+	)
+}
+
 type CategorySeriesResponse struct {
 	Start   Date      `json:"realtime_start" xml:"realtime_start,attr"`
 	End     Date      `json:"realtime_end" xml:"realtime_end,attr"`
@@ -249,22 +297,46 @@ type CategorySeriesResponse struct {
 // Get the `Category` information for the categories related to the given category.
 //
 func (c Client) SeriesInCategory(req CategorySeriesRequest) (CategorySeriesResponse, Error) {
-	req.baseRequest = c.base_req
+	return c.SeriesInCategoryContext(context.Background(), req)
+}
 
+// Same as `SeriesInCategory`, but the request is bound to the given context.
+func (c Client) SeriesInCategoryContext(ctx context.Context, req CategorySeriesRequest) (CategorySeriesResponse, Error) {
+	req.baseRequest = c.base_req
 	req_url := c.base_url
 	req_url.RawQuery = req.ToParams().Encode()
 	req_url.Path = fmt.Sprintf("%s/category/series", req_url.Path)
+	if err := req.Validate(); err != nil {
+		return CategorySeriesResponse{}, c.invalid_request(req_url.String(), "series in category", err)
+	}
 
-	body, err := c.get("series in category", req_url.String())
+	body, err := c.get(ctx, "series in category", req_url.String())
 	if err != nil {
 		return CategorySeriesResponse{}, err.Prefixf("error getting categories related to %d", req.Category)
 	}
 
 	var result CategorySeriesResponse
-	err = c.unmarshal_body(body, &result)
+	err = c.unmarshal_body(req_url.String(), body, &result)
 	return result, err
 }
 
+// Iterate over every `Series` in the category, fetching pages of `req.Limit` items
+// starting from `req.Offset` until every page has been read, an error occurs or
+// `ctx` is done.
+func (c Client) SeriesInCategoryAll(ctx context.Context, req CategorySeriesRequest) *SeriesIterator {
+	it := &SeriesIterator{}
+	it.pager = new_pager(ctx, req.Offset, func(ctx context.Context, offset uint) (uint, int, Error) {
+		req.Offset = offset
+		res, err := c.SeriesInCategoryContext(ctx, req)
+		if err != nil {
+			return 0, 0, err
+		}
+		it.page = res.Series
+		return res.Count, len(res.Series), nil
+	})
+	return it
+}
+
 //==============================================================================
 //
 // GET: /fred/category/tags
@@ -310,6 +382,16 @@ func (r CategoryTagsRequest) ToParams() url.Values {
 	return v
 }
 
+// Satisfies the `Request` interface.
+func (r CategoryTagsRequest) Validate() error {
+	return validate_all(
+		r.DatedRequest.Validate(),
+		r.PagedRequest.Validate(),
+		r.OrderedRequest.Validate(),
+		r.TagGroupId.validate(),
+	)
+}
+
 type CategoryTagsResponse struct {
 	Start   Date      `json:"realtime_start" xml:"realtime_start,attr"`
 	End     Date      `json:"realtime_end" xml:"realtime_end,attr"`
@@ -326,22 +408,46 @@ type CategoryTagsResponse struct {
 // Get the `Category` information for the categories related to the given category.
 //
 func (c Client) CategoryTags(req CategoryTagsRequest) (CategoryTagsResponse, Error) {
-	req.baseRequest = c.base_req
+	return c.CategoryTagsContext(context.Background(), req)
+}
 
+// Same as `CategoryTags`, but the request is bound to the given context.
+func (c Client) CategoryTagsContext(ctx context.Context, req CategoryTagsRequest) (CategoryTagsResponse, Error) {
+	req.baseRequest = c.base_req
 	req_url := c.base_url
 	req_url.RawQuery = req.ToParams().Encode()
 	req_url.Path = fmt.Sprintf("%s/category/tags", req_url.Path)
+	if err := req.Validate(); err != nil {
+		return CategoryTagsResponse{}, c.invalid_request(req_url.String(), "category tags", err)
+	}
 
-	body, err := c.get("series in category", req_url.String())
+	body, err := c.get(ctx, "series in category", req_url.String())
 	if err != nil {
 		return CategoryTagsResponse{}, err.Prefixf("error getting category tags")
 	}
 
 	var result CategoryTagsResponse
-	err = c.unmarshal_body(body, &result)
+	err = c.unmarshal_body(req_url.String(), body, &result)
 	return result, err
 }
 
+// Iterate over every `Tag` for the category, fetching pages of `req.Limit` items
+// starting from `req.Offset` until every page has been read, an error occurs or
+// `ctx` is done.
+func (c Client) CategoryTagsAll(ctx context.Context, req CategoryTagsRequest) *TagIterator {
+	it := &TagIterator{}
+	it.pager = new_pager(ctx, req.Offset, func(ctx context.Context, offset uint) (uint, int, Error) {
+		req.Offset = offset
+		res, err := c.CategoryTagsContext(ctx, req)
+		if err != nil {
+			return 0, 0, err
+		}
+		it.page = res.Tags
+		return res.Count, len(res.Tags), nil
+	})
+	return it
+}
+
 //==============================================================================
 //
 // GET: /fred/category/related_tags
@@ -390,6 +496,17 @@ func (r CategoryRelatedTagsRequest) ToParams() url.Values {
 	return v
 }
 
+// Satisfies the `Request` interface.
+func (r CategoryRelatedTagsRequest) Validate() error {
+	return validate_all(
+		r.DatedRequest.Validate(),
+		r.TaggedRequest.Validate(),
+		r.PagedRequest.Validate(),
+		r.OrderedRequest.Validate(),
+		r.TagGroupId.validate(),
+	)
+}
+
 type CategoryRelatedTagsResponse struct {
 	Start   Date      `json:"realtime_start" xml:"realtime_start,attr"`
 	End     Date      `json:"realtime_end" xml:"realtime_end,attr"`
@@ -406,18 +523,42 @@ type CategoryRelatedTagsResponse struct {
 // Get the `Category` information for the categories related to the given category.
 //
 func (c Client) CategoryRelatedTags(req CategoryRelatedTagsRequest) (CategoryRelatedTagsResponse, Error) {
-	req.baseRequest = c.base_req
+	return c.CategoryRelatedTagsContext(context.Background(), req)
+}
 
+// Same as `CategoryRelatedTags`, but the request is bound to the given context.
+func (c Client) CategoryRelatedTagsContext(ctx context.Context, req CategoryRelatedTagsRequest) (CategoryRelatedTagsResponse, Error) {
+	req.baseRequest = c.base_req
 	req_url := c.base_url
 	req_url.RawQuery = req.ToParams().Encode()
 	req_url.Path = fmt.Sprintf("%s/category/related_tags", req_url.Path)
+	if err := req.Validate(); err != nil {
+		return CategoryRelatedTagsResponse{}, c.invalid_request(req_url.String(), "category related tags", err)
+	}
 
-	body, err := c.get("series in category", req_url.String())
+	body, err := c.get(ctx, "series in category", req_url.String())
 	if err != nil {
 		return CategoryRelatedTagsResponse{}, err.Prefixf("error getting category tags")
 	}
 
 	var result CategoryRelatedTagsResponse
-	err = c.unmarshal_body(body, &result)
+	err = c.unmarshal_body(req_url.String(), body, &result)
 	return result, err
 }
+
+// Iterate over every `Tag` related to the category, fetching pages of `req.Limit` items
+// starting from `req.Offset` until every page has been read, an error occurs or
+// `ctx` is done.
+func (c Client) CategoryRelatedTagsAll(ctx context.Context, req CategoryRelatedTagsRequest) *TagIterator {
+	it := &TagIterator{}
+	it.pager = new_pager(ctx, req.Offset, func(ctx context.Context, offset uint) (uint, int, Error) {
+		req.Offset = offset
+		res, err := c.CategoryRelatedTagsContext(ctx, req)
+		if err != nil {
+			return 0, 0, err
+		}
+		it.page = res.Tags
+		return res.Count, len(res.Tags), nil
+	})
+	return it
+}
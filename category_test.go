@@ -1,6 +1,7 @@
 package gofred
 
 import (
+	"strings"
 	"testing"
 	"time"
 )
@@ -23,7 +24,7 @@ func TestCategory_TradeBalance(t *testing.T) {
 		ParentId: 13,
 	}
 
-	mux_test(t, func(client Client) {
+	fake_test(t, func(client Client) {
 		res, err := client.Category(cat.Id)
 		if err != nil {
 			t.Fatal(err)
@@ -36,13 +37,13 @@ func TestCategory_TradeBalance(t *testing.T) {
 }
 
 func TestCategory_Nonexistant(t *testing.T) {
-	mux_test(t, func(client Client) {
+	fake_test(t, func(client Client) {
 		cat, err := client.Category(999999) // as of writing, this returns a 400 response
 		if err == nil {
 			t.Fatalf("expected an error response, got: %+v", cat)
 		}
 		if err.Type() != Invalid {
-			t.Errorf("expected type:", Invalid, ", got:", err.Type())
+			t.Errorf("expected type: %v, got: %v", Invalid, err.Type())
 		}
 	})
 }
@@ -54,7 +55,7 @@ func TestCategory_Nonexistant(t *testing.T) {
 //==============================================================================
 
 func TestCategoryChildren_TradeBalance(t *testing.T) {
-	mux_test(t, func(client Client) {
+	fake_test(t, func(client Client) {
 		trade, err := client.Category(CATEGORY_TRADE_BALANCE)
 		if err != nil {
 			t.Fatal(err)
@@ -89,7 +90,7 @@ func TestCategoryChildren_TradeBalance(t *testing.T) {
 //==============================================================================
 
 func TestCategoryRelated_Districts(t *testing.T) {
-	mux_test(t, func(client Client) {
+	fake_test(t, func(client Client) {
 		related, err := client.RelatedCategories(CATEGORY_STLOUIS_DISTRICT_STATES, time.Unix(0, 0), time.Now().Add(-time.Hour*24))
 		if err != nil {
 			t.Fatal(err)
@@ -148,7 +149,7 @@ func TestCategorySeries_TradeBalance(t *testing.T) {
 	req.Sort = SortAscending
 	req.Limit = uint(limit)
 
-	mux_test(t, func(client Client) {
+	fake_test(t, func(client Client) {
 		series, err := client.SeriesInCategory(req)
 		if err != nil {
 			t.Fatal(err)
@@ -169,7 +170,7 @@ func TestCategorySeries_TradeBalance(t *testing.T) {
 		last := ""
 		for _, s := range series.Series {
 			if len(last) > 0 && s.Title < last {
-				t.Errorf("expected sorted by title, got '%s' after '%s'", s, last)
+				t.Errorf("expected sorted by title, got '%s' after '%s'", s.Title, last)
 			}
 			last = s.Title
 		}
@@ -194,7 +195,7 @@ func TestCategoryTags_TradeBalance(t *testing.T) {
 	req.Sort = SortAscending
 	req.Limit = uint(limit)
 
-	mux_test(t, func(client Client) {
+	fake_test(t, func(client Client) {
 		res, err := client.CategoryTags(req)
 		if err != nil {
 			t.Fatal(err)
@@ -240,7 +241,7 @@ func TestCategoryRelatedTags_TradeBalance(t *testing.T) {
 	req.Sort = SortAscending
 	req.Limit = uint(limit)
 
-	mux_test(t, func(client Client) {
+	fake_test(t, func(client Client) {
 		res, err := client.CategoryRelatedTags(req)
 		if err != nil {
 			t.Fatal(err)
@@ -267,3 +268,16 @@ func TestCategoryRelatedTags_TradeBalance(t *testing.T) {
 		}
 	})
 }
+
+func TestCategoryTags_InvalidRequest(t *testing.T) {
+	client := make_client(t, JSON)
+	_, err := client.CategoryTags(NewCategoryTagsRequest(CATEGORY_TRADE_BALANCE, TagId("bogus"), ""))
+	if err == nil || !strings.Contains(err.Error(), "invalid category tags request") {
+		t.Errorf("expected an invalid category tags request, got: %v", err)
+	}
+
+	_, err = client.CategoryRelatedTags(NewCategoryRelatedTagsRequest(CATEGORY_TRADE_BALANCE, "", ""))
+	if err == nil || !strings.Contains(err.Error(), "invalid category related tags request") {
+		t.Errorf("expected an invalid category related tags request, got: %v", err)
+	}
+}
@@ -0,0 +1,410 @@
+package gofred
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+//==============================================================================
+// file downloads
+//==============================================================================
+
+// Excel stores dates as days since this date.
+const EXCEL_EPOCH = "1899-12-30"
+
+// Signatures of zip archives, which xlsx workbooks also are, and of the
+// compound files xls workbooks are stored in.
+var (
+	zip_signature = []byte("PK\x03\x04")
+	cfb_signature = []byte{0xD0, 0xCF, 0x11, 0xE0, 0xA1, 0xB1, 0x1A, 0xE1}
+)
+
+// Cells of a spreadsheet or text file, by row then column.
+type sheet [][]string
+
+// Sets the cell, growing the sheet as needed.
+func (s *sheet) set(row, col int, value string) {
+	for len(*s) <= row {
+		*s = append(*s, nil)
+	}
+	for len((*s)[row]) <= col {
+		(*s)[row] = append((*s)[row], "")
+	}
+	(*s)[row][col] = value
+}
+
+// Whether the format is one FRED only sends observations as.
+func (f ResponseFormat) is_file() bool {
+	return f == XLSX || f == XLS || f == TXT
+}
+
+// Reads every sheet of a downloaded file.
+//
+// The format is sniffed rather than taken from the request: FRED zips some
+// downloads, and an xlsx workbook is itself a zip archive.
+func download_sheets(body []byte) ([]sheet, error) {
+	switch {
+	case bytes.HasPrefix(body, zip_signature):
+		archive, err := zip.NewReader(bytes.NewReader(body), int64(len(body)))
+		if err != nil {
+			return nil, err
+		}
+		for _, file := range archive.File {
+			if file.Name == "xl/workbook.xml" {
+				return parse_xlsx(archive)
+			}
+		}
+		return parse_zip(archive)
+	case bytes.HasPrefix(body, cfb_signature):
+		return parse_xls(body)
+	}
+	return []sheet{parse_txt(body)}, nil
+}
+
+// Reads every file of the archive, in name order.
+func parse_zip(archive *zip.Reader) ([]sheet, error) {
+	files := append([]*zip.File{}, archive.File...)
+	sort.Slice(files, func(i, j int) bool { return files[i].Name < files[j].Name })
+
+	sheets := []sheet{}
+	for _, file := range files {
+		if file.FileInfo().IsDir() {
+			continue
+		}
+
+		r, err := file.Open()
+		if err != nil {
+			return nil, fmt.Errorf("could not open %s: %v", file.Name, err)
+		}
+		body, err := ioutil.ReadAll(r)
+		r.Close()
+		if err != nil {
+			return nil, fmt.Errorf("could not read %s: %v", file.Name, err)
+		}
+
+		file_sheets, err := download_sheets(body)
+		if err != nil {
+			return nil, fmt.Errorf("could not read %s: %v", file.Name, err)
+		}
+		sheets = append(sheets, file_sheets...)
+	}
+	return sheets, nil
+}
+
+// Splits text into cells on tabs or commas, or on whitespace if there are neither.
+func parse_txt(body []byte) sheet {
+	var result sheet
+	for _, line := range strings.Split(string(body), "\n") {
+		line = strings.TrimRight(line, "\r")
+
+		var cells []string
+		switch {
+		case strings.Contains(line, "\t"):
+			cells = strings.Split(line, "\t")
+		case strings.Contains(line, ","):
+			cells = strings.Split(line, ",")
+		default:
+			cells = strings.Fields(line)
+		}
+		for i := range cells {
+			cells[i] = strings.TrimSpace(cells[i])
+		}
+		result = append(result, cells)
+	}
+	return result
+}
+
+//==============================================================================
+// columns
+//==============================================================================
+
+var column_name = regexp.MustCompile(`^[A-Za-z0-9_]+$`)
+
+// Name of the observation field the column holds, "" for the value column
+// named after the series.
+func download_column(name string) string {
+	switch strings.ToLower(name) {
+	case "date", "observation_date":
+		return "date"
+	case "realtime_start", "realtime_start_date":
+		return "realtime_start"
+	case "realtime_end", "realtime_end_date":
+		return "realtime_end"
+	case "value":
+		return "value"
+	}
+
+	// vintage columns, e.g. `GNPCA_20090731`
+	if split := strings.LastIndex(name, "_"); split >= 0 {
+		if _, err := time.Parse(VINTAGE_COLUMN_FORMAT, name[split+1:]); err == nil {
+			return name
+		}
+	}
+	return ""
+}
+
+// Turns a date cell into `DATE_FORMAT`.
+//
+// Spreadsheets may hold dates as days since `EXCEL_EPOCH`, possibly with a
+// time of day, or as text with or without a time.
+func download_date(cell string) string {
+	if len(cell) >= len(DATE_FORMAT) {
+		if _, err := time.Parse(DATE_FORMAT, cell[:len(DATE_FORMAT)]); err == nil {
+			return cell[:len(DATE_FORMAT)]
+		}
+	}
+	if serial, err := strconv.ParseFloat(cell, 64); err == nil {
+		epoch, _ := time.Parse(DATE_FORMAT, EXCEL_EPOCH)
+		return epoch.AddDate(0, 0, int(serial)).Format(DATE_FORMAT)
+	}
+	if as_time, err := time.Parse("1/2/2006", cell); err == nil {
+		return as_time.Format(DATE_FORMAT)
+	}
+	return cell
+}
+
+// Turns the spellings of a missing value into FRED's ".".
+func download_value(cell string) string {
+	switch strings.ToUpper(cell) {
+	case "", ".", "#N/A", "NA", "N/A", "ND":
+		return "."
+	}
+	return cell
+}
+
+// The observation field of every column of the header row, or false if the
+// row is not the header of an observations table.
+func header_fields(row []string) ([]string, bool) {
+	fields := make([]string, len(row))
+	has_date := false
+	unnamed := -1
+	for i, cell := range row {
+		if cell == "" {
+			continue
+		}
+		if !column_name.MatchString(cell) {
+			return nil, false
+		}
+
+		fields[i] = download_column(cell)
+		switch {
+		case fields[i] == "date":
+			has_date = true
+		case fields[i] != "":
+		case unnamed >= 0:
+			return nil, false // more than one series column
+		default:
+			unnamed = i
+		}
+	}
+
+	if unnamed >= 0 {
+		fields[unnamed] = "value"
+	}
+	return fields, has_date
+}
+
+// Finds the observations table of the sheet and maps each of its dated rows by
+// field, skipping blank rows and any notes.
+//
+// The header is the first row naming a date column and followed by dated rows,
+// which skips the readme and the notes FRED heads downloads with. Returns false
+// if there is no such table.
+func (s sheet) observations() ([]map[string]string, bool) {
+	for i, row := range s {
+		fields, is_header := header_fields(row)
+		if !is_header {
+			continue
+		}
+
+		rows := []map[string]string{}
+		for _, cells := range s[i+1:] {
+			values := row_fields(fields, cells)
+			if _, err := time.Parse(DATE_FORMAT, values["date"]); err == nil {
+				rows = append(rows, values)
+			}
+		}
+		if len(rows) > 0 {
+			return rows, true
+		}
+	}
+	return nil, false
+}
+
+func row_fields(fields []string, cells []string) map[string]string {
+	result := make(map[string]string, len(fields))
+	for i, field := range fields {
+		if field == "" {
+			continue
+		}
+
+		cell := ""
+		if i < len(cells) {
+			cell = strings.TrimSpace(cells[i])
+		}
+		switch field {
+		case "date", "realtime_start", "realtime_end":
+			if cell != "" {
+				result[field] = download_date(cell)
+			}
+		default:
+			result[field] = download_value(cell)
+		}
+	}
+	return result
+}
+
+// Reads the observation rows of a download.
+//
+// Vintages may be split across files or sheets, a column per vintage, so rows
+// of the same date and realtime period are merged.
+func download_rows(body []byte) ([]vintageRow, error) {
+	sheets, err := download_sheets(body)
+	if err != nil {
+		return nil, err
+	}
+
+	merged := []map[string]string{}
+	index := map[string]int{}
+	found := false
+	for _, s := range sheets {
+		rows, ok := s.observations()
+		if !ok {
+			continue
+		}
+
+		found = true
+		for _, row := range rows {
+			key := row["date"] + " " + row["realtime_start"] + " " + row["realtime_end"]
+			i, exists := index[key]
+			if !exists {
+				index[key] = len(merged)
+				merged = append(merged, row)
+				continue
+			}
+			for field, value := range row {
+				merged[i][field] = value
+			}
+		}
+	}
+	if !found {
+		return nil, fmt.Errorf("no observations found")
+	}
+
+	result := make([]vintageRow, len(merged))
+	for i, row := range merged {
+		if err := result[i].parse(row); err != nil {
+			return nil, err
+		}
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		return time.Time(result[i].date).Before(time.Time(result[j].date))
+	})
+	return result, nil
+}
+
+//==============================================================================
+//
+// GET: /fred/series/observations (files)
+//
+//==============================================================================
+
+// Requests the observations as a file of the given format and reads its rows,
+// returning them along with the URL requested.
+func (c Client) download(ctx context.Context, desc string, req SeriesObservationsRequest, format ResponseFormat) ([]vintageRow, string, Error) {
+	req.baseRequest = c.base_req
+	req.fmt = format
+	req_url := c.observations_url(req)
+	if !format.is_file() {
+		return nil, "", c.invalid_request(req_url.String(), desc, fmt.Errorf("%v is not a file format", format))
+	}
+	if err := req.Validate(); err != nil {
+		return nil, "", c.invalid_request(req_url.String(), desc, err)
+	}
+
+	// error responses are decoded whatever their format, see `get_error`
+	body, err := c.get(ctx, desc, req_url.String())
+	if err != nil {
+		return nil, "", err.Prefixf("error downloading series %s:", req.Series)
+	}
+
+	rows, parse_err := download_rows(body)
+	if parse_err != nil {
+		return nil, "", c.request_error(req_url.String(), &APIError{
+			ty:  ParseError,
+			msg: fmt.Sprintf("could not read %s download of series %s: %v", format, req.Series, parse_err),
+			err: parse_err,
+		})
+	}
+	return rows, req_url.String(), nil
+}
+
+// Download every observation of the series in one request, as a file of the
+// given format (`XLSX`, `XLS` or `TXT`), rather than by pages of JSON or XML.
+//
+// Files carry none of the response fields, only the observations.
+func (c Client) SeriesObservationsFile(req SeriesObservationsRequest, format ResponseFormat) ([]DataPoint, Error) {
+	return c.SeriesObservationsFileContext(context.Background(), req, format)
+}
+
+// Same as `SeriesObservationsFile`, but the request is bound to the given context.
+func (c Client) SeriesObservationsFileContext(ctx context.Context, req SeriesObservationsRequest, format ResponseFormat) ([]DataPoint, Error) {
+	rows, req_url, err := c.download(ctx, "series observations file", req, format)
+	if err != nil {
+		return nil, err
+	}
+
+	points := make([]DataPoint, len(rows))
+	for i, row := range rows {
+		var parse_err error
+		if len(row.cells) > 0 {
+			parse_err = fmt.Errorf("download has vintage columns, use SeriesVintageObservationsFile")
+		} else {
+			points[i], parse_err = parse_data_point(row.date, row.value)
+		}
+		if parse_err != nil {
+			return nil, c.request_error(req_url, &APIError{
+				ty:  ParseError,
+				msg: fmt.Sprintf("could not read %s download of series %s: %v", format, req.Series, parse_err),
+				err: parse_err,
+			})
+		}
+	}
+	return points, nil
+}
+
+// Download every vintage of every observation of the series in one request,
+// as a file of the given format. FRED zips vintages, splitting them across
+// files once there are too many columns.
+//
+// Defaults are the same as `SeriesVintageObservations`.
+func (c Client) SeriesVintageObservationsFile(req SeriesObservationsRequest, format ResponseFormat) ([]VintageDataPoint, Error) {
+	return c.SeriesVintageObservationsFileContext(context.Background(), req, format)
+}
+
+// Same as `SeriesVintageObservationsFile`, but the request is bound to the given context.
+func (c Client) SeriesVintageObservationsFileContext(ctx context.Context, req SeriesObservationsRequest, format ResponseFormat) ([]VintageDataPoint, Error) {
+	req = req.with_vintage_defaults()
+	rows, req_url, err := c.download(ctx, "series vintage observations file", req, format)
+	if err != nil {
+		return nil, err
+	}
+
+	points, parse_err := vintage_points(rows, req.Output)
+	if parse_err != nil {
+		return nil, c.request_error(req_url, &APIError{
+			ty:  ParseError,
+			msg: fmt.Sprintf("could not read %s download of vintages of series %s: %v", format, req.Series, parse_err),
+			err: parse_err,
+		})
+	}
+	return points, nil
+}
@@ -0,0 +1,222 @@
+package gofred
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Zips the given name and content pairs, in order.
+func zip_files(t *testing.T, files ...string) []byte {
+	var buf bytes.Buffer
+	archive := zip.NewWriter(&buf)
+	for i := 0; i+1 < len(files); i += 2 {
+		w, err := archive.Create(files[i])
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := w.Write([]byte(files[i+1])); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := archive.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+// Serves the given body, checking the file type requested.
+func new_download_test_server(t *testing.T, file_type string, body []byte) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("file_type") != file_type {
+			t.Errorf("expected file_type=%s, got: %s", file_type, r.URL.Query().Get("file_type"))
+		}
+		w.Write(body)
+	}))
+}
+
+const DOWNLOAD_README = `Real Gross National Product (GNPCA)
+
+Observations are in GNPCA.txt, see the notes below.
+`
+
+const DOWNLOAD_TXT = "Title:               Real Gross National Product\r\n" +
+	"Series ID:           GNPCA\r\n" +
+	"Frequency:           Annual\r\n" +
+	"Date Range:          1929-01-01 to 1932-01-01\r\n" +
+	"Notes:               BEA Account Code: A001RX\r\n" +
+	"                     The date of each observation is the start of its year.\r\n" +
+	"\r\n" +
+	"DATE          VALUE\r\n" +
+	"1929-01-01   1120.718\r\n" +
+	"1930-01-01   1025.678\r\n" +
+	"1931-01-01          .\r\n" +
+	"1932-01-01    812.866\r\n"
+
+func check_data_points(t *testing.T, expect, got []DataPoint) {
+	if len(got) != len(expect) {
+		t.Fatalf("expected %d points, got %d: %+v", len(expect), len(got), got)
+	}
+	for i := range expect {
+		if got[i] != expect[i] {
+			t.Errorf("point %d: expected %+v, got: %+v", i, expect[i], got[i])
+		}
+	}
+}
+
+func gnpca_points(t *testing.T) []DataPoint {
+	return []DataPoint{
+		{Date(vintage_date(t, "1929-01-01")), 1120.718, true},
+		{Date(vintage_date(t, "1930-01-01")), 1025.678, true},
+		{Date(vintage_date(t, "1931-01-01")), 0, false},
+		{Date(vintage_date(t, "1932-01-01")), 812.866, true},
+	}
+}
+
+func TestSeriesObservationsFile_TXT(t *testing.T) {
+	for _, body := range [][]byte{
+		[]byte(DOWNLOAD_TXT),
+		zip_files(t, "README.txt", DOWNLOAD_README, "GNPCA.txt", DOWNLOAD_TXT),
+	} {
+		server := new_download_test_server(t, "txt", body)
+		client := make_client(t, JSON, WithBaseURL(server.URL))
+
+		points, err := client.SeriesObservationsFile(NewSeriesObservationsRequest(SERIES_GNP_ANNUAL, time.Time{}, time.Time{}), TXT)
+		server.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		check_data_points(t, gnpca_points(t), points)
+	}
+}
+
+func TestSeriesVintageObservationsFile_ZippedTXT(t *testing.T) {
+	// too many vintages for one file, each holds some of the columns
+	body := zip_files(t,
+		"GNPCA_1.txt", "observation_date\tGNPCA_20010115\tGNPCA_20010215\n"+
+			"2000-01-01\t1.0\t1.0\n"+
+			"2000-02-01\t.\t2.0\n",
+		"GNPCA_2.txt", "observation_date\tGNPCA_20010315\n"+
+			"2000-01-01\t1.5\n"+
+			"2000-02-01\t2.0\n",
+		"README_SERIES_ID_SORT.txt", DOWNLOAD_README)
+	server := new_download_test_server(t, "txt", body)
+	defer server.Close()
+	client := make_client(t, XML, WithBaseURL(server.URL))
+
+	points, err := client.SeriesVintageObservationsFile(NewSeriesObservationsRequest(SERIES_GNP_ANNUAL, time.Time{}, time.Time{}), TXT)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	jan, feb := Date(vintage_date(t, "2000-01-01")), Date(vintage_date(t, "2000-02-01"))
+	latest := Date(vintage_date(t, REALTIME_LATEST))
+	check_vintage_points(t, []VintageDataPoint{
+		{DataPoint{jan, 1.0, true}, Date(vintage_date(t, "2001-01-15")), Date(vintage_date(t, "2001-03-14"))},
+		{DataPoint{jan, 1.5, true}, Date(vintage_date(t, "2001-03-15")), latest},
+		{DataPoint{feb, 2.0, true}, Date(vintage_date(t, "2001-02-15")), latest},
+	}, points)
+}
+
+func TestSeriesVintageObservationsFile_RealtimePeriods(t *testing.T) {
+	body := []byte("realtime_start_date,realtime_end_date,observation_date,GNPCA\n" +
+		"2001-01-15,2001-03-14,2000-01-01,1.0\n" +
+		"2001-03-15,9999-12-31,2000-01-01,1.5\n" +
+		"2001-01-15,9999-12-31,2000-02-01,\n")
+	server := new_download_test_server(t, "txt", body)
+	defer server.Close()
+	client := make_client(t, JSON, WithBaseURL(server.URL))
+
+	req := NewSeriesObservationsRequest(SERIES_GNP_ANNUAL, time.Time{}, time.Time{})
+	req.Output = OutputByRealtimePeriod
+	points, err := client.SeriesVintageObservationsFile(req, TXT)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	jan, feb := Date(vintage_date(t, "2000-01-01")), Date(vintage_date(t, "2000-02-01"))
+	latest := Date(vintage_date(t, REALTIME_LATEST))
+	check_vintage_points(t, []VintageDataPoint{
+		{DataPoint{jan, 1.0, true}, Date(vintage_date(t, "2001-01-15")), Date(vintage_date(t, "2001-03-14"))},
+		{DataPoint{jan, 1.5, true}, Date(vintage_date(t, "2001-03-15")), latest},
+		{DataPoint{feb, 0, false}, Date(vintage_date(t, "2001-01-15")), latest},
+	}, points)
+}
+
+func TestSeriesObservationsFile_Errors(t *testing.T) {
+	if _, err := NewClient(API_KEY, TXT); err == nil {
+		t.Errorf("expected clients to only use json or xml")
+	}
+
+	vintages := []byte("DATE\tGNPCA_20010115\n2000-01-01\t1.0\n")
+	server := new_download_test_server(t, "xlsx", vintages)
+	defer server.Close()
+	client := make_client(t, JSON, WithBaseURL(server.URL))
+	req := NewSeriesObservationsRequest(SERIES_GNP_ANNUAL, time.Time{}, time.Time{})
+
+	if _, err := client.SeriesObservationsFile(req, XML); !errors.Is(err, ErrInvalidRequest) {
+		t.Errorf("expected xml to be rejected as a file format, got: %v", err)
+	}
+	if _, err := client.SeriesObservationsFile(req, XLSX); !errors.Is(err, ErrParse) {
+		t.Errorf("expected vintage columns to be rejected, got: %v", err)
+	}
+
+	// errors are decoded whatever their format, or typed by their status
+	bodies := map[string]string{
+		"json": `{"error_code":400,"error_message":"Bad Request.  The series does not exist."}`,
+		"xml":  `<?xml version="1.0" encoding="utf-8" ?><error code="400" message="Bad Request.  The series does not exist."/>`,
+		"html": `<html><body>Bad Request</body></html>`,
+		"text": `Bad Request`,
+	}
+	for desc, body := range bodies {
+		failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(body))
+		}))
+		client = make_client(t, JSON, WithBaseURL(failing.URL))
+		_, err := client.SeriesObservationsFile(req, TXT)
+		failing.Close()
+
+		var api_err *APIError
+		if !errors.Is(err, ErrInvalidRequest) || !errors.As(err, &api_err) || api_err.StatusCode() != 400 {
+			t.Errorf("%s: expected an invalid request, got: %v", desc, err)
+			continue
+		}
+		if decoded := desc == "json" || desc == "xml"; decoded != (api_err.ErrorCode() == 400) {
+			t.Errorf("%s: unexpected error code %d", desc, api_err.ErrorCode())
+		}
+	}
+
+	// the fake serves no files, refusing them with an XML error whatever the client's format
+	fake_test(t, func(client Client) {
+		_, err := client.SeriesObservationsFile(req, XLSX)
+		var api_err *APIError
+		if !errors.Is(err, ErrInvalidRequest) || !errors.As(err, &api_err) || !strings.Contains(api_err.ErrorMessage(), "file_type") {
+			t.Errorf("expected the fake's error to be decoded, got: %v", err)
+		}
+	})
+
+	if _, err := download_rows([]byte(DOWNLOAD_README)); err == nil {
+		t.Errorf("expected a download without observations to fail")
+	}
+}
+
+func TestDownloadDate(t *testing.T) {
+	tests := map[string]string{
+		"1929-01-01":          "1929-01-01",
+		"1929-01-01 00:00:00": "1929-01-01",
+		"10594":               "1929-01-01",
+		"36526.5":             "2000-01-01",
+		"1/1/1929":            "1929-01-01",
+	}
+	for cell, expect := range tests {
+		if got := download_date(cell); got != expect {
+			t.Errorf("%s: expected %s, got: %s", cell, expect, got)
+		}
+	}
+}
@@ -0,0 +1,154 @@
+package fredtest
+
+import (
+	"encoding/xml"
+)
+
+type wireCategory struct {
+	Id       uint   `json:"id" xml:"id,attr"`
+	Name     string `json:"name" xml:"name,attr"`
+	ParentId uint   `json:"parent_id" xml:"parent_id,attr"`
+}
+
+type categoriesResponse struct {
+	XMLName    xml.Name       `json:"-" xml:"categories"`
+	Categories []wireCategory `json:"categories" xml:"category"`
+}
+
+func (c Category) wire() wireCategory {
+	return wireCategory{c.Id, c.Name, c.ParentId}
+}
+
+// The category named by the `category_id` parameter, the root if it is not set.
+func (r request) category(data Dataset) (Category, *apiError) {
+	id, err := r.uint_param("category_id", 0)
+	if err != nil {
+		return Category{}, err
+	}
+
+	cat, exists := data.category(id)
+	if !exists {
+		return Category{}, bad_request("The category does not exist.")
+	}
+	return cat, nil
+}
+
+// Series in the category named by the `category_id` parameter.
+func (r request) category_members(data Dataset) ([]Series, *apiError) {
+	cat, err := r.category(data)
+	if err != nil {
+		return nil, err
+	}
+
+	members := []Series{}
+	for _, s := range data.Series {
+		if s.in_category(cat.Id) {
+			members = append(members, s)
+		}
+	}
+	return members, nil
+}
+
+//==============================================================================
+//
+// GET: /fred/category
+//
+//==============================================================================
+
+func get_category(data Dataset, req request) (interface{}, *apiError) {
+	cat, err := req.category(data)
+	if err != nil {
+		return nil, err
+	}
+	return categoriesResponse{Categories: []wireCategory{cat.wire()}}, nil
+}
+
+//==============================================================================
+//
+// GET: /fred/category/children
+//
+//==============================================================================
+
+func get_category_children(data Dataset, req request) (interface{}, *apiError) {
+	cat, err := req.category(data)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := req.realtime(); err != nil {
+		return nil, err
+	}
+
+	result := categoriesResponse{Categories: []wireCategory{}}
+	for _, child := range data.Categories {
+		if child.ParentId == cat.Id && child.Id != cat.Id {
+			result.Categories = append(result.Categories, child.wire())
+		}
+	}
+	return result, nil
+}
+
+//==============================================================================
+//
+// GET: /fred/category/related
+//
+//==============================================================================
+
+func get_category_related(data Dataset, req request) (interface{}, *apiError) {
+	cat, err := req.category(data)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := req.realtime(); err != nil {
+		return nil, err
+	}
+
+	result := categoriesResponse{Categories: []wireCategory{}}
+	for _, id := range cat.Related {
+		if related, exists := data.category(id); exists {
+			result.Categories = append(result.Categories, related.wire())
+		}
+	}
+	return result, nil
+}
+
+//==============================================================================
+//
+// GET: /fred/category/series
+//
+//==============================================================================
+
+func get_category_series(data Dataset, req request) (interface{}, *apiError) {
+	members, err := req.category_members(data)
+	if err != nil {
+		return nil, err
+	}
+	return req.series_list(members, series_orders...)
+}
+
+//==============================================================================
+//
+// GET: /fred/category/tags
+//
+//==============================================================================
+
+func get_category_tags(data Dataset, req request) (interface{}, *apiError) {
+	members, err := req.category_members(data)
+	if err != nil {
+		return nil, err
+	}
+	return req.tag_list(data, members, false, "search_text")
+}
+
+//==============================================================================
+//
+// GET: /fred/category/related_tags
+//
+//==============================================================================
+
+func get_category_related_tags(data Dataset, req request) (interface{}, *apiError) {
+	members, err := req.category_members(data)
+	if err != nil {
+		return nil, err
+	}
+	return req.tag_list(data, members, true, "search_text")
+}
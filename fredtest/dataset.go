@@ -0,0 +1,435 @@
+package fredtest
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+)
+
+//==============================================================================
+// dataset
+//==============================================================================
+
+// Everything the fake server knows about. It is read, never modified, by the
+// server so the same dataset can back any number of servers.
+type Dataset struct {
+	Categories []Category
+	Series     []Series
+	Tags       []Tag
+	Releases   []Release
+	Sources    []Source
+}
+
+// A node of the category tree. The root category has an ID of 0.
+type Category struct {
+	Id       uint
+	Name     string
+	ParentId uint
+	Related  []uint // served by `/category/related`
+}
+
+type Series struct {
+	Id                      string
+	Title                   string
+	Frequency               string // e.g. "Monthly"
+	FrequencyShort          string // e.g. "M"
+	Units                   string
+	UnitsShort              string
+	SeasonalAdjustment      string // e.g. "Not Seasonally Adjusted"
+	SeasonalAdjustmentShort string // e.g. "NSA"
+	LastUpdated             time.Time
+	Popularity              uint
+	Notes                   string
+
+	Categories   []uint
+	Release      uint
+	Tags         []string
+	Observations []Observation // in date order
+	VintageDates []time.Time   // in date order
+}
+
+type Observation struct {
+	Date    time.Time
+	Value   float64
+	Missing bool // served as "."
+}
+
+// Tags used by series but not listed in the dataset are served in the "gen" group.
+type Tag struct {
+	Name       string
+	GroupId    string // e.g. "freq", "geo", "src"
+	Notes      string
+	Created    time.Time
+	Popularity uint
+}
+
+type Release struct {
+	Id           uint
+	Name         string
+	PressRelease bool
+	Link         string
+	Notes        string
+
+	Sources []uint         // served by `/release/sources`
+	Dates   []time.Time    // in date order
+	Tables  []TableElement // top level elements of the release's tables
+}
+
+// A section of a release's tables, or a series when `SeriesId` is set.
+type TableElement struct {
+	Id       uint
+	SeriesId string
+	Name     string
+	Children []TableElement
+}
+
+type Source struct {
+	Id    uint
+	Name  string
+	Link  string
+	Notes string
+}
+
+func (d Dataset) category(id uint) (Category, bool) {
+	for _, cat := range d.Categories {
+		if cat.Id == id {
+			return cat, true
+		}
+	}
+	return Category{}, false
+}
+
+func (d Dataset) series(id string) (Series, bool) {
+	for _, s := range d.Series {
+		if s.Id == id {
+			return s, true
+		}
+	}
+	return Series{}, false
+}
+
+func (d Dataset) release(id uint) (Release, bool) {
+	for _, r := range d.Releases {
+		if r.Id == id {
+			return r, true
+		}
+	}
+	return Release{}, false
+}
+
+func (d Dataset) source(id uint) (Source, bool) {
+	for _, s := range d.Sources {
+		if s.Id == id {
+			return s, true
+		}
+	}
+	return Source{}, false
+}
+
+func (d Dataset) tag(name string) Tag {
+	for _, tag := range d.Tags {
+		if tag.Name == name {
+			return tag
+		}
+	}
+	return Tag{Name: name, GroupId: "gen"}
+}
+
+func (s Series) has_tag(name string) bool {
+	for _, tag := range s.Tags {
+		if tag == name {
+			return true
+		}
+	}
+	return false
+}
+
+func (r Release) has_source(id uint) bool {
+	for _, source := range r.Sources {
+		if source == id {
+			return true
+		}
+	}
+	return false
+}
+
+func (s Series) in_category(id uint) bool {
+	for _, cat := range s.Categories {
+		if cat == id {
+			return true
+		}
+	}
+	return false
+}
+
+//==============================================================================
+// sample data
+//==============================================================================
+
+func day(date string) time.Time {
+	t, err := time.Parse("2006-01-02", date)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+// `count` observations `months` apart (12 for annual data), starting at `start`.
+func observations(start string, months, count int, value func(i int) float64) []Observation {
+	from := day(start)
+	result := make([]Observation, count)
+	for i := range result {
+		result[i] = Observation{
+			Date:  from.AddDate(0, i*months, 0),
+			Value: math.Round(value(i)*100) / 100,
+		}
+	}
+	return result
+}
+
+// `count` dates `months` apart, starting at `start`.
+func dates(start string, months, count int) []time.Time {
+	from := day(start)
+	result := make([]time.Time, count)
+	for i := range result {
+		result[i] = from.AddDate(0, i*months, 0)
+	}
+	return result
+}
+
+// A small dataset shaped like the real FRED data the library's tests were first
+// written against: the trade balance and district category trees, annual GNP
+// (`GNPCA`) and the yen exchange rate (`EXJPUS`), with their releases and sources.
+//
+// IDs, names and the category tree follow FRED. Observation values, release dates
+// and the elements of release tables are synthetic.
+func Sample() Dataset {
+	created := day("2012-02-27")
+	tags := []Tag{
+		{"annual", "freq", "", created, 87},
+		{"monthly", "freq", "", created, 95},
+		{"quarterly", "freq", "", created, 92},
+		{"weekly", "freq", "", created, 74},
+		{"nsa", "seas", "Not Seasonally Adjusted", created, 99},
+		{"sa", "seas", "Seasonally Adjusted", created, 88},
+		{"saar", "seas", "Seasonally Adjusted Annual Rate", created, 80},
+		{"bea", "src", "Bureau of Economic Analysis", created, 86},
+		{"census", "src", "Census", created, 78},
+		{"frb", "src", "Board of Governors", created, 82},
+		{"h10", "rls", "H.10 Foreign Exchange Rates", created, 60},
+		{"usa", "geo", "United States of America", created, 100},
+		{"japan", "geo", "", created, 65},
+		{"nation", "geot", "Country Level", created, 100},
+		{"trade", "gen", "", created, 70},
+		{"balance", "gen", "", created, 62},
+		{"goods", "gen", "", created, 68},
+		{"services", "gen", "", created, 66},
+		{"exchange rate", "gen", "", created, 72},
+		{"gnp", "gen", "Gross National Product", created, 58},
+		{"monetary aggregates", "gen", "", created, 64},
+		{"m2", "gen", "", created, 61},
+		{"interest rate", "gen", "", created, 81},
+		{"mortgage", "gen", "", created, 69},
+		{"30-year", "gen", "", created, 63},
+	}
+
+	series := []Series{
+		{
+			Id: "GNPCA", Title: "Real Gross National Product",
+			Frequency: "Annual", FrequencyShort: "A",
+			Units: "Billions of Chained 2017 Dollars", UnitsShort: "Bil. of Chn. 2017 $",
+			SeasonalAdjustment: "Not Seasonally Adjusted", SeasonalAdjustmentShort: "NSA",
+			LastUpdated: day("2024-03-28").Add(7*time.Hour + 46*time.Minute), Popularity: 12,
+			Notes:      "BEA Account Code: A001RX",
+			Categories: []uint{106}, Release: 53,
+			Tags:         []string{"annual", "bea", "gnp", "nation", "nsa", "usa"},
+			Observations: observations("1929-01-01", 12, 95, func(i int) float64 { return 1202.66 * math.Pow(1.031, float64(i)) }),
+		},
+		{
+			Id: "EXJPUS", Title: "Japanese Yen to U.S. Dollar Spot Exchange Rate",
+			Frequency: "Monthly", FrequencyShort: "M",
+			Units: "Japanese Yen to One U.S. Dollar", UnitsShort: "Yen to 1 U.S. $",
+			SeasonalAdjustment: "Not Seasonally Adjusted", SeasonalAdjustmentShort: "NSA",
+			LastUpdated: day("2024-04-01").Add(15*time.Hour + 19*time.Minute), Popularity: 63,
+			Notes:      "Averages of daily figures. Noon buying rates in New York City.",
+			Categories: []uint{95, 275}, Release: 17,
+			Tags:         []string{"exchange rate", "frb", "h10", "japan", "monthly", "nation", "nsa"},
+			Observations: observations("1971-01-01", 1, 636, func(i int) float64 { return 100 + 257.8*math.Exp(-float64(i)/120) + 8*math.Sin(float64(i)/9) }),
+		},
+		{
+			Id: "M2SL", Title: "M2",
+			Frequency: "Monthly", FrequencyShort: "M",
+			Units: "Billions of Dollars", UnitsShort: "Bil. of $",
+			SeasonalAdjustment: "Seasonally Adjusted", SeasonalAdjustmentShort: "SA",
+			LastUpdated: day("2024-03-26").Add(12*time.Hour + 1*time.Minute), Popularity: 83,
+			Notes:      "A monetary aggregate: M1 plus small-denomination time deposits and retail money market funds.",
+			Categories: []uint{29}, Release: 21,
+			Tags:         []string{"frb", "m2", "monetary aggregates", "monthly", "nation", "sa", "usa"},
+			Observations: observations("1959-01-01", 1, 780, func(i int) float64 { return 286.6 * math.Pow(1.0055, float64(i)) }),
+		},
+		{
+			Id: "MORTGAGE30US", Title: "30-Year Fixed Rate Mortgage Average in the United States",
+			Frequency: "Monthly", FrequencyShort: "M",
+			Units: "Percent", UnitsShort: "%",
+			SeasonalAdjustment: "Not Seasonally Adjusted", SeasonalAdjustmentShort: "NSA",
+			LastUpdated: day("2024-04-04").Add(11*time.Hour + 2*time.Minute), Popularity: 89,
+			Notes:      "Reflects the monetary policy stance through long-term borrowing costs.",
+			Categories: []uint{114}, Release: 1,
+			Tags:         []string{"30-year", "interest rate", "monthly", "mortgage", "nation", "nsa", "usa"},
+			Observations: observations("1971-04-01", 1, 636, func(i int) float64 { return 7.5 + 3*math.Sin(float64(i)/60) }),
+		},
+		{
+			Id: "BOPGSTB", Title: "Trade Balance: Goods and Services, Balance of Payments Basis",
+			Frequency: "Monthly", FrequencyShort: "M",
+			Units: "Millions of Dollars", UnitsShort: "Mil. of $",
+			SeasonalAdjustment: "Seasonally Adjusted", SeasonalAdjustmentShort: "SA",
+			LastUpdated: day("2024-04-04").Add(7*time.Hour + 31*time.Minute), Popularity: 75,
+			Categories: []uint{125}, Release: 51,
+			Tags:         []string{"balance", "bea", "census", "goods", "monthly", "nation", "sa", "services", "trade", "usa"},
+			Observations: observations("1992-01-01", 1, 384, func(i int) float64 { return -2500 - 180*float64(i) }),
+		},
+		{
+			Id: "BOPGTB", Title: "Trade Balance: Goods, Balance of Payments Basis",
+			Frequency: "Monthly", FrequencyShort: "M",
+			Units: "Millions of Dollars", UnitsShort: "Mil. of $",
+			SeasonalAdjustment: "Seasonally Adjusted", SeasonalAdjustmentShort: "SA",
+			LastUpdated: day("2024-04-04").Add(7*time.Hour + 32*time.Minute), Popularity: 57,
+			Categories: []uint{125}, Release: 51,
+			Tags:         []string{"balance", "bea", "census", "goods", "monthly", "nation", "sa", "trade", "usa"},
+			Observations: observations("1992-01-01", 1, 384, func(i int) float64 { return -5100 - 230*float64(i) }),
+		},
+		{
+			Id: "BOPSTB", Title: "Trade Balance: Services, Balance of Payments Basis",
+			Frequency: "Monthly", FrequencyShort: "M",
+			Units: "Millions of Dollars", UnitsShort: "Mil. of $",
+			SeasonalAdjustment: "Seasonally Adjusted", SeasonalAdjustmentShort: "SA",
+			LastUpdated: day("2024-04-04").Add(7*time.Hour + 33*time.Minute), Popularity: 45,
+			Categories: []uint{125}, Release: 51,
+			Tags:         []string{"balance", "bea", "census", "monthly", "nation", "sa", "services", "trade", "usa"},
+			Observations: observations("1992-01-01", 1, 384, func(i int) float64 { return 2600 + 50*float64(i) }),
+		},
+		{
+			Id: "NETEXP", Title: "Net Exports of Goods and Services",
+			Frequency: "Quarterly", FrequencyShort: "Q",
+			Units: "Billions of Dollars", UnitsShort: "Bil. of $",
+			SeasonalAdjustment: "Seasonally Adjusted Annual Rate", SeasonalAdjustmentShort: "SAAR",
+			LastUpdated: day("2024-03-28").Add(7*time.Hour + 52*time.Minute), Popularity: 68,
+			Categories: []uint{125}, Release: 53,
+			Tags:         []string{"balance", "bea", "goods", "nation", "quarterly", "saar", "services", "trade", "usa"},
+			Observations: observations("1947-01-01", 3, 308, func(i int) float64 { return 6 - 3.1*float64(i) }),
+		},
+	}
+
+	partners := []struct{ code, name string }{
+		{"CA", "Canada"}, {"CN", "China"}, {"JP", "Japan"}, {"MX", "Mexico"},
+		{"DE", "Germany"}, {"FR", "France"}, {"IT", "Italy"}, {"IN", "India"},
+		{"KR", "Korea"}, {"BR", "Brazil"}, {"TW", "Taiwan"}, {"SA", "Saudi Arabia"},
+		{"CH", "Switzerland"}, {"GB", "United Kingdom"},
+	}
+	for i, partner := range partners {
+		balance := -800 + 150*float64(i)
+		series = append(series, Series{
+			Id:        "BOPGTB" + partner.code,
+			Title:     fmt.Sprintf("U.S. Trade Balance in Goods with %s", partner.name),
+			Frequency: "Monthly", FrequencyShort: "M",
+			Units: "Millions of Dollars", UnitsShort: "Mil. of $",
+			SeasonalAdjustment: "Not Seasonally Adjusted", SeasonalAdjustmentShort: "NSA",
+			LastUpdated: day("2024-04-04").Add(time.Duration(8*60+i) * time.Minute), Popularity: uint(20 + i),
+			Categories: []uint{125}, Release: 51,
+			Tags:         []string{"balance", "census", "goods", "monthly", "nation", "nsa", "trade", "usa", strings.ToLower(partner.name)},
+			Observations: observations("1985-01-01", 1, 468, func(j int) float64 { return balance * (1 + float64(j)/100) }),
+		})
+		if partner.code != "JP" {
+			tags = append(tags, Tag{strings.ToLower(partner.name), "geo", "", created, uint(30 + i)})
+		}
+	}
+
+	gnp := &series[0]
+	gnp.VintageDates = []time.Time{day("1958-12-21")}
+	for year := 1959; year <= 2023; year++ {
+		gnp.VintageDates = append(gnp.VintageDates, time.Date(year, time.July, 27+year%3, 0, 0, 0, 0, time.UTC))
+	}
+	for i := range series {
+		if len(series[i].VintageDates) == 0 {
+			series[i].VintageDates = []time.Time{series[i].LastUpdated.Truncate(24 * time.Hour)}
+		}
+	}
+
+	return Dataset{
+		Categories: []Category{
+			{Id: 0, Name: "Categories", ParentId: 0},
+			{Id: 32991, Name: "Money, Banking, & Finance", ParentId: 0},
+			{Id: 32992, Name: "National Accounts", ParentId: 0},
+			{Id: 3008, Name: "U.S. Regional Data", ParentId: 0},
+			{Id: 32263, Name: "International Data", ParentId: 0},
+
+			{Id: 13, Name: "U.S. Trade & International Transactions", ParentId: 32992},
+			{Id: 125, Name: "Trade Balance", ParentId: 13},
+			{Id: 18, Name: "National Income & Product Accounts", ParentId: 32992},
+			{Id: 106, Name: "GDP/GNP", ParentId: 18},
+
+			{Id: 15, Name: "Exchange Rates", ParentId: 32991},
+			{Id: 95, Name: "Monthly Rates", ParentId: 15},
+			{Id: 24, Name: "Monetary Data", ParentId: 32991},
+			{Id: 29, Name: "M2 and Components", ParentId: 24},
+			{Id: 22, Name: "Interest Rates", ParentId: 32991},
+			{Id: 114, Name: "Mortgage Rates", ParentId: 22},
+
+			{Id: 158, Name: "Countries", ParentId: 32263},
+			{Id: 275, Name: "Japan", ParentId: 158},
+
+			{Id: 27281, Name: "States", ParentId: 3008},
+			{Id: 32073, Name: "St. Louis Fed District", ParentId: 3008, Related: []uint{149, 150, 151, 152, 153, 154, 193}},
+			{Id: 149, Name: "Arkansas", ParentId: 27281},
+			{Id: 150, Name: "Illinois", ParentId: 27281},
+			{Id: 151, Name: "Indiana", ParentId: 27281},
+			{Id: 152, Name: "Kentucky", ParentId: 27281},
+			{Id: 153, Name: "Mississippi", ParentId: 27281},
+			{Id: 154, Name: "Missouri", ParentId: 27281},
+			{Id: 193, Name: "Tennessee", ParentId: 27281},
+		},
+		Series: series,
+		Tags:   tags,
+		Releases: []Release{
+			{
+				Id: 1, Name: "Primary Mortgage Market Survey", Link: "http://www.freddiemac.com/pmms/",
+				Dates: dates("2010-01-07", 1, 168),
+			},
+			{
+				Id: 17, Name: "H.10 Foreign Exchange Rates", PressRelease: true, Link: "http://www.federalreserve.gov/releases/h10/",
+				Sources: []uint{1}, Dates: dates("2010-01-04", 1, 168),
+			},
+			{
+				Id: 21, Name: "H.6 Money Stock Measures", PressRelease: true, Link: "http://www.federalreserve.gov/releases/h6/",
+				Sources: []uint{1}, Dates: dates("2010-01-14", 1, 168),
+			},
+			{
+				Id: 50, Name: "Employment Situation", PressRelease: true, Link: "http://www.bls.gov/ces/",
+				Sources: []uint{22}, Dates: dates("2010-01-08", 1, 168),
+			},
+			{
+				Id: 51, Name: "U.S. International Trade in Goods and Services", PressRelease: true, Link: "http://www.bea.gov/newsreleases/international/trade/tradnewsrelease.htm",
+				Sources: []uint{18, 19}, Dates: dates("2010-01-12", 1, 168),
+			},
+			{
+				Id: 53, Name: "Gross National Product", PressRelease: true, Link: "http://www.bea.gov/national/index.htm",
+				Sources: []uint{18}, Dates: dates("2010-01-29", 1, 168),
+				Tables: []TableElement{
+					{Id: 12886, Name: "Gross national product", Children: []TableElement{
+						{Id: 12887, SeriesId: "GNPCA", Name: "Real gross national product"},
+					}},
+					{Id: 12890, Name: "Foreign trade", Children: []TableElement{
+						{Id: 12891, SeriesId: "NETEXP", Name: "Net exports of goods and services"},
+					}},
+				},
+			},
+		},
+		Sources: []Source{
+			{Id: 1, Name: "Board of Governors of the Federal Reserve System (US)", Link: "http://www.federalreserve.gov/"},
+			{Id: 18, Name: "U.S. Bureau of Economic Analysis", Link: "http://www.bea.gov/"},
+			{Id: 19, Name: "U.S. Census Bureau", Link: "http://www.census.gov/"},
+			{Id: 22, Name: "U.S. Bureau of Labor Statistics", Link: "http://www.bls.gov/"},
+		},
+	}
+}
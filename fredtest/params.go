@@ -0,0 +1,187 @@
+package fredtest
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+//==============================================================================
+// parameters
+//==============================================================================
+
+func (r request) param(name string) string {
+	return r.URL.Query().Get(name)
+}
+
+// A non-negative integer parameter, or the default if it is not set.
+func (r request) uint_param(name string, def uint) (uint, *apiError) {
+	value := r.param(name)
+	if value == "" {
+		return def, nil
+	}
+
+	parsed, err := strconv.ParseUint(value, 10, 32)
+	if err != nil {
+		return 0, bad_request("Variable %s is not a non-negative integer.", name)
+	}
+	return uint(parsed), nil
+}
+
+func (r request) required(name string) (string, *apiError) {
+	value := r.param(name)
+	if value == "" {
+		return "", bad_request("Variable %s is not set.", name)
+	}
+	return value, nil
+}
+
+// A date parameter, or the default if it is not set.
+func (r request) date_param(name string, def time.Time) (time.Time, *apiError) {
+	value := r.param(name)
+	if value == "" {
+		return def, nil
+	}
+
+	parsed, err := time.Parse(DATE_FORMAT, value)
+	if err != nil {
+		return time.Time{}, bad_request("Variable %s is not a date in YYYY-MM-DD format.", name)
+	}
+	return parsed, nil
+}
+
+// A semicolon delimited list of tag names.
+func (r request) tag_names(name string) []string {
+	names := []string{}
+	for _, tag := range strings.Split(r.param(name), ";") {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			names = append(names, tag)
+		}
+	}
+	return names
+}
+
+// A parameter which must be one of the given values, or the default if it is not set.
+func (r request) one_of(name string, def string, values ...string) (string, *apiError) {
+	value := r.param(name)
+	if value == "" {
+		return def, nil
+	}
+
+	for _, allowed := range values {
+		if value == allowed {
+			return value, nil
+		}
+	}
+	return "", bad_request("Variable %s is not one of the values: %s.", name, strings.Join(values, ", "))
+}
+
+//==============================================================================
+// list responses
+//==============================================================================
+
+// Fields shared by every response with a realtime period.
+type realtime struct {
+	Start string `json:"realtime_start" xml:"realtime_start,attr"`
+	End   string `json:"realtime_end" xml:"realtime_end,attr"`
+}
+
+// Realtime period of the request, every date defaulting to today.
+func (r request) realtime() (realtime, *apiError) {
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	return r.period(today, today)
+}
+
+// Realtime period of the request, with the given default dates.
+func (r request) period(def_start, def_end time.Time) (realtime, *apiError) {
+	start, err := r.date_param("realtime_start", def_start)
+	if err != nil {
+		return realtime{}, err
+	}
+	end, err := r.date_param("realtime_end", def_end)
+	if err != nil {
+		return realtime{}, err
+	}
+	if end.Before(start) {
+		return realtime{}, bad_request("The value for variable realtime_start is after the value for variable realtime_end.")
+	}
+
+	return realtime{start.Format(DATE_FORMAT), end.Format(DATE_FORMAT)}, nil
+}
+
+// Fields shared by every paged response.
+type list struct {
+	realtime
+	Order  string `json:"order_by" xml:"order_by,attr"`
+	Sort   string `json:"sort_order" xml:"sort_order,attr"`
+	Count  int    `json:"count" xml:"count,attr"`
+	Offset uint   `json:"offset" xml:"offset,attr"`
+	Limit  uint   `json:"limit" xml:"limit,attr"`
+}
+
+// Reads the realtime period, paging and ordering of a list request, where
+// `orders` are the allowed `order_by` values, the first being the default.
+func (r request) list(max_limit uint, orders ...string) (list, *apiError) {
+	period, err := r.realtime()
+	if err != nil {
+		return list{}, err
+	}
+	return r.list_over(period, max_limit, orders...)
+}
+
+// Same as `list`, over an already read realtime period.
+func (r request) list_over(period realtime, max_limit uint, orders ...string) (list, *apiError) {
+	result := list{realtime: period}
+	var err *apiError
+	if result.Order, err = r.one_of("order_by", orders[0], orders...); err != nil {
+		return list{}, err
+	}
+	if result.Sort, err = r.one_of("sort_order", "asc", "asc", "desc"); err != nil {
+		return list{}, err
+	}
+	if result.Offset, err = r.uint_param("offset", 0); err != nil {
+		return list{}, err
+	}
+	if result.Limit, err = r.uint_param("limit", max_limit); err != nil {
+		return list{}, err
+	}
+	if result.Limit < 1 || result.Limit > max_limit {
+		return list{}, bad_request("Variable limit is not between 1 and %d.", max_limit)
+	}
+
+	return result, nil
+}
+
+// Sorts the `count` entries of a list by the given comparison and returns the
+// bounds of the requested page.
+func (l *list) page(count int, less func(i, j int) bool, swap func(i, j int)) (int, int) {
+	sort.Stable(sorter{count, less, swap, l.Sort == "desc"})
+
+	l.Count = count
+	from := int(l.Offset)
+	if from > count {
+		from = count
+	}
+	to := from + int(l.Limit)
+	if to > count {
+		to = count
+	}
+	return from, to
+}
+
+type sorter struct {
+	count int
+	less  func(i, j int) bool
+	swap  func(i, j int)
+	desc  bool
+}
+
+func (s sorter) Len() int      { return s.count }
+func (s sorter) Swap(i, j int) { s.swap(i, j) }
+func (s sorter) Less(i, j int) bool {
+	if s.desc {
+		return s.less(j, i)
+	}
+	return s.less(i, j)
+}
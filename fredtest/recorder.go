@@ -0,0 +1,200 @@
+package fredtest
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+//==============================================================================
+// record/replay
+//==============================================================================
+
+type RecordMode uint8
+
+const (
+	// Replay recorded responses, recording those which have not been yet.
+	ReplayOrRecord RecordMode = iota
+	// Only replay recorded responses, failing requests which have not been recorded.
+	Replay
+	// Send every request and record its response, overwriting any recorded one.
+	Record
+)
+
+// Value of `api_key` in recorded URLs, and of the key anywhere in recorded bodies.
+const REDACTED = "REDACTED"
+
+// An `http.RoundTripper` which records responses to golden files and replays
+// them, so tests written against the live API can run offline:
+//
+//	recorder := fredtest.NewRecorder("testdata/fixtures", fredtest.ReplayOrRecord)
+//	client, err := gofred.NewClient(key, gofred.JSON, gofred.WithHTTPClient(recorder.Client()))
+//
+// Requests are matched on their method, path and query parameters, except
+// `api_key` and `IgnoreParams`. The API key is scrubbed from everything written.
+type Recorder struct {
+	Dir          string
+	Mode         RecordMode
+	Transport    http.RoundTripper // used to send requests being recorded, `http.DefaultTransport` if nil
+	IgnoreParams []string          // e.g. "realtime_end" when it is computed from the current time
+
+	// Called in `Replay` mode with a request which has not been recorded, before
+	// it fails, e.g. to skip the test sending it with `t.Skip`.
+	OnMissing func(req *http.Request)
+
+	lock sync.Mutex
+}
+
+// A response as written to a golden file.
+type fixture struct {
+	Method string      `json:"method"`
+	URL    string      `json:"url"`
+	Status int         `json:"status"`
+	Header http.Header `json:"header"`
+	Body   string      `json:"body"`
+}
+
+func NewRecorder(dir string, mode RecordMode) *Recorder {
+	return &Recorder{Dir: dir, Mode: mode}
+}
+
+// An HTTP client sending its requests through the recorder.
+func (r *Recorder) Client() *http.Client {
+	return &http.Client{Transport: r}
+}
+
+// Golden file the response to the request is recorded in.
+//
+// The name is readable, from the path, and unique, from a hash of the request.
+func (r *Recorder) Path(req *http.Request) string {
+	query := req.URL.Query()
+	query.Del("api_key")
+	for _, param := range r.IgnoreParams {
+		query.Del(param)
+	}
+
+	hash := sha1.Sum([]byte(req.Method + " " + req.URL.Path + "?" + query.Encode()))
+	name := strings.Trim(strings.Replace(req.URL.Path, "/", "_", -1), "_")
+	if name == "" {
+		name = "root"
+	}
+	return filepath.Join(r.Dir, fmt.Sprintf("%s-%s.json", name, hex.EncodeToString(hash[:6])))
+}
+
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	if err := req.Context().Err(); err != nil {
+		return nil, err
+	}
+
+	path := r.Path(req)
+	if r.Mode != Record {
+		recorded, err := r.load(path)
+		if err == nil {
+			return recorded.response(req), nil
+		}
+		if r.Mode == Replay && os.IsNotExist(err) {
+			if r.OnMissing != nil {
+				r.OnMissing(req)
+			}
+			return nil, fmt.Errorf("fredtest: %s %s has not been recorded in %s", req.Method, scrub_url(req), path)
+		}
+		if r.Mode == Replay || !os.IsNotExist(err) {
+			return nil, fmt.Errorf("fredtest: could not replay %s %s from %s: %v", req.Method, scrub_url(req), path, err)
+		}
+	}
+
+	return r.record(req, path)
+}
+
+func (r *Recorder) load(path string) (fixture, error) {
+	raw, err := ioutil.ReadFile(path)
+	if err != nil {
+		return fixture{}, err
+	}
+
+	var recorded fixture
+	err = json.Unmarshal(raw, &recorded)
+	return recorded, err
+}
+
+func (r *Recorder) record(req *http.Request, path string) (*http.Response, error) {
+	transport := r.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+
+	res, err := transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	body, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	recorded := fixture{
+		Method: req.Method,
+		URL:    scrub_url(req),
+		Status: res.StatusCode,
+		Header: res.Header.Clone(),
+		Body:   string(body),
+	}
+	recorded.Header.Del("Set-Cookie")
+	recorded.Header.Del("Date")
+	if key := req.URL.Query().Get("api_key"); key != "" {
+		recorded.Body = strings.Replace(recorded.Body, key, REDACTED, -1)
+	}
+
+	if err := r.save(path, recorded); err != nil {
+		return nil, fmt.Errorf("fredtest: could not record %s %s: %v", req.Method, recorded.URL, err)
+	}
+	return recorded.response(req), nil
+}
+
+func (r *Recorder) save(path string, recorded fixture) error {
+	raw, err := json.MarshalIndent(recorded, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, append(raw, '\n'), 0644)
+}
+
+func (f fixture) response(req *http.Request) *http.Response {
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", f.Status, http.StatusText(f.Status)),
+		StatusCode:    f.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        f.Header.Clone(),
+		Body:          ioutil.NopCloser(bytes.NewReader([]byte(f.Body))),
+		ContentLength: int64(len(f.Body)),
+		Request:       req,
+	}
+}
+
+// URL of the request, with the API key redacted.
+func scrub_url(req *http.Request) string {
+	scrubbed := *req.URL
+	query := scrubbed.Query()
+	if query.Get("api_key") != "" {
+		query.Set("api_key", REDACTED)
+		scrubbed.RawQuery = query.Encode()
+	}
+	return scrubbed.String()
+}
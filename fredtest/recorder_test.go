@@ -0,0 +1,112 @@
+package fredtest
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func recorded_get(t *testing.T, client *http.Client, url string) (int, string) {
+	res, err := client.Get(url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer res.Body.Close()
+
+	body, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return res.StatusCode, string(body)
+}
+
+func TestRecorder_RecordThenReplay(t *testing.T) {
+	server := NewServer(Sample())
+	dir := t.TempDir()
+
+	url := server.URL() + "/category?" + params("json", "category_id", "125").Encode()
+	missing := server.URL() + "/category?" + params("json", "category_id", "999999").Encode()
+
+	recorder := NewRecorder(dir, ReplayOrRecord)
+	recorder.Transport = server.Client().Transport
+	status, live := recorded_get(t, recorder.Client(), url)
+	if status != http.StatusOK {
+		t.Fatalf("expected status 200, got: %d %s", status, live)
+	}
+	if status, _ := recorded_get(t, recorder.Client(), missing); status != http.StatusBadRequest {
+		t.Fatalf("expected errors to be recorded, got status: %d", status)
+	}
+	if server.Requests() != 2 {
+		t.Fatalf("expected 2 requests to be sent, got: %d", server.Requests())
+	}
+
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 2 {
+		t.Fatalf("expected 2 golden files, got: %d", len(files))
+	}
+	for _, file := range files {
+		raw, err := ioutil.ReadFile(dir + "/" + file.Name())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if strings.Contains(string(raw), API_KEY) {
+			t.Errorf("expected the api key to be scrubbed from %s:\n%s", file.Name(), raw)
+		}
+	}
+
+	// replay with the server gone, and with another key
+	server.Close()
+	replayer := NewRecorder(dir, Replay)
+	status, replayed := recorded_get(t, replayer.Client(), strings.Replace(url, API_KEY, "zyxwvutsrqponmlkjihgfedcba012345", 1))
+	if status != http.StatusOK || replayed != live {
+		t.Errorf("expected the recorded response:\n%s\ngot (%d):\n%s", live, status, replayed)
+	}
+	if status, _ := recorded_get(t, replayer.Client(), missing); status != http.StatusBadRequest {
+		t.Errorf("expected the recorded error, got status: %d", status)
+	}
+
+	req, _ := http.NewRequest("GET", url+"&limit=5", nil)
+	_, err = replayer.RoundTrip(req)
+	if err == nil || !strings.Contains(err.Error(), "has not been recorded") || strings.Contains(err.Error(), API_KEY) {
+		t.Errorf("expected an unrecorded request to fail when replaying, got: %v", err)
+	}
+	missed := []*http.Request{}
+	replayer.OnMissing = func(req *http.Request) { missed = append(missed, req) }
+	if _, err = replayer.RoundTrip(req); err == nil || len(missed) != 1 || missed[0] != req {
+		t.Errorf("expected the unrecorded request to be reported, got: %v, %v", missed, err)
+	}
+	if recorded_get(t, replayer.Client(), url); len(missed) != 1 {
+		t.Errorf("expected only unrecorded requests to be reported, got: %d", len(missed))
+	}
+
+	req, _ = http.NewRequest("GET", url, nil)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := replayer.RoundTrip(req.WithContext(ctx)); err == nil {
+		t.Errorf("expected a cancelled request to fail")
+	}
+}
+
+func TestRecorder_IgnoreParams(t *testing.T) {
+	recorder := NewRecorder("fixtures", Replay)
+	recorder.IgnoreParams = []string{"realtime_end"}
+
+	a, _ := http.NewRequest("GET", "https://api.stlouisfed.org/fred/series?series_id=GNPCA&realtime_end=2020-01-01&api_key=a", nil)
+	b, _ := http.NewRequest("GET", "https://api.stlouisfed.org/fred/series?realtime_end=2024-01-01&series_id=GNPCA&api_key=b", nil)
+	c, _ := http.NewRequest("GET", "https://api.stlouisfed.org/fred/series?series_id=EXJPUS&api_key=a", nil)
+
+	if recorder.Path(a) != recorder.Path(b) {
+		t.Errorf("expected the same golden file, got: %s and %s", recorder.Path(a), recorder.Path(b))
+	}
+	if recorder.Path(a) == recorder.Path(c) {
+		t.Errorf("expected different golden files for different series, got: %s", recorder.Path(c))
+	}
+	if !strings.HasPrefix(recorder.Path(a), "fixtures/fred_series-") {
+		t.Errorf("expected a golden file named after the endpoint, got: %s", recorder.Path(a))
+	}
+}
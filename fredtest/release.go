@@ -0,0 +1,390 @@
+package fredtest
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"time"
+)
+
+func (r Release) wire(period realtime) wireRelease {
+	return wireRelease{r.Id, period.Start, period.End, r.Name, r.PressRelease, r.Link, r.Notes}
+}
+
+// The release named by the `release_id` parameter.
+func (r request) release(data Dataset) (Release, *apiError) {
+	if _, err := r.required("release_id"); err != nil {
+		return Release{}, err
+	}
+	id, err := r.uint_param("release_id", 0)
+	if err != nil {
+		return Release{}, err
+	}
+
+	release, exists := data.release(id)
+	if !exists {
+		return Release{}, bad_request("The release does not exist.")
+	}
+	return release, nil
+}
+
+// Series of the release named by the `release_id` parameter.
+func (r request) release_members(data Dataset) ([]Series, *apiError) {
+	release, err := r.release(data)
+	if err != nil {
+		return nil, err
+	}
+
+	members := []Series{}
+	for _, s := range data.Series {
+		if s.Release == release.Id {
+			members = append(members, s)
+		}
+	}
+	return members, nil
+}
+
+//==============================================================================
+// release lists
+//==============================================================================
+
+type releaseList struct {
+	XMLName xml.Name `json:"-" xml:"releases"`
+	list
+	Releases []wireRelease `json:"releases" xml:"release"`
+}
+
+var release_orders = []string{"release_id", "name", "press_release", "realtime_start", "realtime_end"}
+
+func release_less(order string, a, b Release) bool {
+	switch order {
+	case "name":
+		return a.Name < b.Name
+	case "press_release":
+		return !a.PressRelease && b.PressRelease
+	}
+	return a.Id < b.Id
+}
+
+// Sorts and pages the given releases according to the request.
+func (r request) release_list(candidates []Release) (releaseList, *apiError) {
+	header, err := r.list(1000, release_orders...)
+	if err != nil {
+		return releaseList{}, err
+	}
+
+	matches := append([]Release{}, candidates...)
+	result := releaseList{list: header, Releases: []wireRelease{}}
+	from, to := result.page(len(matches),
+		func(i, j int) bool { return release_less(result.Order, matches[i], matches[j]) },
+		func(i, j int) { matches[i], matches[j] = matches[j], matches[i] })
+	for _, release := range matches[from:to] {
+		result.Releases = append(result.Releases, release.wire(result.realtime))
+	}
+	return result, nil
+}
+
+//==============================================================================
+//
+// GET: /fred/releases
+//
+//==============================================================================
+
+func get_releases(data Dataset, req request) (interface{}, *apiError) {
+	return req.release_list(data.Releases)
+}
+
+//==============================================================================
+// release dates
+//==============================================================================
+
+type wireReleaseDate struct {
+	ReleaseId   uint   `json:"release_id" xml:"release_id,attr"`
+	ReleaseName string `json:"release_name,omitempty" xml:"release_name,attr,omitempty"`
+	Date        string `json:"date" xml:",chardata"`
+}
+
+type releaseDatesResponse struct {
+	XMLName xml.Name `json:"-" xml:"release_dates"`
+	list
+	Dates []wireReleaseDate `json:"release_dates" xml:"release_date"`
+}
+
+type releaseDate struct {
+	release Release
+	date    time.Time
+}
+
+func release_date_less(order string, a, b releaseDate) bool {
+	switch order {
+	case "release_id":
+		return a.release.Id < b.release.Id
+	case "release_name":
+		return a.release.Name < b.release.Name
+	}
+	return a.date.Before(b.date)
+}
+
+// Lists the publication dates of the given releases within the realtime period,
+// which defaults to `start` onwards, naming the releases if `named`. Dates are
+// sorted in the `sort` order unless another is requested.
+func (r request) release_dates(releases []Release, start time.Time, named bool, sort string, max_limit uint, orders ...string) (releaseDatesResponse, *apiError) {
+	if _, err := r.one_of("include_release_dates_with_no_data", "false", "true", "false"); err != nil {
+		return releaseDatesResponse{}, err
+	}
+	period, err := r.period(start, day("9999-12-31"))
+	if err != nil {
+		return releaseDatesResponse{}, err
+	}
+	header, err := r.list_over(period, max_limit, orders...)
+	if err != nil {
+		return releaseDatesResponse{}, err
+	}
+	if r.param("sort_order") == "" {
+		header.Sort = sort
+	}
+
+	from, to := day(period.Start), day(period.End)
+	matches := []releaseDate{}
+	for _, release := range releases {
+		for _, date := range release.Dates {
+			if !date.Before(from) && !date.After(to) {
+				matches = append(matches, releaseDate{release, date})
+			}
+		}
+	}
+
+	result := releaseDatesResponse{list: header, Dates: []wireReleaseDate{}}
+	first, last := result.page(len(matches),
+		func(i, j int) bool { return release_date_less(result.Order, matches[i], matches[j]) },
+		func(i, j int) { matches[i], matches[j] = matches[j], matches[i] })
+	for _, match := range matches[first:last] {
+		date := wireReleaseDate{ReleaseId: match.release.Id, Date: match.date.Format(DATE_FORMAT)}
+		if named {
+			date.ReleaseName = match.release.Name
+		}
+		result.Dates = append(result.Dates, date)
+	}
+	return result, nil
+}
+
+//==============================================================================
+//
+// GET: /fred/releases/dates
+//
+//==============================================================================
+
+// Dates are listed from the start of the current year and, unlike every other
+// list, latest first by default.
+func get_releases_dates(data Dataset, req request) (interface{}, *apiError) {
+	year := time.Date(time.Now().UTC().Year(), time.January, 1, 0, 0, 0, 0, time.UTC)
+	return req.release_dates(data.Releases, year, true, "desc", 1000, "release_date", "release_id", "release_name")
+}
+
+//==============================================================================
+//
+// GET: /fred/release
+//
+//==============================================================================
+
+func get_release(data Dataset, req request) (interface{}, *apiError) {
+	release, err := req.release(data)
+	if err != nil {
+		return nil, err
+	}
+	period, err := req.realtime()
+	if err != nil {
+		return nil, err
+	}
+
+	return releasesResponse{realtime: period, Releases: []wireRelease{release.wire(period)}}, nil
+}
+
+//==============================================================================
+//
+// GET: /fred/release/dates
+//
+//==============================================================================
+
+func get_release_dates(data Dataset, req request) (interface{}, *apiError) {
+	release, err := req.release(data)
+	if err != nil {
+		return nil, err
+	}
+	return req.release_dates([]Release{release}, day("1776-07-04"), false, "asc", 10000, "release_date")
+}
+
+//==============================================================================
+//
+// GET: /fred/release/series
+//
+//==============================================================================
+
+func get_release_series(data Dataset, req request) (interface{}, *apiError) {
+	members, err := req.release_members(data)
+	if err != nil {
+		return nil, err
+	}
+	return req.series_list(members, series_orders...)
+}
+
+//==============================================================================
+//
+// GET: /fred/release/sources
+//
+//==============================================================================
+
+func get_release_sources(data Dataset, req request) (interface{}, *apiError) {
+	release, err := req.release(data)
+	if err != nil {
+		return nil, err
+	}
+	period, err := req.realtime()
+	if err != nil {
+		return nil, err
+	}
+
+	result := sourcesResponse{realtime: period, Sources: []wireSource{}}
+	for _, id := range release.Sources {
+		if source, exists := data.source(id); exists {
+			result.Sources = append(result.Sources, source.wire(period))
+		}
+	}
+	return result, nil
+}
+
+//==============================================================================
+//
+// GET: /fred/release/tags
+//
+//==============================================================================
+
+func get_release_tags(data Dataset, req request) (interface{}, *apiError) {
+	members, err := req.release_members(data)
+	if err != nil {
+		return nil, err
+	}
+	return req.tag_list(data, members, false, "search_text")
+}
+
+//==============================================================================
+//
+// GET: /fred/release/related_tags
+//
+//==============================================================================
+
+func get_release_related_tags(data Dataset, req request) (interface{}, *apiError) {
+	members, err := req.release_members(data)
+	if err != nil {
+		return nil, err
+	}
+	return req.tag_list(data, members, true, "search_text")
+}
+
+//==============================================================================
+//
+// GET: /fred/release/tables
+//
+//==============================================================================
+
+type wireElement struct {
+	Id        uint          `json:"element_id" xml:"element_id,attr"`
+	ReleaseId uint          `json:"release_id" xml:"release_id,attr"`
+	SeriesId  string        `json:"series_id" xml:"series_id,attr"`
+	ParentId  uint          `json:"parent_id" xml:"parent_id,attr"`
+	Line      string        `json:"line" xml:"line,attr"`
+	Type      string        `json:"type" xml:"type,attr"`
+	Name      string        `json:"name" xml:"name,attr"`
+	Level     string        `json:"level" xml:"level,attr"`
+	Children  []wireElement `json:"children" xml:"element"`
+}
+
+// Like FRED, JSON responses key the elements by their ID and quote the release's
+// ID, where XML responses list the elements.
+type releaseTablesResponse struct {
+	XMLName   xml.Name      `json:"-" xml:"release_tables"`
+	Name      string        `json:"name" xml:"name,attr"`
+	ElementId uint          `json:"element_id" xml:"element_id,attr"`
+	ReleaseId uint          `json:"release_id,string" xml:"release_id,attr"`
+	Elements  []wireElement `json:"-" xml:"element"`
+}
+
+func (r releaseTablesResponse) MarshalJSON() ([]byte, error) {
+	type plain releaseTablesResponse
+	elements := map[string]wireElement{}
+	for _, elem := range r.Elements {
+		elements[fmt.Sprint(elem.Id)] = elem
+	}
+	return json.Marshal(struct {
+		plain
+		Elements map[string]wireElement `json:"elements"`
+	}{plain(r), elements})
+}
+
+// The release's table elements as served, lines numbered through every table.
+func (r Release) wire_tables() []wireElement {
+	line := 0
+	var wire func(elements []TableElement, parent uint, level int) []wireElement
+	wire = func(elements []TableElement, parent uint, level int) []wireElement {
+		result := []wireElement{}
+		for _, elem := range elements {
+			line++
+			ty := "section"
+			if elem.SeriesId != "" {
+				ty = "series"
+			}
+			result = append(result, wireElement{
+				Id:        elem.Id,
+				ReleaseId: r.Id,
+				SeriesId:  elem.SeriesId,
+				ParentId:  parent,
+				Line:      fmt.Sprint(line),
+				Type:      ty,
+				Name:      elem.Name,
+				Level:     fmt.Sprint(level),
+			})
+			result[len(result)-1].Children = wire(elem.Children, elem.Id, level+1)
+		}
+		return result
+	}
+	return wire(r.Tables, 0, 0)
+}
+
+func find_element(elements []wireElement, id uint) (wireElement, bool) {
+	for _, elem := range elements {
+		if elem.Id == id {
+			return elem, true
+		}
+		if found, ok := find_element(elem.Children, id); ok {
+			return found, true
+		}
+	}
+	return wireElement{}, false
+}
+
+// Observation values are refused rather than looked up.
+func get_release_tables(data Dataset, req request) (interface{}, *apiError) {
+	release, err := req.release(data)
+	if err != nil {
+		return nil, err
+	}
+	element_id, err := req.uint_param("element_id", 0)
+	if err != nil {
+		return nil, err
+	}
+	if include, err := req.one_of("include_observation_values", "false", "true", "false"); err != nil {
+		return nil, err
+	} else if include == "true" || req.param("observation_date") != "" {
+		return nil, bad_request("fredtest does not serve observation values in release tables.")
+	}
+
+	result := releaseTablesResponse{Name: release.Name, ReleaseId: release.Id, Elements: release.wire_tables()}
+	if element_id > 0 {
+		root, exists := find_element(result.Elements, element_id)
+		if !exists {
+			return nil, bad_request("The element %d does not exist in release %d.", element_id, release.Id)
+		}
+		result.Name, result.ElementId, result.Elements = root.Name, root.Id, root.Children
+	}
+	return result, nil
+}
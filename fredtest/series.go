@@ -0,0 +1,591 @@
+package fredtest
+
+import (
+	"encoding/xml"
+	"math"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type wireSeries struct {
+	Id                      string `json:"id" xml:"id,attr"`
+	Start                   string `json:"realtime_start" xml:"realtime_start,attr"`
+	End                     string `json:"realtime_end" xml:"realtime_end,attr"`
+	Title                   string `json:"title" xml:"title,attr"`
+	ObservationStart        string `json:"observation_start" xml:"observation_start,attr"`
+	ObservationEnd          string `json:"observation_end" xml:"observation_end,attr"`
+	Frequency               string `json:"frequency" xml:"frequency,attr"`
+	FrequencyShort          string `json:"frequency_short" xml:"frequency_short,attr"`
+	Units                   string `json:"units" xml:"units,attr"`
+	UnitsShort              string `json:"units_short" xml:"units_short,attr"`
+	SeasonalAdjustment      string `json:"seasonal_adjustment" xml:"seasonal_adjustment,attr"`
+	SeasonalAdjustmentShort string `json:"seasonal_adjustment_short" xml:"seasonal_adjustment_short,attr"`
+	LastUpdated             string `json:"last_updated" xml:"last_updated,attr"`
+	Popularity              uint   `json:"popularity" xml:"popularity,attr"`
+	Notes                   string `json:"notes,omitempty" xml:"notes,attr,omitempty"`
+}
+
+func (s Series) observation_start() time.Time {
+	if len(s.Observations) == 0 {
+		return time.Time{}
+	}
+	return s.Observations[0].Date
+}
+
+func (s Series) observation_end() time.Time {
+	if len(s.Observations) == 0 {
+		return time.Time{}
+	}
+	return s.Observations[len(s.Observations)-1].Date
+}
+
+func (s Series) wire(period realtime) wireSeries {
+	return wireSeries{
+		Id:                      s.Id,
+		Start:                   period.Start,
+		End:                     period.End,
+		Title:                   s.Title,
+		ObservationStart:        s.observation_start().Format(DATE_FORMAT),
+		ObservationEnd:          s.observation_end().Format(DATE_FORMAT),
+		Frequency:               s.Frequency,
+		FrequencyShort:          s.FrequencyShort,
+		Units:                   s.Units,
+		UnitsShort:              s.UnitsShort,
+		SeasonalAdjustment:      s.SeasonalAdjustment,
+		SeasonalAdjustmentShort: s.SeasonalAdjustmentShort,
+		LastUpdated:             s.LastUpdated.Format(TIME_FORMAT),
+		Popularity:              s.Popularity,
+		Notes:                   s.Notes,
+	}
+}
+
+// The series named by the `series_id` parameter.
+func (r request) series(data Dataset) (Series, *apiError) {
+	id, err := r.required("series_id")
+	if err != nil {
+		return Series{}, err
+	}
+
+	s, exists := data.series(id)
+	if !exists {
+		return Series{}, bad_request("The series does not exist.")
+	}
+	return s, nil
+}
+
+// Series with all the given tags and none of the excluded ones.
+func with_tags(candidates []Series, all, none []string) []Series {
+	matches := []Series{}
+outer:
+	for _, s := range candidates {
+		for _, tag := range all {
+			if !s.has_tag(tag) {
+				continue outer
+			}
+		}
+		for _, tag := range none {
+			if s.has_tag(tag) {
+				continue outer
+			}
+		}
+		matches = append(matches, s)
+	}
+	return matches
+}
+
+//==============================================================================
+// series lists
+//==============================================================================
+
+var series_orders = []string{
+	"series_id", "title", "units", "frequency", "seasonal_adjustment", "realtime_start", "realtime_end",
+	"last_updated", "observation_start", "observation_end", "popularity", "group_popularity",
+}
+
+type seriesList struct {
+	XMLName xml.Name `json:"-" xml:"seriess"`
+	list
+	FilterVariable string       `json:"filter_variable,omitempty" xml:"filter_variable,attr,omitempty"`
+	FilterValue    string       `json:"filter_value,omitempty" xml:"filter_value,attr,omitempty"`
+	Series         []wireSeries `json:"seriess" xml:"series"`
+}
+
+func series_less(order string, a, b Series) bool {
+	switch order {
+	case "title":
+		return a.Title < b.Title
+	case "units":
+		return a.Units < b.Units
+	case "frequency":
+		return a.Frequency < b.Frequency
+	case "seasonal_adjustment":
+		return a.SeasonalAdjustment < b.SeasonalAdjustment
+	case "last_updated":
+		return a.LastUpdated.Before(b.LastUpdated)
+	case "observation_start":
+		return a.observation_start().Before(b.observation_start())
+	case "observation_end":
+		return a.observation_end().Before(b.observation_end())
+	case "popularity", "group_popularity", "search_rank":
+		return a.Popularity < b.Popularity
+	}
+	return a.Id < b.Id
+}
+
+// Filters, sorts and pages the given series according to the request.
+func (r request) series_list(candidates []Series, orders ...string) (seriesList, *apiError) {
+	header, err := r.list(1000, orders...)
+	if err != nil {
+		return seriesList{}, err
+	}
+	result := seriesList{list: header, Series: []wireSeries{}}
+
+	matches := with_tags(candidates, r.tag_names("tag_names"), r.tag_names("exclude_tag_names"))
+	if variable := r.param("filter_variable"); variable != "" {
+		if _, err := r.one_of("filter_variable", "", "frequency", "units", "seasonal_adjustment"); err != nil {
+			return seriesList{}, err
+		}
+		result.FilterVariable, result.FilterValue = variable, r.param("filter_value")
+
+		filtered := []Series{}
+		for _, s := range matches {
+			value := map[string]string{
+				"frequency":           s.Frequency,
+				"units":               s.Units,
+				"seasonal_adjustment": s.SeasonalAdjustment,
+			}[variable]
+			if value == result.FilterValue {
+				filtered = append(filtered, s)
+			}
+		}
+		matches = filtered
+	}
+
+	from, to := result.page(len(matches),
+		func(i, j int) bool { return series_less(result.Order, matches[i], matches[j]) },
+		func(i, j int) { matches[i], matches[j] = matches[j], matches[i] })
+	for _, s := range matches[from:to] {
+		result.Series = append(result.Series, s.wire(result.realtime))
+	}
+	return result, nil
+}
+
+//==============================================================================
+//
+// GET: /fred/series
+//
+//==============================================================================
+
+type seriesResponse struct {
+	XMLName xml.Name `json:"-" xml:"seriess"`
+	realtime
+	Series []wireSeries `json:"seriess" xml:"series"`
+}
+
+func get_series(data Dataset, req request) (interface{}, *apiError) {
+	s, err := req.series(data)
+	if err != nil {
+		return nil, err
+	}
+	period, err := req.realtime()
+	if err != nil {
+		return nil, err
+	}
+
+	return seriesResponse{realtime: period, Series: []wireSeries{s.wire(period)}}, nil
+}
+
+//==============================================================================
+//
+// GET: /fred/series/categories
+//
+//==============================================================================
+
+func get_series_categories(data Dataset, req request) (interface{}, *apiError) {
+	s, err := req.series(data)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := req.realtime(); err != nil {
+		return nil, err
+	}
+
+	result := categoriesResponse{Categories: []wireCategory{}}
+	for _, id := range s.Categories {
+		if cat, exists := data.category(id); exists {
+			result.Categories = append(result.Categories, cat.wire())
+		}
+	}
+	return result, nil
+}
+
+//==============================================================================
+//
+// GET: /fred/series/observations
+//
+//==============================================================================
+
+type wireObservation struct {
+	Start string `json:"realtime_start" xml:"realtime_start,attr"`
+	End   string `json:"realtime_end" xml:"realtime_end,attr"`
+	Date  string `json:"date" xml:"date,attr"`
+	Value string `json:"value" xml:"value,attr"`
+}
+
+type observationsResponse struct {
+	XMLName xml.Name `json:"-" xml:"observations"`
+	list
+	ObservationStart string            `json:"observation_start" xml:"observation_start,attr"`
+	ObservationEnd   string            `json:"observation_end" xml:"observation_end,attr"`
+	Units            string            `json:"units" xml:"units,attr"`
+	OutputType       int               `json:"output_type" xml:"output_type,attr"`
+	FileType         string            `json:"file_type" xml:"file_type,attr"`
+	Observations     []wireObservation `json:"observations" xml:"observation"`
+}
+
+// Observations per year of each frequency observations can be served in.
+var periods_per_year = map[string]int{"d": 260, "w": 52, "bw": 26, "m": 12, "q": 4, "sa": 2, "a": 1}
+
+// Aggregates observations of a monthly or lower frequency into periods of `months`
+// months, dated by their first day. Missing values are left out, a period
+// without any value is missing.
+func aggregate(observations []Observation, months int, method string) []Observation {
+	result := []Observation{}
+	count := 0
+	for _, obs := range observations {
+		start := time.Date(obs.Date.Year(), (obs.Date.Month()-1)/time.Month(months)*time.Month(months)+1, 1, 0, 0, 0, 0, time.UTC)
+		if n := len(result); n == 0 || !result[n-1].Date.Equal(start) {
+			result = append(result, Observation{Date: start, Missing: true})
+			count = 0
+		}
+		if obs.Missing {
+			continue
+		}
+
+		period := &result[len(result)-1]
+		switch method {
+		case "sum":
+			period.Value += obs.Value
+		case "eop":
+			period.Value = obs.Value
+		default:
+			period.Value = (period.Value*float64(count) + obs.Value) / float64(count+1)
+		}
+		period.Missing = false
+		count++
+	}
+	return result
+}
+
+// Transforms linear observations into the units, with the formulas FRED
+// documents. `per_year` is the number of observations a year.
+func transform(observations []Observation, units string, per_year int) []Observation {
+	lag := 1
+	if units == "ch1" || units == "pc1" {
+		lag = per_year
+	}
+
+	result := make([]Observation, len(observations))
+	for i, obs := range observations {
+		result[i] = Observation{Date: obs.Date, Missing: true}
+		if obs.Missing || (units != "log" && (i < lag || observations[i-lag].Missing)) {
+			continue
+		}
+
+		x, prev := obs.Value, 0.0
+		if units != "log" {
+			prev = observations[i-lag].Value
+		}
+		var value float64
+		switch units {
+		case "chg", "ch1":
+			value = x - prev
+		case "pch", "pc1":
+			value = (x/prev - 1) * 100
+		case "pca":
+			value = (math.Pow(x/prev, float64(per_year)) - 1) * 100
+		case "cch":
+			value = (math.Log(x) - math.Log(prev)) * 100
+		case "cca":
+			value = (math.Log(x) - math.Log(prev)) * 100 * float64(per_year)
+		case "log":
+			value = math.Log(x)
+		}
+		if !math.IsNaN(value) && !math.IsInf(value, 0) {
+			result[i].Value, result[i].Missing = value, false
+		}
+	}
+	return result
+}
+
+// Observations are aggregated to a lower frequency and transformed before being
+// narrowed down to the observation period, so year-ago values come from before it.
+// Vintage outputs are refused rather than computed.
+func get_series_observations(data Dataset, req request) (interface{}, *apiError) {
+	s, err := req.series(data)
+	if err != nil {
+		return nil, err
+	}
+	header, err := req.list(100000, "observation_date")
+	if err != nil {
+		return nil, err
+	}
+
+	start, err := req.date_param("observation_start", day("1776-07-04"))
+	if err != nil {
+		return nil, err
+	}
+	end, err := req.date_param("observation_end", day("9999-12-31"))
+	if err != nil {
+		return nil, err
+	}
+	units, err := req.one_of("units", "lin", "lin", "chg", "ch1", "pch", "pc1", "pca", "cch", "cca", "log")
+	if err != nil {
+		return nil, err
+	}
+	native := strings.ToLower(s.FrequencyShort)
+	frequency, err := req.one_of("frequency", native, "d", "w", "bw", "m", "q", "sa", "a")
+	if err != nil {
+		return nil, err
+	}
+	method, err := req.one_of("aggregation_method", "avg", "avg", "sum", "eop")
+	if err != nil {
+		return nil, err
+	}
+	if output, _ := req.one_of("output_type", "1", "1"); output != "1" || req.param("vintage_dates") != "" {
+		return nil, bad_request("fredtest only serves observations by realtime period.")
+	}
+
+	observations := s.Observations
+	if frequency != native {
+		if periods_per_year[frequency] > periods_per_year[native] {
+			return nil, bad_request("The value for variable frequency can not be higher than the native frequency of the series.")
+		}
+		observations = aggregate(observations, 12/periods_per_year[frequency], method)
+	}
+	if units != "lin" {
+		observations = transform(observations, units, periods_per_year[frequency])
+	}
+
+	matches := []Observation{}
+	for _, obs := range observations {
+		if !obs.Date.Before(start) && !obs.Date.After(end) {
+			matches = append(matches, obs)
+		}
+	}
+
+	result := observationsResponse{
+		list:             header,
+		ObservationStart: start.Format(DATE_FORMAT),
+		ObservationEnd:   end.Format(DATE_FORMAT),
+		Units:            units,
+		OutputType:       1,
+		FileType:         req.format,
+		Observations:     []wireObservation{},
+	}
+	from, to := result.page(len(matches),
+		func(i, j int) bool { return matches[i].Date.Before(matches[j].Date) },
+		func(i, j int) { matches[i], matches[j] = matches[j], matches[i] })
+	for _, obs := range matches[from:to] {
+		value := "."
+		if !obs.Missing {
+			value = strconv.FormatFloat(obs.Value, 'f', -1, 64)
+		}
+		result.Observations = append(result.Observations, wireObservation{
+			Start: header.Start,
+			End:   header.End,
+			Date:  obs.Date.Format(DATE_FORMAT),
+			Value: value,
+		})
+	}
+	return result, nil
+}
+
+//==============================================================================
+//
+// GET: /fred/series/release
+//
+//==============================================================================
+
+type wireRelease struct {
+	Id           uint   `json:"id" xml:"id,attr"`
+	Start        string `json:"realtime_start" xml:"realtime_start,attr"`
+	End          string `json:"realtime_end" xml:"realtime_end,attr"`
+	Name         string `json:"name" xml:"name,attr"`
+	PressRelease bool   `json:"press_release" xml:"press_release,attr"`
+	Link         string `json:"link,omitempty" xml:"link,attr,omitempty"`
+	Notes        string `json:"notes,omitempty" xml:"notes,attr,omitempty"`
+}
+
+type releasesResponse struct {
+	XMLName xml.Name `json:"-" xml:"releases"`
+	realtime
+	Releases []wireRelease `json:"releases" xml:"release"`
+}
+
+func get_series_release(data Dataset, req request) (interface{}, *apiError) {
+	s, err := req.series(data)
+	if err != nil {
+		return nil, err
+	}
+	period, err := req.realtime()
+	if err != nil {
+		return nil, err
+	}
+
+	result := releasesResponse{realtime: period, Releases: []wireRelease{}}
+	if r, exists := data.release(s.Release); exists {
+		result.Releases = append(result.Releases, r.wire(period))
+	}
+	return result, nil
+}
+
+//==============================================================================
+//
+// GET: /fred/series/search
+//
+//==============================================================================
+
+// Series matching the search text of the request, either all of its words in
+// the title, units, notes or tags (full text) or as a pattern on the ID.
+func (r request) search(data Dataset, param string) ([]Series, *apiError) {
+	text, err := r.required(param)
+	if err != nil {
+		return nil, err
+	}
+	ty, err := r.one_of("search_type", "full_text", "full_text", "series_id")
+	if err != nil {
+		return nil, err
+	}
+
+	matches := []Series{}
+	for _, s := range data.Series {
+		if ty == "series_id" {
+			if ok, _ := path.Match(strings.ToUpper(text), s.Id); ok || strings.Contains(s.Id, strings.ToUpper(text)) {
+				matches = append(matches, s)
+			}
+			continue
+		}
+
+		haystack := strings.ToLower(strings.Join(append([]string{s.Id, s.Title, s.Units, s.Notes}, s.Tags...), " "))
+		found := true
+		for _, word := range strings.Fields(strings.ToLower(text)) {
+			found = found && strings.Contains(haystack, word)
+		}
+		if found {
+			matches = append(matches, s)
+		}
+	}
+	return matches, nil
+}
+
+func get_series_search(data Dataset, req request) (interface{}, *apiError) {
+	matches, err := req.search(data, "search_text")
+	if err != nil {
+		return nil, err
+	}
+	return req.series_list(matches, append([]string{"search_rank"}, series_orders...)...)
+}
+
+//==============================================================================
+//
+// GET: /fred/series/search/tags
+//
+//==============================================================================
+
+func get_series_search_tags(data Dataset, req request) (interface{}, *apiError) {
+	matches, err := req.search(data, "series_search_text")
+	if err != nil {
+		return nil, err
+	}
+	return req.tag_list(data, matches, false, "tag_search_text")
+}
+
+//==============================================================================
+//
+// GET: /fred/series/search/related_tags
+//
+//==============================================================================
+
+func get_series_search_related_tags(data Dataset, req request) (interface{}, *apiError) {
+	matches, err := req.search(data, "series_search_text")
+	if err != nil {
+		return nil, err
+	}
+	return req.tag_list(data, matches, true, "tag_search_text")
+}
+
+//==============================================================================
+//
+// GET: /fred/series/tags
+//
+//==============================================================================
+
+func get_series_tags(data Dataset, req request) (interface{}, *apiError) {
+	s, err := req.series(data)
+	if err != nil {
+		return nil, err
+	}
+	return req.tag_list(data, []Series{s}, false, "")
+}
+
+//==============================================================================
+//
+// GET: /fred/series/updates
+//
+//==============================================================================
+
+func get_series_updates(data Dataset, req request) (interface{}, *apiError) {
+	filter, err := req.one_of("filter_value", "all", "all", "macro", "regional")
+	if err != nil {
+		return nil, err
+	}
+
+	// every series is national, so regional updates are always empty
+	candidates := data.Series
+	if filter == "regional" {
+		candidates = nil
+	}
+
+	result, err := req.series_list(candidates, "last_updated")
+	if err != nil {
+		return nil, err
+	}
+	result.FilterVariable, result.FilterValue = "geography", filter
+	return result, nil
+}
+
+//==============================================================================
+//
+// GET: /fred/series/vintagedates
+//
+//==============================================================================
+
+type vintageDatesResponse struct {
+	XMLName xml.Name `json:"-" xml:"vintage_dates"`
+	list
+	Dates []string `json:"vintage_dates" xml:"vintage_date"`
+}
+
+func get_series_vintage_dates(data Dataset, req request) (interface{}, *apiError) {
+	s, err := req.series(data)
+	if err != nil {
+		return nil, err
+	}
+	header, err := req.list(10000, "vintage_date")
+	if err != nil {
+		return nil, err
+	}
+
+	dates := append([]time.Time{}, s.VintageDates...)
+	result := vintageDatesResponse{list: header, Dates: []string{}}
+	from, to := result.page(len(dates),
+		func(i, j int) bool { return dates[i].Before(dates[j]) },
+		func(i, j int) { dates[i], dates[j] = dates[j], dates[i] })
+	for _, date := range dates[from:to] {
+		result.Dates = append(result.Dates, date.Format(DATE_FORMAT))
+	}
+	return result, nil
+}
@@ -0,0 +1,299 @@
+// Package fredtest provides an in-memory fake of the FRED API, to test code
+// using `gofred` without network access or a registered API key.
+//
+// The fake serves the category, release, series, source and tags endpoints as JSON
+// or XML from a `Dataset`, and validates requests the way FRED does:
+//
+//	server := fredtest.NewServer(fredtest.Sample())
+//	defer server.Close()
+//
+//	client, err := gofred.NewClient(fredtest.API_KEY, gofred.JSON, gofred.WithBaseURL(server.URL()))
+package fredtest
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// The key registered with a server unless others are given to `NewServer`.
+	API_KEY = "abcdefghijklmnopqrstuvwxyz012345"
+
+	DATE_FORMAT = "2006-01-02"
+	TIME_FORMAT = "2006-01-02 15:04:05-07"
+
+	// Path the endpoints are served under, as on the real API.
+	BASE_PATH = "/fred"
+)
+
+var api_key_format = regexp.MustCompile("^[a-z0-9]{32}$")
+
+//==============================================================================
+// server
+//==============================================================================
+
+// A running fake FRED API.
+type Server struct {
+	data   Dataset
+	keys   map[string]bool
+	server *httptest.Server
+
+	lock     sync.Mutex
+	requests int
+	failures []failure
+}
+
+type failure struct {
+	status      int
+	retry_after time.Duration
+}
+
+// Start a server serving the given dataset. Only the given API keys are accepted,
+// or `API_KEY` if none are given.
+func NewServer(data Dataset, keys ...string) *Server {
+	if len(keys) == 0 {
+		keys = []string{API_KEY}
+	}
+
+	s := &Server{data: data, keys: map[string]bool{}}
+	for _, key := range keys {
+		s.keys[key] = true
+	}
+	s.server = httptest.NewServer(http.HandlerFunc(s.serve))
+	return s
+}
+
+// The base URL of the fake API, to be given to `gofred.WithBaseURL`.
+func (s *Server) URL() string {
+	return s.server.URL + BASE_PATH
+}
+
+// An HTTP client configured to talk to the server.
+func (s *Server) Client() *http.Client {
+	return s.server.Client()
+}
+
+// Shut the server down, blocking until all outstanding requests have completed.
+func (s *Server) Close() {
+	s.server.Close()
+}
+
+// Number of requests received so far, including failed ones.
+func (s *Server) Requests() int {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.requests
+}
+
+// Fail the next `n` requests with the given status code, before any validation.
+//
+// A `Retry-After` header is sent with the failures if `retry_after` is positive,
+// e.g. to simulate FRED's rate limiting with `http.StatusTooManyRequests`.
+func (s *Server) FailNext(n int, status int, retry_after time.Duration) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	for i := 0; i < n; i++ {
+		s.failures = append(s.failures, failure{status, retry_after})
+	}
+}
+
+// Pops the next injected failure, if any, counting the request.
+func (s *Server) next_failure() (failure, bool) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.requests++
+
+	if len(s.failures) == 0 {
+		return failure{}, false
+	}
+	next := s.failures[0]
+	s.failures = s.failures[1:]
+	return next, true
+}
+
+//==============================================================================
+// requests
+//==============================================================================
+
+// Holds an incoming request and the response format it asked for.
+type request struct {
+	*http.Request
+	format string
+}
+
+// An error response, served with the status code as `error_code`.
+type apiError struct {
+	status  int
+	message string
+}
+
+func (e *apiError) Error() string {
+	return e.message
+}
+
+func bad_request(format string, args ...interface{}) *apiError {
+	return &apiError{http.StatusBadRequest, "Bad Request.  " + fmt.Sprintf(format, args...)}
+}
+
+func not_found(path string) *apiError {
+	return &apiError{http.StatusNotFound, fmt.Sprintf("Not Found.  The requested URL %s was not found on this server.", path)}
+}
+
+type endpoint func(Dataset, request) (interface{}, *apiError)
+
+var endpoints = map[string]endpoint{
+	"/category":              get_category,
+	"/category/children":     get_category_children,
+	"/category/related":      get_category_related,
+	"/category/series":       get_category_series,
+	"/category/tags":         get_category_tags,
+	"/category/related_tags": get_category_related_tags,
+
+	"/series":                     get_series,
+	"/series/categories":          get_series_categories,
+	"/series/observations":        get_series_observations,
+	"/series/release":             get_series_release,
+	"/series/search":              get_series_search,
+	"/series/search/tags":         get_series_search_tags,
+	"/series/search/related_tags": get_series_search_related_tags,
+	"/series/tags":                get_series_tags,
+	"/series/updates":             get_series_updates,
+	"/series/vintagedates":        get_series_vintage_dates,
+
+	"/releases":             get_releases,
+	"/releases/dates":       get_releases_dates,
+	"/release":              get_release,
+	"/release/dates":        get_release_dates,
+	"/release/series":       get_release_series,
+	"/release/sources":      get_release_sources,
+	"/release/tags":         get_release_tags,
+	"/release/related_tags": get_release_related_tags,
+	"/release/tables":       get_release_tables,
+
+	"/sources":         get_sources,
+	"/source":          get_source,
+	"/source/releases": get_source_releases,
+
+	"/tags":         get_tags,
+	"/related_tags": get_related_tags,
+	"/tags/series":  get_tags_series,
+}
+
+func (s *Server) serve(w http.ResponseWriter, r *http.Request) {
+	req := request{r, r.URL.Query().Get("file_type")}
+	if req.format == "" {
+		req.format = "xml" // FRED's default
+	}
+
+	if fail, ok := s.next_failure(); ok {
+		if fail.retry_after > 0 {
+			w.Header().Set("Retry-After", fmt.Sprint(int(fail.retry_after.Seconds())))
+		}
+		s.write_error(w, req, &apiError{fail.status, http.StatusText(fail.status) + "."})
+		return
+	}
+
+	if r.Method != http.MethodGet {
+		s.write_error(w, req, &apiError{http.StatusMethodNotAllowed, "Method Not Allowed."})
+		return
+	}
+
+	handler, exists := endpoints[trim_base(r.URL.Path)]
+	if !exists {
+		s.write_error(w, req, not_found(r.URL.Path))
+		return
+	}
+
+	if err := s.validate(req); err != nil {
+		s.write_error(w, req, err)
+		return
+	}
+
+	result, err := handler(s.data, req)
+	if err != nil {
+		s.write_error(w, req, err)
+		return
+	}
+	s.write(w, req, http.StatusOK, result)
+}
+
+func trim_base(path string) string {
+	if !strings.HasPrefix(path, BASE_PATH+"/") {
+		return "" // never an endpoint
+	}
+	return strings.TrimPrefix(path, BASE_PATH)
+}
+
+// Checks the parameters common to every endpoint. Errors about the format are
+// served as XML, like FRED does.
+func (s *Server) validate(req request) *apiError {
+	switch req.format {
+	case "json", "xml":
+	default:
+		return bad_request("Variable file_type is not one of the values: xml, json.")
+	}
+
+	key := req.URL.Query().Get("api_key")
+	switch {
+	case key == "":
+		return bad_request("Variable api_key is not set.")
+	case !api_key_format.MatchString(key):
+		return bad_request("The value for variable api_key is not a 32 character alpha-numeric lower-case string.")
+	case !s.keys[key]:
+		return bad_request("The value for variable api_key is not registered.")
+	}
+
+	return nil
+}
+
+//==============================================================================
+// responses
+//==============================================================================
+
+type jsonError struct {
+	Code    int    `json:"error_code"`
+	Message string `json:"error_message"`
+}
+
+type xmlError struct {
+	XMLName xml.Name `xml:"error"`
+	Code    int      `xml:"code,attr"`
+	Message string   `xml:"message,attr"`
+}
+
+func (s *Server) write_error(w http.ResponseWriter, req request, err *apiError) {
+	if req.format == "json" {
+		s.write(w, req, err.status, jsonError{err.status, err.message})
+	} else {
+		s.write(w, request{req.Request, "xml"}, err.status, xmlError{Code: err.status, Message: err.message})
+	}
+}
+
+func (s *Server) write(w http.ResponseWriter, req request, status int, body interface{}) {
+	var out []byte
+	var err error
+	if req.format == "json" {
+		w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+		out, err = json.Marshal(body)
+	} else {
+		w.Header().Set("Content-Type", "text/xml; charset=UTF-8")
+		out, err = xml.Marshal(body)
+		out = append([]byte(xml.Header), out...)
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Length", strconv.Itoa(len(out)))
+	w.WriteHeader(status)
+	w.Write(out)
+}
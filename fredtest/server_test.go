@@ -0,0 +1,229 @@
+package fredtest
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"io/ioutil"
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func get(t *testing.T, server *Server, path string, params url.Values) (*http.Response, []byte) {
+	res, err := server.Client().Get(server.URL() + path + "?" + params.Encode())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer res.Body.Close()
+
+	body, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return res, body
+}
+
+func params(format string, pairs ...string) url.Values {
+	v := url.Values{}
+	v.Set("api_key", API_KEY)
+	v.Set("file_type", format)
+	for i := 0; i+1 < len(pairs); i += 2 {
+		v.Set(pairs[i], pairs[i+1])
+	}
+	return v
+}
+
+func TestServer_Category(t *testing.T) {
+	server := NewServer(Sample())
+	defer server.Close()
+
+	res, body := get(t, server, "/category", params("json", "category_id", "125"))
+	if res.StatusCode != http.StatusOK {
+		t.Fatalf("expected status 200, got: %d %s", res.StatusCode, body)
+	}
+	var js categoriesResponse
+	if err := json.Unmarshal(body, &js); err != nil {
+		t.Fatal(err)
+	}
+	expect := wireCategory{125, "Trade Balance", 13}
+	if len(js.Categories) != 1 || js.Categories[0] != expect {
+		t.Errorf("expected %+v, got: %s", expect, body)
+	}
+
+	res, body = get(t, server, "/category", params("xml", "category_id", "125"))
+	var x categoriesResponse
+	if err := xml.Unmarshal(body, &x); err != nil {
+		t.Fatal(err)
+	}
+	if len(x.Categories) != 1 || x.Categories[0] != expect {
+		t.Errorf("expected %+v, got: %s", expect, body)
+	}
+}
+
+func TestServer_Errors(t *testing.T) {
+	server := NewServer(Sample())
+	defer server.Close()
+
+	no_key := params("json")
+	no_key.Del("api_key")
+	bad_format := params("csv")
+	tests := []struct {
+		path   string
+		params url.Values
+		status int
+	}{
+		{"/category", no_key, http.StatusBadRequest},
+		{"/category", params("json", "api_key", "ABCDEFGHIJKLMNOPQRSTUVWXYZ012345"), http.StatusBadRequest},
+		{"/category", params("json", "api_key", "zyxwvutsrqponmlkjihgfedcba012345"), http.StatusBadRequest},
+		{"/category", bad_format, http.StatusBadRequest},
+		{"/series/observations", params("xlsx", "series_id", "GNPCA"), http.StatusBadRequest},
+		{"/category", params("json", "category_id", "999999"), http.StatusBadRequest},
+		{"/series", params("json"), http.StatusBadRequest},
+		{"/series/observations", params("json", "series_id", "GNPCA", "units", "pc2"), http.StatusBadRequest},
+		{"/series/observations", params("json", "series_id", "GNPCA", "frequency", "q"), http.StatusBadRequest},
+		{"/series/observations", params("json", "series_id", "GNPCA", "output_type", "2"), http.StatusBadRequest},
+		{"/category/series", params("json", "category_id", "125", "order_by", "colour"), http.StatusBadRequest},
+		{"/release", params("json"), http.StatusBadRequest},
+		{"/release", params("xml", "release_id", "999999"), http.StatusBadRequest},
+		{"/release/tables", params("json", "release_id", "53", "include_observation_values", "true"), http.StatusBadRequest},
+		{"/source", params("json", "source_id", "999999"), http.StatusBadRequest},
+		{"/source/releases", params("xml"), http.StatusBadRequest},
+		{"/releases/everything", params("json"), http.StatusNotFound},
+	}
+
+	for _, test := range tests {
+		res, body := get(t, server, test.path, test.params)
+		if res.StatusCode != test.status {
+			t.Errorf("%s %v: expected status %d, got: %d", test.path, test.params, test.status, res.StatusCode)
+			continue
+		}
+
+		if test.params.Get("file_type") == "json" {
+			var e jsonError
+			if err := json.Unmarshal(body, &e); err != nil || e.Code != test.status || e.Message == "" {
+				t.Errorf("%s: expected a json error, got: %s", test.path, body)
+			}
+		} else {
+			var e xmlError
+			if err := xml.Unmarshal(body, &e); err != nil || e.Code != test.status || e.Message == "" {
+				t.Errorf("%s: expected an xml error, got: %s", test.path, body)
+			}
+		}
+	}
+}
+
+func TestServer_FailNext(t *testing.T) {
+	server := NewServer(Sample())
+	defer server.Close()
+
+	server.FailNext(2, http.StatusTooManyRequests, 3*time.Second)
+	for i := 0; i < 2; i++ {
+		res, _ := get(t, server, "/category", params("json"))
+		if res.StatusCode != http.StatusTooManyRequests {
+			t.Errorf("expected status 429, got: %d", res.StatusCode)
+		}
+		if res.Header.Get("Retry-After") != "3" {
+			t.Errorf("expected a Retry-After of 3 seconds, got: %s", res.Header.Get("Retry-After"))
+		}
+	}
+
+	if res, body := get(t, server, "/category", params("json")); res.StatusCode != http.StatusOK {
+		t.Errorf("expected the failures to be used up, got: %d %s", res.StatusCode, body)
+	}
+	if server.Requests() != 3 {
+		t.Errorf("expected 3 requests, got: %d", server.Requests())
+	}
+}
+
+func TestServer_Lists(t *testing.T) {
+	server := NewServer(Sample())
+	defer server.Close()
+
+	_, body := get(t, server, "/category/series",
+		params("json", "category_id", "125", "order_by", "title", "sort_order", "desc", "limit", "5", "offset", "2"))
+	var series seriesList
+	if err := json.Unmarshal(body, &series); err != nil {
+		t.Fatal(err)
+	}
+	if len(series.Series) != 5 || series.Count != 18 || series.Offset != 2 {
+		t.Fatalf("expected 5 of 18 series from offset 2, got: %s", body)
+	}
+	for i := 1; i < len(series.Series); i++ {
+		if series.Series[i].Title > series.Series[i-1].Title {
+			t.Errorf("expected titles in descending order, got '%s' after '%s'", series.Series[i].Title, series.Series[i-1].Title)
+		}
+	}
+
+	_, body = get(t, server, "/series/observations",
+		params("xml", "series_id", "GNPCA", "observation_start", "2000-01-01", "observation_end", "2009-12-31"))
+	var obs observationsResponse
+	if err := xml.Unmarshal(body, &obs); err != nil {
+		t.Fatal(err)
+	}
+	if len(obs.Observations) != 10 || obs.Observations[0].Date != "2000-01-01" {
+		t.Errorf("expected the 10 observations of the 2000s, got: %s", body)
+	}
+
+	_, body = get(t, server, "/related_tags", params("json", "tag_names", "trade;quarterly"))
+	var tags tagList
+	if err := json.Unmarshal(body, &tags); err != nil {
+		t.Fatal(err)
+	}
+	for _, tag := range tags.Tags {
+		if tag.Name == "trade" || tag.Name == "quarterly" || tag.SeriesCount != 1 {
+			t.Errorf("expected only tags related to the one quarterly trade series, got: %+v", tag)
+		}
+	}
+	if len(tags.Tags) == 0 {
+		t.Errorf("expected related tags, got: %s", body)
+	}
+}
+
+func TestServer_ObservationsAggregated(t *testing.T) {
+	server := NewServer(Sample())
+	defer server.Close()
+
+	exjpus, _ := Sample().series("EXJPUS")
+	first_year := exjpus.Observations[:12]
+	sum := 0.0
+	for _, obs := range first_year {
+		sum += obs.Value
+	}
+
+	tests := []struct {
+		method, units string
+		expect        float64
+	}{
+		{"sum", "lin", sum},
+		{"eop", "lin", first_year[11].Value},
+		{"avg", "log", math.Log(sum / 12)},
+	}
+	for _, test := range tests {
+		_, body := get(t, server, "/series/observations", params("json", "series_id", "EXJPUS", "frequency", "a",
+			"aggregation_method", test.method, "units", test.units, "observation_end", "1972-12-31"))
+		var obs observationsResponse
+		if err := json.Unmarshal(body, &obs); err != nil {
+			t.Fatal(err)
+		}
+		if len(obs.Observations) != 2 || obs.Observations[0].Date != "1971-01-01" || obs.Units != test.units {
+			t.Fatalf("%s: expected 2 annual observations, got: %s", test.method, body)
+		}
+		value, err := strconv.ParseFloat(obs.Observations[0].Value, 64)
+		if err != nil || math.Abs(value-test.expect) > 1e-9 {
+			t.Errorf("%s, %s: expected %v, got: %s", test.method, test.units, test.expect, obs.Observations[0].Value)
+		}
+	}
+
+	// changes from a year ago are missing without a value a year before
+	_, body := get(t, server, "/series/observations", params("xml", "series_id", "GNPCA", "units", "pc1", "observation_end", "1930-01-01"))
+	var obs observationsResponse
+	if err := xml.Unmarshal(body, &obs); err != nil {
+		t.Fatal(err)
+	}
+	if len(obs.Observations) != 2 || obs.Observations[0].Value != "." || obs.Observations[1].Value == "." {
+		t.Errorf("expected the first change to be missing, got: %s", body)
+	}
+}
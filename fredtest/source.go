@@ -0,0 +1,117 @@
+package fredtest
+
+import (
+	"encoding/xml"
+)
+
+type wireSource struct {
+	Id    uint   `json:"id" xml:"id,attr"`
+	Start string `json:"realtime_start" xml:"realtime_start,attr"`
+	End   string `json:"realtime_end" xml:"realtime_end,attr"`
+	Name  string `json:"name" xml:"name,attr"`
+	Link  string `json:"link" xml:"link,attr"`
+	Notes string `json:"notes" xml:"notes,attr"`
+}
+
+type sourcesResponse struct {
+	XMLName xml.Name `json:"-" xml:"sources"`
+	realtime
+	Sources []wireSource `json:"sources" xml:"source"`
+}
+
+func (s Source) wire(period realtime) wireSource {
+	return wireSource{s.Id, period.Start, period.End, s.Name, s.Link, s.Notes}
+}
+
+// The source named by the `source_id` parameter.
+func (r request) source(data Dataset) (Source, *apiError) {
+	if _, err := r.required("source_id"); err != nil {
+		return Source{}, err
+	}
+	id, err := r.uint_param("source_id", 0)
+	if err != nil {
+		return Source{}, err
+	}
+
+	source, exists := data.source(id)
+	if !exists {
+		return Source{}, bad_request("The source does not exist.")
+	}
+	return source, nil
+}
+
+//==============================================================================
+//
+// GET: /fred/sources
+//
+//==============================================================================
+
+type sourceList struct {
+	XMLName xml.Name `json:"-" xml:"sources"`
+	list
+	Sources []wireSource `json:"sources" xml:"source"`
+}
+
+func source_less(order string, a, b Source) bool {
+	if order == "name" {
+		return a.Name < b.Name
+	}
+	return a.Id < b.Id
+}
+
+func get_sources(data Dataset, req request) (interface{}, *apiError) {
+	header, err := req.list(1000, "source_id", "name", "realtime_start", "realtime_end")
+	if err != nil {
+		return nil, err
+	}
+
+	matches := append([]Source{}, data.Sources...)
+	result := sourceList{list: header, Sources: []wireSource{}}
+	from, to := result.page(len(matches),
+		func(i, j int) bool { return source_less(result.Order, matches[i], matches[j]) },
+		func(i, j int) { matches[i], matches[j] = matches[j], matches[i] })
+	for _, source := range matches[from:to] {
+		result.Sources = append(result.Sources, source.wire(result.realtime))
+	}
+	return result, nil
+}
+
+//==============================================================================
+//
+// GET: /fred/source
+//
+//==============================================================================
+
+func get_source(data Dataset, req request) (interface{}, *apiError) {
+	source, err := req.source(data)
+	if err != nil {
+		return nil, err
+	}
+	period, err := req.realtime()
+	if err != nil {
+		return nil, err
+	}
+
+	return sourcesResponse{realtime: period, Sources: []wireSource{source.wire(period)}}, nil
+}
+
+//==============================================================================
+//
+// GET: /fred/source/releases
+//
+//==============================================================================
+
+func get_source_releases(data Dataset, req request) (interface{}, *apiError) {
+	source, err := req.source(data)
+	if err != nil {
+		return nil, err
+	}
+
+	releases := []Release{}
+	for _, release := range data.Releases {
+		if release.has_source(source.Id) {
+			releases = append(releases, release)
+		}
+	}
+	return req.release_list(releases)
+}
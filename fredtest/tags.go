@@ -0,0 +1,147 @@
+package fredtest
+
+import (
+	"encoding/xml"
+	"strings"
+)
+
+type wireTag struct {
+	Name        string `json:"name" xml:"name,attr"`
+	GroupId     string `json:"group_id" xml:"group_id,attr"`
+	Notes       string `json:"notes" xml:"notes,attr"`
+	Created     string `json:"created" xml:"created,attr"`
+	Popularity  uint   `json:"popularity" xml:"popularity,attr"`
+	SeriesCount int    `json:"series_count" xml:"series_count,attr"`
+}
+
+type tagList struct {
+	XMLName xml.Name `json:"-" xml:"tags"`
+	list
+	Tags []wireTag `json:"tags" xml:"tag"`
+}
+
+var tag_orders = []string{"series_count", "popularity", "created", "name", "group_id"}
+
+func tag_less(order string, a, b wireTag) bool {
+	switch order {
+	case "series_count":
+		return a.SeriesCount < b.SeriesCount
+	case "popularity":
+		return a.Popularity < b.Popularity
+	case "created":
+		return a.Created < b.Created
+	case "group_id":
+		return a.GroupId < b.GroupId
+	}
+	return a.Name < b.Name
+}
+
+// Lists the tags of the given series, counting the series each is on.
+//
+// For related tags the series are first narrowed down to those having every tag
+// of `tag_names` and none of `exclude_tag_names`, which are then left out of the
+// result. Otherwise `tag_names` restricts the result to the named tags.
+func (r request) tag_list(data Dataset, candidates []Series, related bool, search_param string) (tagList, *apiError) {
+	header, err := r.list(1000, tag_orders...)
+	if err != nil {
+		return tagList{}, err
+	}
+	group, err := r.one_of("tag_group_id", "", "freq", "gen", "geo", "geot", "rls", "seas", "src", "cc")
+	if err != nil {
+		return tagList{}, err
+	}
+	search := ""
+	if search_param != "" {
+		search = strings.ToLower(r.param(search_param))
+	}
+
+	names, exclude := r.tag_names("tag_names"), r.tag_names("exclude_tag_names")
+	skip := map[string]bool{}
+	only := map[string]bool{}
+	if related {
+		if len(names) == 0 {
+			return tagList{}, bad_request("Variable tag_names is not set.")
+		}
+		candidates = with_tags(candidates, names, exclude)
+		for _, name := range append(names, exclude...) {
+			skip[name] = true
+		}
+	} else {
+		for _, name := range names {
+			only[name] = true
+		}
+	}
+
+	counts := map[string]int{}
+	matches := []wireTag{}
+	for _, s := range candidates {
+		for _, name := range s.Tags {
+			if skip[name] || (len(only) > 0 && !only[name]) {
+				continue
+			}
+
+			tag := data.tag(name)
+			if group != "" && tag.GroupId != group {
+				continue
+			}
+			if search != "" && !strings.Contains(strings.ToLower(tag.Name+" "+tag.Notes), search) {
+				continue
+			}
+
+			if counts[name] == 0 {
+				matches = append(matches, wireTag{
+					Name:       tag.Name,
+					GroupId:    tag.GroupId,
+					Notes:      tag.Notes,
+					Created:    tag.Created.Format(TIME_FORMAT),
+					Popularity: tag.Popularity,
+				})
+			}
+			counts[name]++
+		}
+	}
+	for i := range matches {
+		matches[i].SeriesCount = counts[matches[i].Name]
+	}
+
+	result := tagList{list: header, Tags: []wireTag{}}
+	from, to := result.page(len(matches),
+		func(i, j int) bool { return tag_less(result.Order, matches[i], matches[j]) },
+		func(i, j int) { matches[i], matches[j] = matches[j], matches[i] })
+	result.Tags = append(result.Tags, matches[from:to]...)
+	return result, nil
+}
+
+//==============================================================================
+//
+// GET: /fred/tags
+//
+//==============================================================================
+
+func get_tags(data Dataset, req request) (interface{}, *apiError) {
+	return req.tag_list(data, data.Series, false, "search_text")
+}
+
+//==============================================================================
+//
+// GET: /fred/related_tags
+//
+//==============================================================================
+
+func get_related_tags(data Dataset, req request) (interface{}, *apiError) {
+	return req.tag_list(data, data.Series, true, "search_text")
+}
+
+//==============================================================================
+//
+// GET: /fred/tags/series
+//
+//==============================================================================
+
+func get_tags_series(data Dataset, req request) (interface{}, *apiError) {
+	names := req.tag_names("tag_names")
+	if len(names) == 0 {
+		return nil, bad_request("Variable tag_names is not set.")
+	}
+	return req.series_list(data.Series, series_orders...)
+}
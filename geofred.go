@@ -0,0 +1,650 @@
+package gofred
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+//==============================================================================
+// GeoFRED (Maps API)
+//==============================================================================
+
+// Kind of region the Maps API reports values for.
+type RegionType string
+
+const (
+	RegionBEA            RegionType = "bea"
+	RegionMSA            RegionType = "msa"
+	RegionFRB            RegionType = "frb"
+	RegionNECTA          RegionType = "necta"
+	RegionState          RegionType = "state"
+	RegionCountry        RegionType = "country"
+	RegionCounty         RegionType = "county"
+	RegionCensusRegion   RegionType = "censusregion"
+	RegionCensusDivision RegionType = "censusdivision"
+)
+
+// Checks the region type is one the Maps API knows.
+func (r RegionType) validate() error {
+	switch r {
+	case RegionBEA, RegionMSA, RegionFRB, RegionNECTA, RegionState, RegionCountry,
+		RegionCounty, RegionCensusRegion, RegionCensusDivision:
+		return nil
+	}
+	return fmt.Errorf("unknown region type '%s'", string(r))
+}
+
+// Base URL of the Maps API, a sibling of the FRED API under the client's base URL.
+//
+// `https://api.stlouisfed.org/fred` maps to `https://api.stlouisfed.org/geofred`,
+// a base URL not ending in `/fred` gets `/geofred` appended.
+func (c Client) maps_url() url.URL {
+	maps_url := c.base_url
+	maps_url.Path = strings.TrimSuffix(maps_url.Path, "/fred") + "/geofred"
+	return maps_url
+}
+
+//==============================================================================
+//
+// GET: /geofred/series/group
+//
+//==============================================================================
+
+// The group of regional series a series belongs to, e.g. per capita personal
+// income by state.
+type SeriesGroup struct {
+	Id         string     `json:"series_group" xml:"series_group,attr"`
+	Title      string     `json:"title" xml:"title,attr"`
+	RegionType RegionType `json:"region_type" xml:"region_type,attr"`
+	Season     string     `json:"season" xml:"season,attr"`
+	Units      string     `json:"units" xml:"units,attr"`
+	Frequency  Frequency  `json:"frequency" xml:"frequency,attr"`
+	MinDate    Date       `json:"min_date" xml:"min_date,attr"`
+	MaxDate    Date       `json:"max_date" xml:"max_date,attr"`
+}
+
+// Holds the data needed to request the regional group or data of a series.
+type GeoSeriesRequest struct {
+	baseRequest
+	Series string
+
+	// Only used by `GeoSeriesData`, the latest date is used if unset.
+	Date time.Time
+	// Only used by `GeoSeriesData`, request every date from this one through `Date`.
+	StartDate time.Time
+}
+
+func NewGeoSeriesRequest(series string) GeoSeriesRequest {
+	return GeoSeriesRequest{
+		Series: series,
+	}
+}
+
+// Satisfies the `Request` interface.
+func (r GeoSeriesRequest) ToParams() url.Values {
+	v := r.baseRequest.ToParams()
+	v.Set("series_id", r.Series)
+	if !r.Date.IsZero() {
+		v.Set("date", r.Date.Format(DATE_FORMAT))
+	}
+	if !r.StartDate.IsZero() {
+		v.Set("start_date", r.StartDate.Format(DATE_FORMAT))
+	}
+	return v
+}
+
+// Satisfies the `Request` interface.
+func (r GeoSeriesRequest) Validate() error {
+	if len(r.Series) == 0 {
+		return fmt.Errorf("no series id given")
+	}
+	if !r.StartDate.IsZero() && !r.Date.IsZero() && r.Date.Before(r.StartDate) {
+		return fmt.Errorf("date %s is before start date %s",
+			r.Date.Format(DATE_FORMAT), r.StartDate.Format(DATE_FORMAT))
+	}
+	return nil
+}
+
+type seriesGroupResponse struct {
+	Group SeriesGroup `json:"series_group" xml:"series_group"`
+}
+
+// Get the `SeriesGroup` of a regional series, which can be passed to `GeoRegionalData`.
+func (c Client) GeoSeriesGroup(req GeoSeriesRequest) (SeriesGroup, Error) {
+	return c.GeoSeriesGroupContext(context.Background(), req)
+}
+
+// Same as `GeoSeriesGroup`, but the request is bound to the given context.
+func (c Client) GeoSeriesGroupContext(ctx context.Context, req GeoSeriesRequest) (SeriesGroup, Error) {
+	req.baseRequest = c.base_req
+	req_url := c.maps_url()
+	req_url.RawQuery = req.ToParams().Encode()
+	req_url.Path = fmt.Sprintf("%s/series/group", req_url.Path)
+	if err := req.Validate(); err != nil {
+		return SeriesGroup{}, c.invalid_request(req_url.String(), "series group", err)
+	}
+
+	body, err := c.get(ctx, "series group", req_url.String())
+	if err != nil {
+		return SeriesGroup{}, err.Prefixf("error getting group of series %s:", req.Series)
+	}
+
+	// parse the correct format
+	var result seriesGroupResponse
+	err = c.unmarshal_body(req_url.String(), body, &result)
+	if err != nil {
+		return SeriesGroup{}, err.Prefixf("could not get group of series %s:", req.Series)
+	}
+
+	return result.Group, nil
+}
+
+//==============================================================================
+// regional data
+//==============================================================================
+
+// The value of a single region.
+type RegionalValue struct {
+	Region string
+	Code   string
+	Series string
+	Value  float64
+	Valid  bool // false if the value is missing
+}
+
+// Values are strings in some responses and numbers in others.
+func (v *RegionalValue) UnmarshalJSON(input []byte) error {
+	var raw struct {
+		Region string          `json:"region"`
+		Code   json.RawMessage `json:"code"`
+		Series string          `json:"series_id"`
+		Value  json.RawMessage `json:"value"`
+	}
+	if err := json.Unmarshal(input, &raw); err != nil {
+		return err
+	}
+
+	*v = RegionalValue{
+		Region: raw.Region,
+		Code:   strings.Trim(string(raw.Code), `"`),
+		Series: raw.Series,
+	}
+	return v.parse_value(strings.Trim(string(raw.Value), `"`))
+}
+
+func (v *RegionalValue) parse_value(value string) error {
+	if len(value) == 0 || value == "null" {
+		value = "."
+	}
+
+	point, err := parse_data_point(Date{}, value)
+	if err != nil {
+		return fmt.Errorf("region %s: %v", v.Code, err)
+	}
+	v.Value, v.Valid = point.Value, point.Valid
+	return nil
+}
+
+// The values of every region on a single date.
+type RegionalCrossSection struct {
+	Date   Date
+	Values map[string]RegionalValue // by region code
+}
+
+// Regional values across one or more dates.
+type RegionalData struct {
+	Title       string
+	Region      RegionType
+	Seasonality string
+	Units       string
+	Frequency   string
+
+	Dates []RegionalCrossSection // oldest first
+}
+
+// The values of every region on the given date, by region code.
+func (d RegionalData) On(date time.Time) (map[string]RegionalValue, bool) {
+	i := sort.Search(len(d.Dates), func(i int) bool {
+		return !time.Time(d.Dates[i].Date).Before(date)
+	})
+	if i == len(d.Dates) || !time.Time(d.Dates[i].Date).Equal(date) {
+		return nil, false
+	}
+	return d.Dates[i].Values, true
+}
+
+// The values of every region on the latest date, by region code.
+func (d RegionalData) Latest() (map[string]RegionalValue, bool) {
+	if len(d.Dates) == 0 {
+		return nil, false
+	}
+	return d.Dates[len(d.Dates)-1].Values, true
+}
+
+// Adds a value on the given date, keeping dates sorted.
+func (d *RegionalData) add(date Date, value RegionalValue) {
+	i := sort.Search(len(d.Dates), func(i int) bool {
+		return !time.Time(d.Dates[i].Date).Before(time.Time(date))
+	})
+	if i == len(d.Dates) || !time.Time(d.Dates[i].Date).Equal(time.Time(date)) {
+		d.Dates = append(d.Dates, RegionalCrossSection{})
+		copy(d.Dates[i+1:], d.Dates[i:])
+		d.Dates[i] = RegionalCrossSection{Date: date, Values: map[string]RegionalValue{}}
+	}
+	d.Dates[i].Values[value.Code] = value
+}
+
+// The data is nested in `meta`, keyed on date.
+func (d *RegionalData) UnmarshalJSON(input []byte) error {
+	var raw struct {
+		Meta struct {
+			Title       string                     `json:"title"`
+			Region      RegionType                 `json:"region"`
+			Seasonality string                     `json:"seasonality"`
+			Units       string                     `json:"units"`
+			Frequency   string                     `json:"frequency"`
+			Data        map[string][]RegionalValue `json:"data"`
+		} `json:"meta"`
+	}
+	if err := json.Unmarshal(input, &raw); err != nil {
+		return err
+	}
+
+	*d = RegionalData{
+		Title:       raw.Meta.Title,
+		Region:      raw.Meta.Region,
+		Seasonality: raw.Meta.Seasonality,
+		Units:       raw.Meta.Units,
+		Frequency:   raw.Meta.Frequency,
+		Dates:       []RegionalCrossSection{},
+	}
+	for date_str, values := range raw.Meta.Data {
+		var date Date
+		if err := date.UnmarshalText([]byte(date_str)); err != nil {
+			return err
+		}
+		for _, value := range values {
+			d.add(date, value)
+		}
+	}
+	return nil
+}
+
+// Regional values are elements with a `code` attribute, dated by their closest
+// enclosing element (`meta` included) with a `date` attribute.
+func (d *RegionalData) UnmarshalXML(dec *xml.Decoder, start xml.StartElement) error {
+	*d = RegionalData{Dates: []RegionalCrossSection{}}
+
+	dates := []Date{{}}
+	for tok := xml.Token(start); ; {
+		switch el := tok.(type) {
+		case xml.StartElement:
+			attrs := make(map[string]string, len(el.Attr))
+			for _, attr := range el.Attr {
+				attrs[attr.Name.Local] = attr.Value
+			}
+
+			if el.Name.Local == "meta" {
+				d.Title = attrs["title"]
+				d.Region = RegionType(attrs["region"])
+				d.Seasonality = attrs["seasonality"]
+				d.Units = attrs["units"]
+				d.Frequency = attrs["frequency"]
+			}
+
+			date := dates[len(dates)-1]
+			if date_str, exists := attrs["date"]; exists {
+				if err := date.UnmarshalText([]byte(date_str)); err != nil {
+					return err
+				}
+			}
+			dates = append(dates, date)
+
+			if code, exists := attrs["code"]; exists {
+				value := RegionalValue{Region: attrs["region"], Code: code, Series: attrs["series_id"]}
+				if err := value.parse_value(attrs["value"]); err != nil {
+					return err
+				}
+				if time.Time(date).IsZero() {
+					return fmt.Errorf("no date for region %s", code)
+				}
+				d.add(date, value)
+			}
+
+		case xml.EndElement:
+			dates = dates[:len(dates)-1]
+			if len(dates) == 1 {
+				return nil
+			}
+		}
+
+		var err error
+		if tok, err = dec.Token(); err != nil {
+			return err
+		}
+	}
+}
+
+//==============================================================================
+//
+// GET: /geofred/series/data
+//
+//==============================================================================
+
+// Get the regional cross-section(s) of the group a series belongs to.
+func (c Client) GeoSeriesData(req GeoSeriesRequest) (RegionalData, Error) {
+	return c.GeoSeriesDataContext(context.Background(), req)
+}
+
+// Same as `GeoSeriesData`, but the request is bound to the given context.
+func (c Client) GeoSeriesDataContext(ctx context.Context, req GeoSeriesRequest) (RegionalData, Error) {
+	req.baseRequest = c.base_req
+	req_url := c.maps_url()
+	req_url.RawQuery = req.ToParams().Encode()
+	req_url.Path = fmt.Sprintf("%s/series/data", req_url.Path)
+	if err := req.Validate(); err != nil {
+		return RegionalData{}, c.invalid_request(req_url.String(), "series data", err)
+	}
+
+	body, err := c.get(ctx, "series data", req_url.String())
+	if err != nil {
+		return RegionalData{}, err.Prefixf("error getting regional data of series %s:", req.Series)
+	}
+
+	// parse the correct format
+	var result RegionalData
+	err = c.unmarshal_body(req_url.String(), body, &result)
+	if err != nil {
+		return RegionalData{}, err.Prefixf("could not get regional data of series %s:", req.Series)
+	}
+
+	return result, nil
+}
+
+//==============================================================================
+//
+// GET: /geofred/regional/data
+//
+//==============================================================================
+
+// Holds the data needed to request the values of a series group across regions.
+type RegionalDataRequest struct {
+	baseRequest
+
+	SeriesGroup string
+	RegionType  RegionType
+	Date        time.Time
+	StartDate   time.Time // request every date from this one through `Date`
+	Season      string    // e.g. "SA", "NSA"
+	Units       string
+
+	Transformation UnitType
+
+	// Aggregate to a lower frequency, both must be set together. Since `Daily`
+	// is the zero value, a frequency is only sent along with a method.
+	Frequency   Frequency
+	Aggregation AggregationMethod
+}
+
+func NewRegionalDataRequest(group SeriesGroup, date time.Time) RegionalDataRequest {
+	return RegionalDataRequest{
+		SeriesGroup: group.Id,
+		RegionType:  group.RegionType,
+		Date:        date,
+		Season:      group.Season,
+		Units:       group.Units,
+	}
+}
+
+// Satisfies the `Request` interface.
+func (r RegionalDataRequest) ToParams() url.Values {
+	v := r.baseRequest.ToParams()
+	v.Set("series_group", r.SeriesGroup)
+	v.Set("region_type", string(r.RegionType))
+	v.Set("date", r.Date.Format(DATE_FORMAT))
+	if !r.StartDate.IsZero() {
+		v.Set("start_date", r.StartDate.Format(DATE_FORMAT))
+	}
+	v.Set("season", r.Season)
+	v.Set("units", r.Units)
+
+	if r.Transformation != UnitLinear {
+		v.Set("transformation", r.Transformation.ShortString())
+	}
+	if len(r.Aggregation) > 0 {
+		v.Set("frequency", r.Frequency.String())
+		v.Set("aggregation_method", string(r.Aggregation))
+	}
+	return v
+}
+
+// Satisfies the `Request` interface.
+func (r RegionalDataRequest) Validate() error {
+	if len(r.SeriesGroup) == 0 {
+		return fmt.Errorf("no series group given")
+	}
+	if r.Date.IsZero() {
+		return fmt.Errorf("no date given")
+	}
+	if !r.StartDate.IsZero() && r.Date.Before(r.StartDate) {
+		return fmt.Errorf("date %s is before start date %s",
+			r.Date.Format(DATE_FORMAT), r.StartDate.Format(DATE_FORMAT))
+	}
+	if len(r.Season) == 0 || len(r.Units) == 0 {
+		return fmt.Errorf("both a season and units must be given")
+	}
+	if r.Transformation > UnitNaturalLog {
+		return fmt.Errorf("unknown transformation %d", r.Transformation)
+	}
+
+	switch r.Aggregation {
+	case "":
+		if r.Frequency != Daily {
+			return fmt.Errorf("frequency %s given without an aggregation method", r.Frequency.LongString())
+		}
+	case AggregateAverage, AggregateSum, AggregateEndOfPeriod:
+		if r.Frequency >= UnknownFrequency {
+			return fmt.Errorf("cannot aggregate to an unknown frequency")
+		}
+	default:
+		return fmt.Errorf("unknown aggregation method '%s'", r.Aggregation)
+	}
+
+	return r.RegionType.validate()
+}
+
+// Get the values of a series group across every region of the given type.
+func (c Client) GeoRegionalData(req RegionalDataRequest) (RegionalData, Error) {
+	return c.GeoRegionalDataContext(context.Background(), req)
+}
+
+// Same as `GeoRegionalData`, but the request is bound to the given context.
+func (c Client) GeoRegionalDataContext(ctx context.Context, req RegionalDataRequest) (RegionalData, Error) {
+	req.baseRequest = c.base_req
+	req_url := c.maps_url()
+	req_url.RawQuery = req.ToParams().Encode()
+	req_url.Path = fmt.Sprintf("%s/regional/data", req_url.Path)
+	if err := req.Validate(); err != nil {
+		return RegionalData{}, c.invalid_request(req_url.String(), "regional data", err)
+	}
+
+	body, err := c.get(ctx, "regional data", req_url.String())
+	if err != nil {
+		return RegionalData{}, err.Prefixf("error getting regional data of group %s:", req.SeriesGroup)
+	}
+
+	// parse the correct format
+	var result RegionalData
+	err = c.unmarshal_body(req_url.String(), body, &result)
+	if err != nil {
+		return RegionalData{}, err.Prefixf("could not get regional data of group %s:", req.SeriesGroup)
+	}
+
+	return result, nil
+}
+
+//==============================================================================
+//
+// GET: /geofred/shapes/file
+//
+//==============================================================================
+
+// Property regional values are joined on when none is given to `Join`.
+const SHAPE_CODE_PROPERTY = "code"
+
+// A GeoJSON geometry, whose coordinates are left for the consumer to interpret.
+type GeoJSONGeometry struct {
+	Type        string            `json:"type"`
+	Coordinates json.RawMessage   `json:"coordinates,omitempty"`
+	Geometries  []GeoJSONGeometry `json:"geometries,omitempty"`
+}
+
+// A GeoJSON feature, i.e. the boundary of a single region.
+type GeoJSONFeature struct {
+	Type       string                 `json:"type"`
+	Id         interface{}            `json:"id,omitempty"`
+	Geometry   *GeoJSONGeometry       `json:"geometry"`
+	Properties map[string]interface{} `json:"properties"`
+}
+
+// A GeoJSON feature collection, marshaling back to valid GeoJSON.
+type GeoJSONFeatureCollection struct {
+	Type     string           `json:"type"`
+	Features []GeoJSONFeature `json:"features"`
+}
+
+// Normalizes a region code so numeric codes match whatever their padding, e.g. "01" and 1.
+func region_code(code interface{}) string {
+	str := fmt.Sprint(code)
+	if trimmed := strings.TrimLeft(str, "0"); len(trimmed) > 0 {
+		return trimmed
+	}
+	return str
+}
+
+// Join regional values, such as those returned by `RegionalData.Latest`, to the
+// features whose `code_property` (`SHAPE_CODE_PROPERTY` if empty) matches their
+// region code.
+//
+// Returns a new collection whose features have `value`, `series_id` and `region`
+// properties added. Features without a value, or whose value is missing, get a
+// null `value`.
+func (fc GeoJSONFeatureCollection) Join(values map[string]RegionalValue, code_property string) GeoJSONFeatureCollection {
+	if len(code_property) == 0 {
+		code_property = SHAPE_CODE_PROPERTY
+	}
+
+	by_code := make(map[string]RegionalValue, len(values))
+	for code, value := range values {
+		by_code[region_code(code)] = value
+	}
+
+	joined := GeoJSONFeatureCollection{Type: "FeatureCollection", Features: make([]GeoJSONFeature, len(fc.Features))}
+	for i, feature := range fc.Features {
+		props := make(map[string]interface{}, len(feature.Properties)+3)
+		for k, v := range feature.Properties {
+			props[k] = v
+		}
+		props["value"] = nil
+
+		if code, exists := feature.Properties[code_property]; exists {
+			if value, exists := by_code[region_code(code)]; exists {
+				props["series_id"] = value.Series
+				props["region"] = value.Region
+				if value.Valid {
+					props["value"] = value.Value
+				}
+			}
+		}
+
+		feature.Properties = props
+		joined.Features[i] = feature
+	}
+	return joined
+}
+
+// Holds the data needed to request the shapes of every region of a type.
+type ShapesRequest struct {
+	baseRequest
+	Shape RegionType
+}
+
+func NewShapesRequest(shape RegionType) ShapesRequest {
+	return ShapesRequest{
+		Shape: shape,
+	}
+}
+
+// Satisfies the `Request` interface.
+func (r ShapesRequest) ToParams() url.Values {
+	v := r.baseRequest.ToParams()
+	v.Set("shape", string(r.Shape))
+	return v
+}
+
+// Satisfies the `Request` interface.
+func (r ShapesRequest) Validate() error {
+	return r.Shape.validate()
+}
+
+// Shapes are always GeoJSON, either the collection itself or wrapped in an
+// object keyed on the shape type.
+func parse_shapes(body []byte) (GeoJSONFeatureCollection, error) {
+	var result GeoJSONFeatureCollection
+	if err := json.Unmarshal(body, &result); err != nil {
+		return result, err
+	}
+	if result.Type == "FeatureCollection" {
+		return result, nil
+	}
+
+	var wrapped map[string]json.RawMessage
+	if err := json.Unmarshal(body, &wrapped); err != nil {
+		return result, err
+	}
+	for _, inner := range wrapped {
+		if err := json.Unmarshal(inner, &result); err == nil && result.Type == "FeatureCollection" {
+			return result, nil
+		}
+	}
+	return result, fmt.Errorf("no feature collection in response")
+}
+
+// Get the boundaries of every region of the given type as GeoJSON.
+//
+// The response is GeoJSON whatever the client's response format.
+func (c Client) GeoShapes(req ShapesRequest) (GeoJSONFeatureCollection, Error) {
+	return c.GeoShapesContext(context.Background(), req)
+}
+
+// Same as `GeoShapes`, but the request is bound to the given context.
+func (c Client) GeoShapesContext(ctx context.Context, req ShapesRequest) (GeoJSONFeatureCollection, Error) {
+	req.baseRequest = c.base_req
+	req_url := c.maps_url()
+	req_url.RawQuery = req.ToParams().Encode()
+	req_url.Path = fmt.Sprintf("%s/shapes/file", req_url.Path)
+	if err := req.Validate(); err != nil {
+		return GeoJSONFeatureCollection{}, c.invalid_request(req_url.String(), "shapes", err)
+	}
+
+	body, err := c.get(ctx, "shapes", req_url.String())
+	if err != nil {
+		return GeoJSONFeatureCollection{}, err.Prefixf("error getting %s shapes:", req.Shape)
+	}
+
+	result, parse_err := parse_shapes(body)
+	if parse_err != nil {
+		return GeoJSONFeatureCollection{}, c.request_error(req_url.String(), &APIError{
+			ty:  ParseError,
+			msg: fmt.Sprintf("could not get %s shapes: failed to parse geojson response: %v", req.Shape, parse_err),
+			err: parse_err,
+		})
+	}
+	return result, nil
+}
@@ -0,0 +1,195 @@
+package gofred
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+const SERIES_INCOME_WYOMING = "WYPCPI"
+
+// Serves the Maps API below `/geofred`, next to a client base URL ending in `/fred`.
+func new_geofred_test_server(t *testing.T) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		xml := q.Get("file_type") == "xml"
+
+		switch r.URL.Path {
+		case "/geofred/series/group":
+			if xml {
+				w.Write([]byte(`<series_groups><series_group title="Per Capita Personal Income" region_type="state"
+					series_group="882" season="NSA" units="Dollars" frequency="a" min_date="1929-01-01" max_date="2013-01-01"/></series_groups>`))
+				return
+			}
+			w.Write([]byte(`{"series_group":{"title":"Per Capita Personal Income","region_type":"state",
+				"series_group":"882","season":"NSA","units":"Dollars","frequency":"a","min_date":"1929-01-01","max_date":"2013-01-01"}}`))
+
+		case "/geofred/series/data", "/geofred/regional/data":
+			if r.URL.Path == "/geofred/regional/data" {
+				expect := map[string]string{"series_group": "882", "region_type": "state", "date": "2013-01-01",
+					"season": "NSA", "units": "Dollars", "start_date": "2012-01-01"}
+				for param, value := range expect {
+					if q.Get(param) != value {
+						t.Errorf("expected %s=%s, got: %s", param, value, q.Get(param))
+					}
+				}
+			}
+
+			if xml {
+				w.Write([]byte(`<series_data><meta title="Per Capita Personal Income by State" region="state"
+					seasonality="Not Seasonally Adjusted" units="Dollars" frequency="Annual">
+					<observation date="2012-01-01"><data region="Alabama" code="01" value="35625" series_id="ALPCPI"/></observation>
+					<observation date="2013-01-01"><data region="Alabama" code="01" value="36481" series_id="ALPCPI"/>
+					<data region="Alaska" code="02" value="." series_id="AKPCPI"/></observation>
+				</meta></series_data>`))
+				return
+			}
+			w.Write([]byte(`{"meta":{"title":"Per Capita Personal Income by State","region":"state",
+				"seasonality":"Not Seasonally Adjusted","units":"Dollars","frequency":"Annual","data":{
+				"2013-01-01":[{"region":"Alabama","code":"01","value":"36481","series_id":"ALPCPI"},
+					{"region":"Alaska","code":"02","value":null,"series_id":"AKPCPI"}],
+				"2012-01-01":[{"region":"Alabama","code":"01","value":35625,"series_id":"ALPCPI"}]}}}`))
+
+		case "/geofred/shapes/file":
+			if q.Get("shape") != "state" {
+				t.Errorf("expected shape=state, got: %s", q.Get("shape"))
+			}
+			w.Write([]byte(`{"state":{"type":"FeatureCollection","features":[
+				{"type":"Feature","properties":{"name":"Alabama","code":1},
+					"geometry":{"type":"Polygon","coordinates":[[[0,0],[1,0],[1,1],[0,0]]]}},
+				{"type":"Feature","properties":{"name":"Alaska","code":"02"},
+					"geometry":{"type":"Polygon","coordinates":[[[2,2],[3,2],[3,3],[2,2]]]}},
+				{"type":"Feature","properties":{"name":"Arizona","code":"04"},"geometry":null}]}}`))
+
+		default:
+			t.Errorf("unexpected path: %s", r.URL.Path)
+		}
+	}))
+}
+
+func geofred_test_client(t *testing.T, url string, format ResponseFormat) Client {
+	client, err := NewClient(API_KEY, format, WithBaseURL(url+"/fred"))
+	if err != nil {
+		t.Fatalf("could not create client: %v", err)
+	}
+	return client
+}
+
+func check_regional_data(t *testing.T, data RegionalData) {
+	if data.Region != RegionState || data.Units != "Dollars" || len(data.Dates) != 2 {
+		t.Fatalf("unexpected regional data: %+v", data)
+	}
+
+	first := data.Dates[0]
+	if time.Time(first.Date).Format(DATE_FORMAT) != "2012-01-01" || first.Values["01"].Value != 35625 {
+		t.Errorf("expected 2012 values first, got: %+v", first)
+	}
+
+	latest, ok := data.On(time.Date(2013, time.January, 1, 0, 0, 0, 0, time.UTC))
+	if !ok {
+		t.Fatalf("expected values on 2013-01-01: %+v", data)
+	}
+	expect := RegionalValue{Region: "Alabama", Code: "01", Series: "ALPCPI", Value: 36481, Valid: true}
+	if latest["01"] != expect {
+		t.Errorf("expected value:\n%+v\ngot:\n%+v", expect, latest["01"])
+	}
+	if alaska, exists := latest["02"]; !exists || alaska.Valid {
+		t.Errorf("expected a missing value for Alaska, got: %+v", alaska)
+	}
+}
+
+func TestGeoSeriesData(t *testing.T) {
+	server := new_geofred_test_server(t)
+	defer server.Close()
+
+	for _, format := range []ResponseFormat{JSON, XML} {
+		client := geofred_test_client(t, server.URL, format)
+
+		group, err := client.GeoSeriesGroup(NewGeoSeriesRequest(SERIES_INCOME_WYOMING))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if group.Id != "882" || group.RegionType != RegionState || group.Frequency != Annual {
+			t.Errorf("unexpected series group: %+v", group)
+		}
+
+		data, err := client.GeoSeriesData(NewGeoSeriesRequest(SERIES_INCOME_WYOMING))
+		if err != nil {
+			t.Fatal(err)
+		}
+		check_regional_data(t, data)
+
+		req := NewRegionalDataRequest(group, time.Date(2013, time.January, 1, 0, 0, 0, 0, time.UTC))
+		req.StartDate = time.Date(2012, time.January, 1, 0, 0, 0, 0, time.UTC)
+		data, err = client.GeoRegionalData(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		check_regional_data(t, data)
+	}
+}
+
+func TestRegionalDataRequest_Validate(t *testing.T) {
+	group := SeriesGroup{Id: "882", RegionType: RegionState, Season: "NSA", Units: "Dollars"}
+	date := time.Date(2013, time.January, 1, 0, 0, 0, 0, time.UTC)
+	if err := NewRegionalDataRequest(group, date).Validate(); err != nil {
+		t.Errorf("expected request to be valid: %v", err)
+	}
+
+	invalid := []RegionalDataRequest{}
+	for i := 0; i < 4; i++ {
+		invalid = append(invalid, NewRegionalDataRequest(group, date))
+	}
+	invalid[0].SeriesGroup = ""
+	invalid[1].Date = time.Time{}
+	invalid[2].RegionType = "planet"
+	invalid[3].StartDate = date.AddDate(1, 0, 0)
+	for _, r := range invalid {
+		if err := r.Validate(); err == nil {
+			t.Errorf("expected request to be invalid: %+v", r)
+		}
+	}
+}
+
+func TestGeoShapes_Join(t *testing.T) {
+	server := new_geofred_test_server(t)
+	defer server.Close()
+
+	client := geofred_test_client(t, server.URL, JSON)
+	shapes, err := client.GeoShapes(NewShapesRequest(RegionState))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(shapes.Features) != 3 || shapes.Features[0].Geometry.Type != "Polygon" {
+		t.Fatalf("unexpected shapes: %+v", shapes)
+	}
+
+	data, err := client.GeoSeriesData(NewGeoSeriesRequest(SERIES_INCOME_WYOMING))
+	if err != nil {
+		t.Fatal(err)
+	}
+	values, _ := data.Latest()
+
+	joined := shapes.Join(values, "")
+	if joined.Type != "FeatureCollection" || len(joined.Features) != 3 {
+		t.Fatalf("unexpected joined collection: %+v", joined)
+	}
+	alabama := joined.Features[0].Properties
+	if alabama["value"] != 36481.0 || alabama["series_id"] != "ALPCPI" || alabama["name"] != "Alabama" {
+		t.Errorf("expected Alabama's value to be joined, got: %+v", alabama)
+	}
+	for _, i := range []int{1, 2} {
+		if props := joined.Features[i].Properties; props["value"] != nil {
+			t.Errorf("expected a null value for %s, got: %+v", props["name"], props)
+		}
+	}
+	if _, exists := shapes.Features[0].Properties["value"]; exists {
+		t.Errorf("joining should not modify the original features")
+	}
+
+	if _, err := json.Marshal(joined); err != nil {
+		t.Errorf("joined collection should marshal to geojson: %v", err)
+	}
+}
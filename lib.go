@@ -1,13 +1,16 @@
 package gofred
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
 	"encoding/xml"
+	"errors"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"net/http"
 	"net/url"
-	"strconv"
 	"strings"
 	"time"
 )
@@ -16,8 +19,17 @@ const (
 	// Base API endpoint URL all requests go through
 	API_URL = "https://api.stlouisfed.org/fred"
 
+	// Largest page most paged endpoints accept
+	MAX_LIMIT = 1000
+	// Largest page `series/observations` accepts
+	MAX_OBSERVATIONS_LIMIT = 100000
+
 	DATE_FORMAT = "2006-01-02"
 	TIME_FORMAT = "2006-01-02 15:04:05-07"
+
+	// Bounds of the realtime periods FRED knows, requesting the whole range returns every vintage
+	REALTIME_EARLIEST = "1776-07-04"
+	REALTIME_LATEST   = "9999-12-31"
 )
 
 //==============================================================================
@@ -32,6 +44,13 @@ const (
 	JSON ResponseFormat = iota
 	// Get responses in XML format
 	XML ResponseFormat = iota + 1
+
+	// Download observations as an Excel workbook, see `SeriesObservationsFile`.
+	XLSX
+	// Download observations as a legacy (BIFF8) Excel workbook.
+	XLS
+	// Download observations as tab delimited text, zipped along with a readme.
+	TXT
 )
 
 // Get the string representation of the response format as it should be used in a URL param.
@@ -41,6 +60,12 @@ func (f ResponseFormat) String() string {
 		return "json"
 	case XML:
 		return "xml"
+	case XLSX:
+		return "xlsx"
+	case XLS:
+		return "xls"
+	case TXT:
+		return "txt"
 	default:
 		return "ERROR" // TODO
 	}
@@ -60,6 +85,7 @@ func (k ApiKey) String() string {
 type Request interface {
 	ToParams() url.Values
 	MergeParams(url.Values)
+	Validate() error
 }
 
 // Minimal shared request objects.
@@ -84,6 +110,11 @@ func (r baseRequest) MergeParams(v url.Values) {
 	v.Set("file_type", r.fmt.String())
 }
 
+// Satisfies the `Request` interface, the key and format are checked by `NewClient`.
+func (r baseRequest) Validate() error {
+	return nil
+}
+
 type DatedRequest struct {
 	Start Date `json:"realtime_start" xml:"realtime_start"`
 	End   Date `json:"realtime_end" xml:"realtime_end"`
@@ -104,10 +135,20 @@ func (r DatedRequest) MergeParams(v url.Values) {
 	}
 }
 
+// Checks the realtime period is not inverted.
+func (r DatedRequest) Validate() error {
+	start, end := time.Time(r.Start), time.Time(r.End)
+	if !start.IsZero() && !end.IsZero() && end.Before(start) {
+		return fmt.Errorf("realtime end %s is before realtime start %s",
+			end.Format(DATE_FORMAT), start.Format(DATE_FORMAT))
+	}
+	return nil
+}
+
 // Embedded struct for requests with offset/limit params
 type PagedRequest struct {
-	Limit  uint // 1 - 1000
-	Offset uint // 0 - 999
+	Limit  uint // 0 for the API default, otherwise at most the endpoint's maximum
+	Offset uint
 }
 
 func (r PagedRequest) ToParams() url.Values {
@@ -118,17 +159,26 @@ func (r PagedRequest) ToParams() url.Values {
 
 func (r PagedRequest) MergeParams(v url.Values) {
 	if r.Limit > 0 {
-		adj := r.Limit % 1000 // limit is max 1000
-		if adj == 0 {
-			adj = 1
-		}
-		v.Set("limit", fmt.Sprint(adj))
+		v.Set("limit", fmt.Sprint(r.Limit))
 	}
 	if r.Offset > 0 {
-		v.Set("offset", fmt.Sprint(r.Offset%999)) // max offset = max limit - 1
+		v.Set("offset", fmt.Sprint(r.Offset))
 	}
 }
 
+// Checks the limit is within `MAX_LIMIT`.
+func (r PagedRequest) Validate() error {
+	return r.validate_limit(MAX_LIMIT)
+}
+
+// Checks the limit is within the given endpoint maximum.
+func (r PagedRequest) validate_limit(max uint) error {
+	if r.Limit > max {
+		return fmt.Errorf("limit %d exceeds the maximum of %d", r.Limit, max)
+	}
+	return nil
+}
+
 // Embedded struct for requests with order params
 type OrderedRequest struct {
 	Order OrderType
@@ -150,6 +200,15 @@ func (r OrderedRequest) MergeParams(v url.Values) {
 	}
 }
 
+// Checks the sort order is one FRED knows.
+func (r OrderedRequest) Validate() error {
+	switch r.Sort {
+	case "", SortAscending, SortDescending:
+		return nil
+	}
+	return fmt.Errorf("unknown sort order '%s'", r.Sort)
+}
+
 // Embedded struct for requests with filter params
 type FilteredRequest struct {
 	Variable FilterType
@@ -171,6 +230,14 @@ func (r FilteredRequest) MergeParams(v url.Values) {
 	}
 }
 
+// Checks a filter value is only given along with the variable it filters.
+func (r FilteredRequest) Validate() error {
+	if len(r.Value) > 0 && len(r.Variable) == 0 {
+		return fmt.Errorf("filter value '%s' given without a filter variable", r.Value)
+	}
+	return nil
+}
+
 // Embedded struct for requests with tag params
 type TaggedRequest struct {
 	Tags    []string
@@ -188,8 +255,30 @@ func (r TaggedRequest) MergeParams(v url.Values) {
 		v.Set("tag_names", strings.Join(r.Tags, ";"))
 	}
 	if len(r.Exclude) > 0 {
-		v.Set("exclude_tag_name", strings.Join(r.Exclude, ";"))
+		v.Set("exclude_tag_names", strings.Join(r.Exclude, ";"))
+	}
+}
+
+// Checks no tag name is empty or contains the `;` separator.
+func (r TaggedRequest) Validate() error {
+	for _, names := range [][]string{r.Tags, r.Exclude} {
+		for _, name := range names {
+			if len(name) == 0 || strings.Contains(name, ";") {
+				return fmt.Errorf("invalid tag name '%s'", name)
+			}
+		}
+	}
+	return nil
+}
+
+// Runs each validation in order, returning the first failure.
+func validate_all(checks ...error) error {
+	for _, err := range checks {
+		if err != nil {
+			return err
+		}
 	}
+	return nil
 }
 
 //==============================================================================
@@ -273,6 +362,13 @@ func (d *Date) UnmarshalXMLAttr(attr xml.Attr) error {
 	return err
 }
 
+// Used when a date is the character data of an XML element, e.g. `<release_date>`.
+func (d *Date) UnmarshalText(text []byte) error {
+	as_time, err := time.Parse(DATE_FORMAT, strings.TrimSpace(string(text)))
+	*d = Date(as_time)
+	return err
+}
+
 type DateTime time.Time
 
 func (d *DateTime) UnmarshalJSON(input []byte) error {
@@ -468,45 +564,52 @@ func (f Frequency) LongString() string {
 }
 
 type DataPoint struct {
-	Date  Date    `json:"date" xml:"date"`
-	Value float64 `json:"value" xml:"value"`
-	Valid bool
+	Date  Date    `json:"date" xml:"date,attr"`
+	Value float64 `json:"value" xml:"value,attr"`
+	Valid bool    // false for missing values, given as "." by FRED
 }
 
 func (d *DataPoint) UnmarshalJSON(input []byte) error {
-	d.Valid = false
 	var as_map map[string]string
 	if err := json.Unmarshal(input, &as_map); err != nil {
 		return err
 	}
 
-	date, exists := as_map["date"]
-	if !exists {
-		return fmt.Errorf("no date in datapoint")
+	return d.parse(as_map["date"], as_map["value"])
+}
+
+func (d *DataPoint) UnmarshalXML(dec *xml.Decoder, start xml.StartElement) error {
+	var date, value string
+	for _, attr := range start.Attr {
+		switch attr.Name.Local {
+		case "date":
+			date = attr.Value
+		case "value":
+			value = attr.Value
+		}
+	}
+	if err := dec.Skip(); err != nil {
+		return err
 	}
 
-	value, exists := as_map["value"]
-	if !exists {
-		return fmt.Errorf("no value in datapoint")
+	return d.parse(date, value)
+}
+
+func (d *DataPoint) parse(date, value string) error {
+	if date == "" {
+		return fmt.Errorf("no date in datapoint")
 	}
-	if value == "." {
-		return nil // TODO: can we sentinel with error rather than filtering
+	if value == "" {
+		return fmt.Errorf("no value in datapoint")
 	}
 
 	as_time, err := time.Parse(DATE_FORMAT, date)
 	if err != nil {
 		return err
 	}
-	d.Date = Date(as_time)
-
-	d.Value, err = strconv.ParseFloat(value, 64)
-	if err != nil {
-		fmt.Println(string(input))
-		return fmt.Errorf("could not parse '%s': %v", value, err)
-	}
 
-	d.Valid = true
-	return nil
+	*d, err = parse_data_point(Date(as_time), value)
+	return err
 }
 
 // ordering
@@ -528,6 +631,11 @@ const (
 	OrderObservationStart   OrderType = "observation_start"
 	OrderObservationEnd     OrderType = "observation_end"
 	OrderPopularity         OrderType = "popularity"
+	OrderReleaseId          OrderType = "release_id"
+	OrderReleaseName        OrderType = "release_name"
+	OrderReleaseDate        OrderType = "release_date"
+	OrderPressRelease       OrderType = "press_release"
+	OrderSourceId           OrderType = "source_id"
 )
 
 // filter
@@ -629,6 +737,14 @@ func (t TagId) MarshalJSON() ([]byte, error) {
 	return json.Marshal(t.String())
 }
 
+// Checks the tag group is either unset or one FRED knows.
+func (t TagId) validate() error {
+	if t != TagNone && TagIdFromString(t.String()) != t {
+		return fmt.Errorf("unknown tag group '%s'", string(t))
+	}
+	return nil
+}
+
 // units
 
 type UnitType uint
@@ -670,6 +786,32 @@ func (u UnitType) String() string {
 	return "unknwon unit"
 }
 
+// The code FRED uses for the unit in requests and responses, e.g. "pc1".
+func (u UnitType) ShortString() string {
+	switch u {
+	case UnitLinear:
+		return "lin"
+	case UnitChange:
+		return "chg"
+	case UnitChangeFromYearAgo:
+		return "ch1"
+	case UnitPercentChange:
+		return "pch"
+	case UnitPercentChangeFromYearAgo:
+		return "pc1"
+	case UnitCompoundedAnnualRateOfChange:
+		return "pca"
+	case UnitContinuouslyCompoundedRateOfChange:
+		return "cch"
+	case UnitContinuouslyCompoundedAnnualRateOfChange:
+		return "cca"
+	case UnitNaturalLog:
+		return "log"
+	}
+
+	return "unknown unit"
+}
+
 func UnitTypeFromString(str string) (UnitType, error) {
 	switch str {
 	case "lin":
@@ -731,9 +873,19 @@ const (
 	UnknownResponseFormat ErrorType = 4
 	NotFound              ErrorType = 404 // HTTP errors
 	Invalid               ErrorType = 400
+	RateLimited           ErrorType = 429
 	UnknownError          ErrorType = 999 // misc
 )
 
+// Sentinel errors matched by `errors.Is` against any `Error` of the corresponding type.
+var (
+	ErrNotFound       = errors.New("gofred: not found")
+	ErrRateLimited    = errors.New("gofred: rate limited")
+	ErrInvalidRequest = errors.New("gofred: invalid request")
+	ErrParse          = errors.New("gofred: could not parse response")
+	ErrTransport      = errors.New("gofred: request failed")
+)
+
 type Error interface {
 	error
 	Type() ErrorType
@@ -741,28 +893,104 @@ type Error interface {
 	Prefixf(string, ...interface{}) Error
 }
 
+// Error returned by every request method.
+//
+// Errors are never modified once created, `Prefix` and `Prefixf` return a new
+// error wrapping the original. Use `errors.As` to get at the details and
+// `errors.Is` with the `Err...` sentinels to check the kind of failure.
 type APIError struct {
 	ty  ErrorType
 	msg string
+
+	status   int
+	code     uint32
+	message  string
+	endpoint string
+	url      string
+
+	err error // the cause, if any
 }
 
 func (e *APIError) Error() string   { return e.msg }
 func (e *APIError) Type() ErrorType { return e.ty }
+
+// HTTP status code of the response, 0 if none was received.
+func (e *APIError) StatusCode() int { return e.status }
+
+// The `error_code` FRED responded with, if any.
+func (e *APIError) ErrorCode() uint32 { return e.code }
+
+// The `error_message` FRED responded with, if any.
+func (e *APIError) ErrorMessage() string { return e.message }
+
+// The endpoint requested, relative to the base URL, e.g. "series/observations".
+func (e *APIError) Endpoint() string { return e.endpoint }
+
+// The URL requested, with the API key redacted.
+func (e *APIError) URL() string { return e.url }
+
+// The error this one wraps, if any.
+func (e *APIError) Unwrap() error { return e.err }
+
+// Matches the `Err...` sentinel of the error's type.
+func (e *APIError) Is(target error) bool {
+	switch target {
+	case ErrNotFound:
+		return e.ty == NotFound
+	case ErrRateLimited:
+		return e.ty == RateLimited
+	case ErrInvalidRequest:
+		return e.ty == Invalid
+	case ErrParse:
+		return e.ty == ParseError
+	case ErrTransport:
+		return e.ty == HTTPError || e.ty == ReadError
+	}
+	return false
+}
+
+// Returns a copy of the error, with the message prefixed and wrapping the original.
 func (e *APIError) Prefix(p string) Error {
-	e.msg = fmt.Sprintf("%s %v", p, e.msg)
-	return e
+	if e == nil {
+		return nil
+	}
+	prefixed := *e
+	prefixed.msg = fmt.Sprintf("%s %v", p, e.msg)
+	prefixed.err = e
+	return &prefixed
 }
+
+// Same as `Prefix`, with a formatted prefix.
 func (e *APIError) Prefixf(f string, args ...interface{}) Error {
-	e.msg = fmt.Sprintf("%s %v", fmt.Sprintf(f, args...), e.msg)
-	return e
+	return e.Prefix(fmt.Sprintf(f, args...))
+}
+
+// Attaches the request to the error, returning a copy.
+func (e *APIError) with_request(endpoint, req_url string) *APIError {
+	with := *e
+	with.endpoint = endpoint
+	with.url = req_url
+	return &with
+}
+
+// Error returned when a request fails `Validate` and is never sent, attached to
+// the URL it would have been sent to.
+func (c Client) invalid_request(req_url, desc string, err error) Error {
+	return c.request_error(req_url, &APIError{
+		ty:  Invalid,
+		msg: fmt.Sprintf("invalid %s request: %v", desc, err),
+		err: err,
+	})
 }
 
 // Generic error response type.
 //
 // If a non-success return code is returned, this type is expected to be parseable.
+// FRED's error response, `{"error_code": ..., "error_message": ...}` in JSON and
+// `<error code="..." message="..."/>` in XML.
 type baseError struct {
-	Message string `json:"error_message" xml:"error_message"`
-	Code    uint32 `json:"error_code" xml:"error_code"`
+	Message string `json:"error_message" xml:"message,attr"`
+	Code    uint32 `json:"error_code" xml:"code,attr"`
 }
 
 //==============================================================================
@@ -774,64 +1002,172 @@ type baseError struct {
 // Requires specifying the API key and response format for all future requests
 // through this client.
 type Client struct {
-	base_req baseRequest
-	base_url url.URL
+	base_req    baseRequest
+	base_url    url.URL
+	http_client *http.Client
+	user_agent  string
+	timeout     time.Duration
+	retry       RetryPolicy
+	limiter     *RateLimiter
+	cache       Cache
+	cache_ttl   time.Duration
+	cache_ttls  map[string]time.Duration
+}
+
+// Functional option used to configure a `Client` in `NewClient`.
+type ClientOption func(*Client) error
+
+// Send all requests through the given `http.Client` rather than `http.DefaultClient`.
+//
+// Useful for routing through a proxy or configuring TLS.
+func WithHTTPClient(hc *http.Client) ClientOption {
+	return func(c *Client) error {
+		if hc == nil {
+			return fmt.Errorf("http client cannot be nil")
+		}
+		c.http_client = hc
+		return nil
+	}
+}
+
+// Send all requests to the given base URL rather than `API_URL`.
+//
+// Endpoint paths (`/category`, `/series`, ...) are appended to the URL's path.
+// Maps API requests go to its sibling `/geofred` path.
+func WithBaseURL(base string) ClientOption {
+	return func(c *Client) error {
+		base_url, err := url.Parse(strings.TrimSuffix(base, "/"))
+		if err != nil {
+			return fmt.Errorf("invalid base url '%s': %v", base, err)
+		}
+		c.base_url = *base_url
+		return nil
+	}
+}
+
+// Set the `User-Agent` header sent with every request.
+func WithUserAgent(agent string) ClientOption {
+	return func(c *Client) error {
+		c.user_agent = agent
+		return nil
+	}
+}
+
+// Bound every request made through the client by the given timeout.
+//
+// This applies on top of any deadline on the context given to a `...Context` method.
+func WithTimeout(timeout time.Duration) ClientOption {
+	return func(c *Client) error {
+		if timeout < 0 {
+			return fmt.Errorf("timeout cannot be negative: %v", timeout)
+		}
+		c.timeout = timeout
+		return nil
+	}
 }
 
 // Create a new client with the given API key and response format.
-func NewClient(key string, format ResponseFormat) (Client, error) {
+//
+// The format must be `JSON` or `XML`, the file formats are only used to download
+// observations. Any options are applied in order after the defaults have been set.
+func NewClient(key string, format ResponseFormat, opts ...ClientOption) (Client, error) {
 	if len(key) != 32 {
 		return Client{}, fmt.Errorf("api key is invalid length")
 	}
+	if format != JSON && format != XML {
+		return Client{}, fmt.Errorf("response format %v can only be used to download observations", format)
+	}
 
 	api_url, err := url.Parse(API_URL)
 	if err != nil {
 		return Client{}, err
 	}
 
-	return Client{
+	client := Client{
 		base_req: baseRequest{
 			fmt:     format,
 			api_key: ApiKey(key),
 		},
-		base_url: *api_url,
-	}, nil
+		base_url:    *api_url,
+		http_client: http.DefaultClient,
+		retry:       NoRetry,
+	}
+
+	for _, opt := range opts {
+		if err := opt(&client); err != nil {
+			return Client{}, err
+		}
+	}
+
+	return client, nil
 }
 
 // Unmarshals the byte slice into the target interface based on the internal
 // response format given when the client was created.
-func (c Client) unmarshal_body(body []byte, into interface{}) Error {
+//
+// Errors are attached to the request they were a response to.
+func (c Client) unmarshal_body(req_url string, body []byte, into interface{}) Error {
+	var err error
 	switch c.base_req.fmt {
 	case JSON:
-		err := json.Unmarshal(body, into)
-		if err != nil {
-			return &APIError{
-				ty:  ParseError,
-				msg: fmt.Sprintf("failed to parse json response: %v", err),
-			}
-		}
+		err = json.Unmarshal(body, into)
 	case XML:
-		err := xml.Unmarshal(body, into)
-		if err != nil {
-			return &APIError{
-				ty:  ParseError,
-				msg: fmt.Sprintf("failed to parse xml response: %v", err),
-			}
-		}
+		err = xml.Unmarshal(body, into)
 	default:
-		return &APIError{
+		return c.request_error(req_url, &APIError{
 			ty:  UnknownResponseFormat,
 			msg: fmt.Sprintf("unknown request/response type: %v", c.base_req.fmt),
-		}
+		})
 	}
 
+	if err != nil {
+		return c.request_error(req_url, &APIError{
+			ty:  ParseError,
+			msg: fmt.Sprintf("failed to parse %s response: %v", c.base_req.fmt, err),
+			err: err,
+		})
+	}
 	return nil
 }
 
-// Parses the byte slice as a `baseError` depending on the response format.
+// The endpoint of the request URL, its path relative to the base URL.
+func (c Client) endpoint(u *url.URL) string {
+	return strings.Trim(strings.TrimPrefix(u.Path, c.base_url.Path), "/")
+}
+
+// The request URL with the API key redacted.
+func redact_url(u url.URL) string {
+	query := u.Query()
+	if _, exists := query["api_key"]; exists {
+		query.Set("api_key", ApiKey("").String())
+		u.RawQuery = query.Encode()
+	}
+	return u.String()
+}
+
+// Attaches the request's endpoint and redacted URL to the error.
+func (c Client) request_error(req_url string, err *APIError) *APIError {
+	u, parse_err := url.Parse(req_url)
+	if parse_err != nil {
+		return err
+	}
+	return err.with_request(c.endpoint(u), redact_url(*u))
+}
+
+// Parses the byte slice as a `baseError`, in whichever of JSON and XML the body
+// is rather than the client's format: errors about downloads or the format itself
+// need not be in the format requested.
 func (c Client) get_error(body []byte) (baseError, Error) {
+	format := c.base_req.fmt
+	switch trimmed := bytes.TrimSpace(body); {
+	case bytes.HasPrefix(trimmed, []byte("{")):
+		format = JSON
+	case bytes.HasPrefix(trimmed, []byte("<")):
+		format = XML
+	}
+
 	var result baseError
-	switch c.base_req.fmt {
+	switch format {
 	case JSON:
 		err := json.Unmarshal(body, &result)
 		if err != nil {
@@ -851,67 +1187,241 @@ func (c Client) get_error(body []byte) (baseError, Error) {
 	default:
 		return baseError{}, &APIError{
 			ty:  UnknownResponseFormat,
-			msg: fmt.Sprintf("unknown request/response type: %v", c.base_req.fmt),
+			msg: fmt.Sprintf("unknown request/response type: %v", format),
 		}
 	}
 
+	// any XML document decodes, FRED's errors always have a code or message
+	if result.Code == 0 && len(result.Message) == 0 {
+		return baseError{}, &APIError{
+			ty:  ParseError,
+			msg: "no error code or message in the error response",
+		}
+	}
 	return result, nil
 }
 
-// Wrapper around `http.Get()` which checks status codes and proxies back either a
+// Wrapper around a GET request which checks status codes and proxies back either a
 // valid response or a parsed/generated error.
-func (c Client) get(desc, req_url string) ([]byte, Error) {
-	res, err := http.Get(req_url)
+//
+// The request is bound to `ctx`, so cancelling it or letting its deadline pass
+// aborts the request in flight. Every attempt waits on the client's rate limiter,
+// if any, and failures are retried according to the client's `RetryPolicy`.
+//
+// If the client has a `Cache`, fresh cached responses are returned without
+// touching the network and successful responses are stored.
+func (c Client) get(ctx context.Context, desc, req_url string) ([]byte, Error) {
+	if c.cache == nil {
+		return c.fetch(ctx, desc, req_url)
+	}
+
+	key, ttl := c.cache_key(req_url)
+	if ttl <= 0 {
+		return c.fetch(ctx, desc, req_url)
+	}
+	if body, hit := c.cache_lookup(key); hit {
+		return body, nil
+	}
+
+	body, err := c.fetch(ctx, desc, req_url)
+	if err == nil {
+		now := time.Now()
+		c.cache.Set(key, CacheEntry{Body: body, Stored: now, Expires: now.Add(ttl)})
+	}
+	return body, err
+}
+
+// Performs the request against the network, waiting on the rate limiter and
+// retrying as needed.
+func (c Client) fetch(ctx context.Context, desc, req_url string) ([]byte, Error) {
+	var body []byte
+	err := c.with_retries(ctx, desc, func(ctx context.Context) (int, time.Duration, Error) {
+		var status int
+		var retry_after time.Duration
+		var err Error
+		body, status, retry_after, err = c.get_once(ctx, desc, req_url)
+		return status, retry_after, err
+	})
+	return body, err
+}
+
+// Performs the request against the network like `fetch`, but hands the body of
+// a successful response to `read` as it arrives rather than buffering it.
+//
+// Streamed responses are never cached, and a failure while reading the body is
+// not retried since `read` may already have acted on part of it.
+func (c Client) stream(ctx context.Context, desc, req_url string, read func(io.Reader) Error) Error {
+	return c.with_retries(ctx, desc, func(ctx context.Context) (int, time.Duration, Error) {
+		res, status, retry_after, err := c.open(ctx, desc, req_url)
+		if err != nil {
+			return status, retry_after, err
+		}
+
+		defer res.Body.Close()
+		return status, 0, read(res.Body)
+	})
+}
+
+// Runs `attempt` until it succeeds, bounded by the client's timeout, waiting on
+// the rate limiter before every attempt and retrying failures according to the
+// client's `RetryPolicy`.
+//
+// Along with any error, `attempt` returns the status code (0 if no response was
+// received) and any `Retry-After` duration given by the server.
+func (c Client) with_retries(ctx context.Context, desc string, attempt func(context.Context) (int, time.Duration, Error)) Error {
+	if c.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.timeout)
+		defer cancel()
+	}
+
+	for i := uint(0); ; i++ {
+		if c.limiter != nil {
+			if err := c.limiter.Wait(ctx); err != nil {
+				return &APIError{
+					ty:  HTTPError,
+					msg: fmt.Sprintf("waiting to request %s: %v", desc, err),
+					err: err,
+				}
+			}
+		}
+
+		status, retry_after, err := attempt(ctx)
+		if err == nil {
+			return nil
+		}
+
+		if i+1 >= c.retry.MaxAttempts || !c.retry.retryable(ctx, status, err) {
+			return err
+		}
+
+		wait := time.NewTimer(c.retry.backoff(i, retry_after))
+		select {
+		case <-ctx.Done():
+			wait.Stop()
+			return err.Prefixf("gave up retrying (%v):", ctx.Err())
+		case <-wait.C:
+		}
+	}
+}
+
+// Performs a single GET request.
+//
+// Along with the body or error, returns the status code (0 if no response was
+// received) and any `Retry-After` duration given by the server.
+func (c Client) get_once(ctx context.Context, desc, req_url string) ([]byte, int, time.Duration, Error) {
+	res, status, retry_after, api_err := c.open(ctx, desc, req_url)
+	if api_err != nil {
+		return nil, status, retry_after, api_err
+	}
+
+	defer res.Body.Close()
+	body, err := ioutil.ReadAll(res.Body)
 	if err != nil {
-		return nil, &APIError{ty: HTTPError, msg: err.Error()}
+		return nil, 0, 0, c.request_error(req_url, &APIError{ty: ReadError, msg: err.Error(), err: err})
+	}
+
+	return body, status, 0, nil
+}
+
+// Sends a single GET request, returning the response if it succeeded.
+//
+// The caller must close the body of a returned response. A failed response is
+// read and closed here, turning it into an error along with its status code
+// and any `Retry-After` duration.
+func (c Client) open(ctx context.Context, desc, req_url string) (*http.Response, int, time.Duration, Error) {
+	req, err := http.NewRequest("GET", req_url, nil)
+	if err != nil {
+		return nil, 0, 0, c.request_error(req_url, &APIError{ty: HTTPError, msg: err.Error(), err: err})
+	}
+	if len(c.user_agent) > 0 {
+		req.Header.Set("User-Agent", c.user_agent)
+	}
+
+	http_client := c.http_client
+	if http_client == nil {
+		http_client = http.DefaultClient
+	}
+
+	res, err := http_client.Do(req.WithContext(ctx))
+	if err != nil {
+		// the transport error includes the url, along with the key
+		msg := strings.Replace(err.Error(), req_url, redact_url(*req.URL), -1)
+		return nil, 0, 0, c.request_error(req_url, &APIError{ty: HTTPError, msg: msg, err: err})
+	}
+	if res.StatusCode == 200 {
+		return res, res.StatusCode, 0, nil
 	}
 
 	defer res.Body.Close()
 	body, err := ioutil.ReadAll(res.Body)
 	if err != nil {
-		return nil, &APIError{ty: ReadError, msg: err.Error()}
+		return nil, 0, 0, c.request_error(req_url, &APIError{ty: ReadError, msg: err.Error(), err: err})
 	}
 
-	// we need this a few times
-	failed_to_parse := &APIError{
-		ty:  ParseError,
-		msg: fmt.Sprintf("failed to parse %s error response: %v", desc, err),
+	api_err, retry_after := c.status_error(desc, res, body)
+	api_err = c.request_error(req_url, api_err)
+	api_err.status = res.StatusCode
+	return nil, res.StatusCode, retry_after, api_err
+}
+
+// Turns a failed response into an error, along with any `Retry-After` duration.
+//
+// A body which is not one of FRED's errors, e.g. a proxy's error page, leaves
+// the error typed by the status code alone.
+func (c Client) status_error(desc string, res *http.Response, body []byte) (*APIError, time.Duration) {
+	req_err, parse_err := c.get_error(body)
+	detail := req_err.Message
+	if parse_err != nil {
+		detail = res.Status
 	}
 
-	// catch early errors
 	switch res.StatusCode {
-	case 200:
-		// do nothing
-
 	// not found (endpoint, seems to not be returned by API)
 	case 404:
-		return nil, &APIError{
-			ty:  NotFound,
-			msg: fmt.Sprintf("could not find %s: %d", desc, res.StatusCode),
-		}
+		return &APIError{
+			ty:      NotFound,
+			msg:     fmt.Sprintf("could not find %s: %d", desc, res.StatusCode),
+			code:    req_err.Code,
+			message: req_err.Message,
+		}, 0
 
 	// invalid request
 	case 400:
-		req_err, err := c.get_error(body)
-		if err != nil {
-			return nil, failed_to_parse
-		}
-		return nil, &APIError{
-			ty:  Invalid,
-			msg: fmt.Sprintf("invalid %s request: %s", desc, req_err.Message),
+		return &APIError{
+			ty:      Invalid,
+			msg:     fmt.Sprintf("invalid %s request: %s", desc, detail),
+			code:    req_err.Code,
+			message: req_err.Message,
+		}, 0
+
+	// too many requests, the body is not always a well formed error
+	case 429:
+		msg := fmt.Sprintf("rate limited requesting %s", desc)
+		if parse_err == nil && len(req_err.Message) > 0 {
+			msg = fmt.Sprintf("%s: %s", msg, req_err.Message)
 		}
+		return &APIError{
+			ty:      RateLimited,
+			msg:     msg,
+			code:    req_err.Code,
+			message: req_err.Message,
+		}, parse_retry_after(res.Header.Get("Retry-After"))
 
 	// anything else
 	default:
-		req_err, err := c.get_error(body)
-		if err != nil {
-			return nil, failed_to_parse
-		}
-		return nil, &APIError{
-			ty:  UnknownError,
-			msg: fmt.Sprintf("could not get %s (%d): %v", desc, req_err.Code, req_err.Message),
+		retry_after := parse_retry_after(res.Header.Get("Retry-After"))
+		if parse_err != nil {
+			return &APIError{
+				ty:  UnknownError,
+				msg: fmt.Sprintf("could not get %s: %s", desc, detail),
+			}, retry_after
 		}
+		return &APIError{
+			ty:      UnknownError,
+			msg:     fmt.Sprintf("could not get %s (%d): %v", desc, req_err.Code, req_err.Message),
+			code:    req_err.Code,
+			message: req_err.Message,
+		}, retry_after
 	}
-
-	return body, nil
 }
@@ -1,9 +1,25 @@
 package gofred
 
-import "testing"
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"strings"
+	"testing"
+	"time"
 
-func make_client(t *testing.T, format ResponseFormat) Client {
-	client, err := NewClient(API_KEY, format)
+	"github.com/zmarcantel/gofred/fredtest"
+)
+
+// Golden files of live API responses, replayed by `mux_test`.
+const FIXTURE_DIR = "testdata/fixtures"
+
+func make_client(t *testing.T, format ResponseFormat, opts ...ClientOption) Client {
+	client, err := NewClient(API_KEY, format, opts...)
 	if err != nil {
 		t.Fatalf("could not create client: %v", err)
 	}
@@ -11,10 +27,324 @@ func make_client(t *testing.T, format ResponseFormat) Client {
 	return client
 }
 
+// Responses are only replayed from `FIXTURE_DIR`, the test sending a request which
+// has not been recorded is skipped rather than reaching the network. Set
+// `GOFRED_RECORD=1` to record every response from the live API, which needs a
+// registered `API_KEY`.
+func fixture_recorder(t *testing.T) *fredtest.Recorder {
+	if os.Getenv("GOFRED_RECORD") == "1" {
+		return fredtest.NewRecorder(FIXTURE_DIR, fredtest.Record)
+	}
+
+	recorder := fredtest.NewRecorder(FIXTURE_DIR, fredtest.Replay)
+	recorder.OnMissing = func(req *http.Request) {
+		t.Skipf("%s %s has not been recorded in %s, set GOFRED_RECORD=1 to record it", req.Method, req.URL.Path, recorder.Path(req))
+	}
+	return recorder
+}
+
 func mux_test(t *testing.T, test func(Client)) {
-	js_client := make_client(t, JSON)
-	xml_client := make_client(t, XML)
+	recorder := fixture_recorder(t)
+	js_client := make_client(t, JSON, WithHTTPClient(recorder.Client()))
+	xml_client := make_client(t, XML, WithHTTPClient(recorder.Client()))
 
 	test(js_client)
 	test(xml_client)
 }
+
+// Same as `mux_test`, but the clients talk to a fake FRED serving the `fredtest`
+// sample dataset rather than the live API. The options are given to both clients.
+func fake_test(t *testing.T, test func(Client), opts ...ClientOption) {
+	server := fredtest.NewServer(fredtest.Sample())
+	defer server.Close()
+
+	for _, format := range []ResponseFormat{JSON, XML} {
+		client, err := NewClient(fredtest.API_KEY, format, append([]ClientOption{WithBaseURL(server.URL())}, opts...)...)
+		if err != nil {
+			t.Fatalf("could not create client: %v", err)
+		}
+		test(client)
+	}
+}
+
+// An `http.RoundTripper` keeping the query of every request it sends.
+type queryRecorder struct {
+	queries []url.Values
+}
+
+func (q *queryRecorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	q.queries = append(q.queries, req.URL.Query())
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func (q *queryRecorder) last() url.Values {
+	if len(q.queries) == 0 {
+		return url.Values{}
+	}
+	return q.queries[len(q.queries)-1]
+}
+
+func TestClient_CancelledContext(t *testing.T) {
+	mux_test(t, func(client Client) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		cat, err := client.CategoryContext(ctx, CATEGORY_TRADE_BALANCE)
+		if err == nil {
+			t.Fatalf("expected an error response, got: %+v", cat)
+		}
+		if err.Type() != HTTPError {
+			t.Errorf("expected type: %v, got: %v", HTTPError, err.Type())
+		}
+	})
+}
+
+func TestClient_Options(t *testing.T) {
+	agent := "gofred-test"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/fred/category" {
+			t.Errorf("unexpected path: %s", r.URL.Path)
+		}
+		if r.UserAgent() != agent {
+			t.Errorf("expected user agent %s, got: %s", agent, r.UserAgent())
+		}
+		w.Write([]byte(`{"categories":[{"id":125,"name":"Trade Balance","parent_id":13}]}`))
+	}))
+	defer server.Close()
+
+	client, err := NewClient(API_KEY, JSON,
+		WithBaseURL(server.URL+"/fred/"),
+		WithHTTPClient(server.Client()),
+		WithUserAgent(agent),
+		WithTimeout(time.Second),
+	)
+	if err != nil {
+		t.Fatalf("could not create client: %v", err)
+	}
+
+	expect := Category{Id: 125, Name: "Trade Balance", ParentId: 13}
+	cat, api_err := client.Category(expect.Id)
+	if api_err != nil {
+		t.Fatal(api_err)
+	}
+	if cat != expect {
+		t.Errorf("expected response:\n%+v\ngot:\n%+v", expect, cat)
+	}
+}
+
+func TestClient_InvalidOptions(t *testing.T) {
+	if _, err := NewClient(API_KEY, JSON, WithHTTPClient(nil)); err == nil {
+		t.Errorf("expected an error for a nil http client")
+	}
+	if _, err := NewClient(API_KEY, JSON, WithTimeout(-time.Second)); err == nil {
+		t.Errorf("expected an error for a negative timeout")
+	}
+	if _, err := NewClient(API_KEY, JSON, WithBaseURL("://bad")); err == nil {
+		t.Errorf("expected an error for an unparseable base url")
+	}
+}
+
+func TestPagedRequest_Params(t *testing.T) {
+	v := PagedRequest{Limit: 1000, Offset: 2500}.ToParams()
+	if v.Get("limit") != "1000" || v.Get("offset") != "2500" {
+		t.Errorf("expected paging params to be sent unchanged, got: %v", v)
+	}
+
+	if err := (PagedRequest{Limit: MAX_LIMIT}).Validate(); err != nil {
+		t.Errorf("expected the maximum limit to be valid: %v", err)
+	}
+	if err := (PagedRequest{Limit: MAX_LIMIT + 1}).Validate(); err == nil {
+		t.Errorf("expected a limit over the maximum to be invalid")
+	}
+}
+
+func TestRequest_Validate(t *testing.T) {
+	observations := NewSeriesObservationsRequest("GNPCA", time.Time{}, time.Time{})
+	observations.Limit = MAX_OBSERVATIONS_LIMIT
+	if err := observations.Validate(); err != nil {
+		t.Errorf("expected observations to allow a limit of %d: %v", MAX_OBSERVATIONS_LIMIT, err)
+	}
+
+	inverted := NewSeriesObservationsRequest("GNPCA", time.Now(), time.Now().Add(-time.Hour*24))
+	sorted := NewSeriesTagsRequest("GNPCA")
+	sorted.Sort = "sideways"
+	filtered := NewSeriesSearchRequest("gdp", SearchFullText)
+	filtered.Value = "Monthly"
+	tagged := NewCategoryRelatedTagsRequest(125, "usa", "")
+	grouped := NewCategoryTagsRequest(125, TagId("bogus"), "")
+
+	invalid := map[string]interface {
+		Validate() error
+	}{
+		"missing series":         NewSeriesRequest(""),
+		"inverted observations":  inverted,
+		"unknown sort order":     sorted,
+		"filter without var":     filtered,
+		"empty tag name":         tagged,
+		"unknown tag group":      grouped,
+		"unknown updates filter": NewSeriesUpdatesRequest(FilterType("bogus")),
+	}
+	for desc, req := range invalid {
+		if err := req.Validate(); err == nil {
+			t.Errorf("%s: expected request to be invalid: %+v", desc, req)
+		}
+	}
+}
+
+func TestClient_RefusesInvalidRequest(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+	}))
+	defer server.Close()
+
+	client, err := NewClient(API_KEY, JSON, WithBaseURL(server.URL))
+	if err != nil {
+		t.Fatalf("could not create client: %v", err)
+	}
+
+	req := NewSeriesSearchRequest("gdp", SearchFullText)
+	req.Limit = MAX_LIMIT + 1
+	_, api_err := client.SeriesSearch(req)
+	if api_err == nil {
+		t.Fatalf("expected an error response")
+	}
+	if api_err.Type() != Invalid {
+		t.Errorf("expected type: %v, got: %v", Invalid, api_err.Type())
+	}
+	if requests != 0 {
+		t.Errorf("expected the invalid request not to be sent, server saw %d", requests)
+	}
+}
+
+func TestAPIError_Details(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/fred/series":
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"error_code":400,"error_message":"Bad Request.  The series does not exist."}`))
+		case "/fred/category":
+			w.WriteHeader(http.StatusTooManyRequests)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client, err := NewClient(API_KEY, JSON, WithBaseURL(server.URL+"/fred"))
+	if err != nil {
+		t.Fatalf("could not create client: %v", err)
+	}
+
+	_, api_err := client.Series(NewSeriesRequest("ABCD"))
+	if api_err == nil {
+		t.Fatalf("expected an error response")
+	}
+	if !errors.Is(api_err, ErrInvalidRequest) || errors.Is(api_err, ErrNotFound) {
+		t.Errorf("expected only %v to match, got: %v", ErrInvalidRequest, api_err)
+	}
+
+	var details *APIError
+	if !errors.As(api_err, &details) {
+		t.Fatalf("expected an *APIError, got: %T", api_err)
+	}
+	if details.StatusCode() != 400 || details.ErrorCode() != 400 || details.Endpoint() != "series" {
+		t.Errorf("unexpected error details: %d, %d, %s", details.StatusCode(), details.ErrorCode(), details.Endpoint())
+	}
+	if details.ErrorMessage() != "Bad Request.  The series does not exist." {
+		t.Errorf("unexpected error message: %s", details.ErrorMessage())
+	}
+	if strings.Contains(details.URL(), API_KEY) || !strings.Contains(details.URL(), "series_id=ABCD") {
+		t.Errorf("expected the url without the api key, got: %s", details.URL())
+	}
+
+	if _, api_err := client.Category(125); !errors.Is(api_err, ErrRateLimited) {
+		t.Errorf("expected %v, got: %v", ErrRateLimited, api_err)
+	}
+	if _, api_err := client.Source(NewSourceRequest(1)); !errors.Is(api_err, ErrNotFound) {
+		t.Errorf("expected %v, got: %v", ErrNotFound, api_err)
+	}
+	_, api_err = client.SeriesSearch(NewSeriesSearchRequest("", SearchFullText))
+	if !errors.Is(api_err, ErrInvalidRequest) {
+		t.Errorf("expected %v for a request failing validation, got: %v", ErrInvalidRequest, api_err)
+	}
+	if !errors.As(api_err, &details) || details.Endpoint() != "series/search" || strings.Contains(details.URL(), API_KEY) {
+		t.Errorf("expected the request failing validation to be attached, got: %s, %s", details.Endpoint(), details.URL())
+	}
+}
+
+func TestAPIError_StatusWithoutFREDError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		status := http.StatusBadGateway
+		if r.URL.Path == "/fred/series" {
+			status = http.StatusBadRequest
+		}
+		w.Header().Set("Content-Type", "text/html")
+		w.WriteHeader(status)
+		w.Write([]byte(`<html><body><h1>Proxy Error</h1></body></html>`))
+	}))
+	defer server.Close()
+
+	for _, format := range []ResponseFormat{JSON, XML} {
+		client := make_client(t, format, WithBaseURL(server.URL+"/fred"), WithRetryPolicy(RetryPolicy{MaxAttempts: 1}))
+
+		_, api_err := client.Series(NewSeriesRequest("ABCD"))
+		var details *APIError
+		if !errors.Is(api_err, ErrInvalidRequest) || errors.Is(api_err, ErrParse) || !errors.As(api_err, &details) {
+			t.Fatalf("%v: expected %v from the status alone, got: %v", format, ErrInvalidRequest, api_err)
+		}
+		if details.StatusCode() != 400 || details.ErrorCode() != 0 || len(details.ErrorMessage()) > 0 {
+			t.Errorf("%v: unexpected error details: %d, %d, %s", format, details.StatusCode(), details.ErrorCode(), details.ErrorMessage())
+		}
+
+		_, api_err = client.Category(125)
+		if errors.Is(api_err, ErrParse) || !errors.As(api_err, &details) || details.Type() != UnknownError {
+			t.Fatalf("%v: expected an unknown error, got: %v", format, api_err)
+		}
+		if details.StatusCode() != 502 || !strings.Contains(details.Error(), "502 Bad Gateway") {
+			t.Errorf("%v: expected the status to be kept, got: %d, %v", format, details.StatusCode(), details)
+		}
+	}
+}
+
+func TestAPIError_Parse(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"categories":`))
+	}))
+	defer server.Close()
+
+	client, err := NewClient(API_KEY, JSON, WithBaseURL(server.URL))
+	if err != nil {
+		t.Fatalf("could not create client: %v", err)
+	}
+
+	_, api_err := client.Category(125)
+	if !errors.Is(api_err, ErrParse) {
+		t.Fatalf("expected %v, got: %v", ErrParse, api_err)
+	}
+
+	var syntax *json.SyntaxError
+	if !errors.As(api_err, &syntax) {
+		t.Errorf("expected the json error to be wrapped, got: %v", api_err)
+	}
+}
+
+func TestAPIError_PrefixIsImmutable(t *testing.T) {
+	original := &APIError{ty: NotFound, msg: "missing", status: 404}
+	prefixed := original.Prefixf("getting %s:", "thing")
+
+	if original.Error() != "missing" {
+		t.Errorf("prefixing modified the original: %s", original.Error())
+	}
+	if prefixed.Error() != "getting thing: missing" || prefixed.Type() != NotFound {
+		t.Errorf("unexpected prefixed error: %v (%v)", prefixed, prefixed.Type())
+	}
+	if errors.Unwrap(prefixed) != original || !errors.Is(prefixed, ErrNotFound) {
+		t.Errorf("expected the prefixed error to wrap the original")
+	}
+
+	var nil_err *APIError
+	if nil_err.Prefix("nothing") != nil {
+		t.Errorf("prefixing a nil error should stay nil")
+	}
+}
@@ -0,0 +1,146 @@
+package gofred
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+//==============================================================================
+// rate limiting
+//==============================================================================
+
+const (
+	// Number of requests FRED allows per API key in `RATE_LIMIT_PERIOD`.
+	RATE_LIMIT_REQUESTS = 120
+	RATE_LIMIT_PERIOD   = time.Minute
+)
+
+// Token bucket limiting how quickly requests are sent.
+//
+// Tokens accrue at a steady rate up to `burst`, and every request consumes one.
+// A single limiter is safe to share across goroutines and across every `Client`
+// using the same API key.
+type RateLimiter struct {
+	mu       sync.Mutex
+	interval time.Duration // time to accrue a single token
+	burst    float64
+	tokens   float64
+	last     time.Time
+}
+
+// Create a limiter allowing `requests` requests every `per`, with up to `burst`
+// requests sent back to back.
+func NewRateLimiter(requests uint, per time.Duration, burst uint) (*RateLimiter, error) {
+	if requests == 0 || per <= 0 {
+		return nil, fmt.Errorf("rate limit must allow at least one request in a positive period")
+	}
+	if burst == 0 {
+		burst = 1
+	}
+
+	return &RateLimiter{
+		interval: per / time.Duration(requests),
+		burst:    float64(burst),
+		tokens:   float64(burst),
+		last:     time.Now(),
+	}, nil
+}
+
+// Refill the bucket for the time elapsed since the last refill.
+//
+// Must be called with the lock held.
+func (l *RateLimiter) refill(now time.Time) {
+	if elapsed := now.Sub(l.last); elapsed > 0 && l.interval > 0 {
+		l.tokens += float64(elapsed) / float64(l.interval)
+		if l.tokens > l.burst {
+			l.tokens = l.burst
+		}
+	}
+	l.last = now
+}
+
+// How long a request made now would have to wait before being sent.
+//
+// Useful for logging; the value may be stale by the time it is acted on.
+func (l *RateLimiter) Delay() time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.refill(time.Now())
+	if l.tokens >= 1 {
+		return 0
+	}
+	return time.Duration((1 - l.tokens) * float64(l.interval))
+}
+
+// Block until a request may be sent, or the context is done.
+//
+// If the context is done first the reserved token is handed back and the
+// context's error is returned.
+func (l *RateLimiter) Wait(ctx context.Context) error {
+	l.mu.Lock()
+	l.refill(time.Now())
+	l.tokens -= 1
+	wait := time.Duration(0)
+	if l.tokens < 0 {
+		wait = time.Duration(-l.tokens * float64(l.interval))
+	}
+	l.mu.Unlock()
+
+	if wait == 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		l.mu.Lock()
+		l.tokens += 1
+		l.mu.Unlock()
+		return ctx.Err()
+	}
+}
+
+// Limit requests made through the client with the given limiter.
+//
+// Pass the same limiter to every client sharing an API key.
+func WithRateLimiter(l *RateLimiter) ClientOption {
+	return func(c *Client) error {
+		if l == nil {
+			return fmt.Errorf("rate limiter cannot be nil")
+		}
+		c.limiter = l
+		return nil
+	}
+}
+
+// Limit requests made through the client to `requests` every `per`, allowing
+// up to `burst` back to back.
+//
+// For FRED's quota use `WithRateLimit(RATE_LIMIT_REQUESTS, RATE_LIMIT_PERIOD, 1)`.
+func WithRateLimit(requests uint, per time.Duration, burst uint) ClientOption {
+	return func(c *Client) error {
+		l, err := NewRateLimiter(requests, per, burst)
+		if err != nil {
+			return err
+		}
+		c.limiter = l
+		return nil
+	}
+}
+
+// How long the next request made through the client would wait on its rate limiter.
+//
+// Always zero if the client is not rate limited.
+func (c Client) RateLimitDelay() time.Duration {
+	if c.limiter == nil {
+		return 0
+	}
+	return c.limiter.Delay()
+}
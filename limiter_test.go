@@ -0,0 +1,110 @@
+package gofred
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestRateLimiter_Burst(t *testing.T) {
+	limiter, err := NewRateLimiter(10, time.Second, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 3; i++ {
+		if delay := limiter.Delay(); delay != 0 {
+			t.Fatalf("request %d within burst should not wait, delay: %v", i, delay)
+		}
+		if err := limiter.Wait(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if delay := limiter.Delay(); delay <= 0 || delay > 100*time.Millisecond {
+		t.Errorf("expected a delay of up to one interval after the burst, got: %v", delay)
+	}
+}
+
+func TestRateLimiter_Rate(t *testing.T) {
+	limiter, err := NewRateLimiter(100, time.Second, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	start := time.Now()
+	var wg sync.WaitGroup
+	for i := 0; i < 6; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := limiter.Wait(context.Background()); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	// first request is free, the other five wait 10ms each
+	if elapsed := time.Since(start); elapsed < 45*time.Millisecond {
+		t.Errorf("expected requests to be spread over ~50ms, took: %v", elapsed)
+	}
+}
+
+func TestRateLimiter_Cancelled(t *testing.T) {
+	limiter, err := NewRateLimiter(1, time.Hour, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := limiter.Wait(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := limiter.Wait(ctx); err != context.DeadlineExceeded {
+		t.Errorf("expected deadline to be exceeded, got: %v", err)
+	}
+
+	// the cancelled wait should not have consumed a token
+	if delay := limiter.Delay(); delay > time.Hour {
+		t.Errorf("cancelled wait kept its token, delay: %v", delay)
+	}
+}
+
+func TestRateLimiter_Invalid(t *testing.T) {
+	if _, err := NewRateLimiter(0, time.Second, 1); err == nil {
+		t.Errorf("expected an error for a zero request rate")
+	}
+	if _, err := NewClient(API_KEY, JSON, WithRateLimiter(nil)); err == nil {
+		t.Errorf("expected an error for a nil rate limiter")
+	}
+}
+
+func TestRateLimiter_Client(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"categories":[{"id":125,"name":"Trade Balance","parent_id":13}]}`))
+	}))
+	defer server.Close()
+
+	client, err := NewClient(API_KEY, JSON, WithBaseURL(server.URL), WithRateLimit(1, time.Hour, 1))
+	if err != nil {
+		t.Fatalf("could not create client: %v", err)
+	}
+
+	if _, err := client.Category(125); err != nil {
+		t.Fatal(err)
+	}
+	if delay := client.RateLimitDelay(); delay <= 0 {
+		t.Errorf("expected the next request to wait, got: %v", delay)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := client.CategoryContext(ctx, 125); err == nil {
+		t.Errorf("expected rate limited request to fail when the context expires")
+	}
+}
@@ -0,0 +1,173 @@
+package gofred
+
+import (
+	"context"
+	"fmt"
+)
+
+//==============================================================================
+// pagination
+//==============================================================================
+
+// Fetches the page starting at `offset`, buffering its items in the owning
+// iterator and returning the total number of items and the number on the page.
+type pageFetcher func(ctx context.Context, offset uint) (count uint, fetched int, err Error)
+
+// Walks every page of a paged endpoint, one item at a time.
+//
+// Embedded in the typed iterators, which hold the current page.
+type pager struct {
+	ctx   context.Context
+	fetch pageFetcher
+
+	offset  uint // offset of the next page
+	count   uint // total items, as reported by the last page
+	index   int  // position in the current page
+	size    int  // items in the current page
+	started bool
+	done    bool
+	err     Error
+}
+
+func new_pager(ctx context.Context, offset uint, fetch pageFetcher) pager {
+	return pager{ctx: ctx, fetch: fetch, offset: offset, index: -1}
+}
+
+// Advance to the next item, fetching the next page if the current one is exhausted.
+func (p *pager) next() bool {
+	if p.done {
+		return false
+	}
+
+	p.index++
+	if p.index < p.size {
+		return true
+	}
+
+	if p.started && p.offset >= p.count {
+		p.done = true
+		return false
+	}
+	if err := p.ctx.Err(); err != nil {
+		p.err = &APIError{ty: HTTPError, msg: fmt.Sprintf("stopped paging: %v", err), err: err}
+		p.done = true
+		return false
+	}
+
+	count, fetched, err := p.fetch(p.ctx, p.offset)
+	if err != nil {
+		p.err = err
+		p.done = true
+		return false
+	}
+
+	p.started = true
+	p.count = count
+	p.offset += uint(fetched)
+	p.index = 0
+	p.size = fetched
+
+	if fetched == 0 {
+		p.done = true
+		return false
+	}
+	return true
+}
+
+// Iterates over every `Series` of a paged endpoint.
+//
+//	it := client.SeriesSearchAll(ctx, req)
+//	for it.Next() {
+//		fmt.Println(it.Series().Title)
+//	}
+//	if err := it.Err(); err != nil {
+//		...
+//	}
+type SeriesIterator struct {
+	pager
+	page []Series
+}
+
+// Advance to the next series, returning false once there are none left or an error occurred.
+func (it *SeriesIterator) Next() bool { return it.next() }
+
+// The current series.
+func (it *SeriesIterator) Series() Series { return it.page[it.index] }
+
+// The error that stopped iteration, if any.
+func (it *SeriesIterator) Err() Error { return it.err }
+
+// Iterates over every `Tag` of a paged endpoint.
+type TagIterator struct {
+	pager
+	page []Tag
+}
+
+// Advance to the next tag, returning false once there are none left or an error occurred.
+func (it *TagIterator) Next() bool { return it.next() }
+
+// The current tag.
+func (it *TagIterator) Tag() Tag { return it.page[it.index] }
+
+// The error that stopped iteration, if any.
+func (it *TagIterator) Err() Error { return it.err }
+
+// Iterates over every observation of a series.
+type ObservationIterator struct {
+	pager
+	page []DataPoint
+}
+
+// Advance to the next observation, returning false once there are none left or an error occurred.
+func (it *ObservationIterator) Next() bool { return it.next() }
+
+// The current observation.
+func (it *ObservationIterator) Observation() DataPoint { return it.page[it.index] }
+
+// The error that stopped iteration, if any.
+func (it *ObservationIterator) Err() Error { return it.err }
+
+// Iterates over every `Release` of a paged endpoint.
+type ReleaseIterator struct {
+	pager
+	page []Release
+}
+
+// Advance to the next release, returning false once there are none left or an error occurred.
+func (it *ReleaseIterator) Next() bool { return it.next() }
+
+// The current release.
+func (it *ReleaseIterator) Release() Release { return it.page[it.index] }
+
+// The error that stopped iteration, if any.
+func (it *ReleaseIterator) Err() Error { return it.err }
+
+// Iterates over every `Source` of a paged endpoint.
+type SourceIterator struct {
+	pager
+	page []Source
+}
+
+// Advance to the next source, returning false once there are none left or an error occurred.
+func (it *SourceIterator) Next() bool { return it.next() }
+
+// The current source.
+func (it *SourceIterator) Source() Source { return it.page[it.index] }
+
+// The error that stopped iteration, if any.
+func (it *SourceIterator) Err() Error { return it.err }
+
+// Iterates over every `Date` of a paged endpoint.
+type DateIterator struct {
+	pager
+	page []Date
+}
+
+// Advance to the next date, returning false once there are none left or an error occurred.
+func (it *DateIterator) Next() bool { return it.next() }
+
+// The current date.
+func (it *DateIterator) Date() Date { return it.page[it.index] }
+
+// The error that stopped iteration, if any.
+func (it *DateIterator) Err() Error { return it.err }
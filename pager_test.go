@@ -0,0 +1,130 @@
+package gofred
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// Serves `series/search` over `total` series, `limit` per page.
+func new_pager_test_server(total int) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+		limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+		if limit == 0 {
+			limit = 1000
+		}
+
+		series := []string{}
+		for i := offset; i < offset+limit && i < total; i++ {
+			series = append(series, fmt.Sprintf(`{"id":"S%d"}`, i))
+		}
+
+		fmt.Fprintf(w, `{"count":%d,"offset":%d,"limit":%d,"seriess":[%s]}`,
+			total, offset, limit, strings.Join(series, ","))
+	}))
+}
+
+func TestPager_WalksAllPages(t *testing.T) {
+	server := new_pager_test_server(5)
+	defer server.Close()
+
+	client, err := NewClient(API_KEY, JSON, WithBaseURL(server.URL))
+	if err != nil {
+		t.Fatalf("could not create client: %v", err)
+	}
+
+	req := NewSeriesSearchRequest("anything", SearchTypeNone)
+	req.Limit = 2
+
+	ids := []string{}
+	it := client.SeriesSearchAll(context.Background(), req)
+	for it.Next() {
+		ids = append(ids, it.Series().Id)
+	}
+	if err := it.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	expect := "S0,S1,S2,S3,S4"
+	if got := strings.Join(ids, ","); got != expect {
+		t.Errorf("expected series %s, got: %s", expect, got)
+	}
+	if it.Next() {
+		t.Errorf("exhausted iterator should stay exhausted")
+	}
+}
+
+func TestPager_Empty(t *testing.T) {
+	server := new_pager_test_server(0)
+	defer server.Close()
+
+	client, err := NewClient(API_KEY, JSON, WithBaseURL(server.URL))
+	if err != nil {
+		t.Fatalf("could not create client: %v", err)
+	}
+
+	it := client.SeriesSearchAll(context.Background(), NewSeriesSearchRequest("anything", SearchTypeNone))
+	if it.Next() {
+		t.Errorf("expected no series, got: %+v", it.Series())
+	}
+	if err := it.Err(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestPager_Cancelled(t *testing.T) {
+	server := new_pager_test_server(5)
+	defer server.Close()
+
+	client, err := NewClient(API_KEY, JSON, WithBaseURL(server.URL))
+	if err != nil {
+		t.Fatalf("could not create client: %v", err)
+	}
+
+	req := NewSeriesSearchRequest("anything", SearchTypeNone)
+	req.Limit = 2
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	it := client.SeriesSearchAll(ctx, req)
+
+	seen := 0
+	for it.Next() {
+		seen++
+		if seen == 2 {
+			cancel()
+		}
+	}
+	if seen != 2 {
+		t.Errorf("expected to stop after the first page, saw %d series", seen)
+	}
+	if it.Err() == nil {
+		t.Errorf("expected an error after cancelling")
+	}
+}
+
+func TestPager_Error(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"error_code":400,"error_message":"Bad Request."}`))
+	}))
+	defer server.Close()
+
+	client, err := NewClient(API_KEY, JSON, WithBaseURL(server.URL))
+	if err != nil {
+		t.Fatalf("could not create client: %v", err)
+	}
+
+	it := client.CategoryTagsAll(context.Background(), NewCategoryTagsRequest(125, TagNone, ""))
+	if it.Next() {
+		t.Errorf("expected no tags, got: %+v", it.Tag())
+	}
+	if it.Err() == nil || it.Err().Type() != Invalid {
+		t.Errorf("expected an invalid request error, got: %v", it.Err())
+	}
+}
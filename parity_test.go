@@ -0,0 +1,240 @@
+package gofred
+
+import (
+	"errors"
+	"io/ioutil"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Recorded responses, one `.json` and one `.xml` file per fixture.
+const PARITY_DIR = "testdata/parity"
+
+// Serves `PARITY_DIR/<fixture>.<file_type>`, the fixture being named after the
+// request path, e.g. `series_observations` for `/fred/series/observations` and
+// `geofred_series_group` for `/geofred/series/group`. Non-default output types
+// are appended, e.g. `series_observations_output2`.
+//
+// Error fixtures are served with a 400 status.
+func new_parity_server(t *testing.T) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path := strings.TrimPrefix(strings.TrimPrefix(r.URL.Path, "/"), "fred/")
+		fixture := strings.Replace(path, "/", "_", -1)
+		if output := r.URL.Query().Get("output_type"); output != "" && output != "1" {
+			fixture += "_output" + output
+		}
+
+		body, err := ioutil.ReadFile(filepath.Join(PARITY_DIR, fixture+"."+r.URL.Query().Get("file_type")))
+		if err != nil {
+			t.Errorf("no fixture for %s: %v", r.URL.Path, err)
+			http.NotFound(w, r)
+			return
+		}
+
+		if strings.Contains(string(body), "error_code") || strings.Contains(string(body), "<error ") {
+			w.WriteHeader(http.StatusBadRequest)
+		}
+		w.Write(body)
+	}))
+}
+
+var time_type = reflect.TypeOf(time.Time{})
+
+// Deep equality where times are compared with `Equal`, as those parsed with an
+// offset have their own location, and nil slices and maps equal empty ones.
+func equivalent(a, b reflect.Value) bool {
+	if a.Type() != b.Type() {
+		return false
+	}
+	if a.Kind() == reflect.Struct && a.Type().ConvertibleTo(time_type) && a.CanInterface() {
+		return a.Convert(time_type).Interface().(time.Time).Equal(b.Convert(time_type).Interface().(time.Time))
+	}
+
+	switch a.Kind() {
+	case reflect.Slice, reflect.Array:
+		if a.Len() != b.Len() {
+			return false
+		}
+		for i := 0; i < a.Len(); i++ {
+			if !equivalent(a.Index(i), b.Index(i)) {
+				return false
+			}
+		}
+		return true
+	case reflect.Map:
+		if a.Len() != b.Len() {
+			return false
+		}
+		for _, key := range a.MapKeys() {
+			value := b.MapIndex(key)
+			if !value.IsValid() || !equivalent(a.MapIndex(key), value) {
+				return false
+			}
+		}
+		return true
+	case reflect.Struct:
+		for i := 0; i < a.NumField(); i++ {
+			if !equivalent(a.Field(i), b.Field(i)) {
+				return false
+			}
+		}
+		return true
+	case reflect.Ptr, reflect.Interface:
+		if a.IsNil() || b.IsNil() {
+			return a.IsNil() == b.IsNil()
+		}
+		return equivalent(a.Elem(), b.Elem())
+	case reflect.Bool:
+		return a.Bool() == b.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return a.Int() == b.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return a.Uint() == b.Uint()
+	case reflect.Float32, reflect.Float64:
+		return a.Float() == b.Float() || (math.IsNaN(a.Float()) && math.IsNaN(b.Float()))
+	case reflect.String:
+		return a.String() == b.String()
+	}
+	return false
+}
+
+// Every fixture in `PARITY_DIR`, with the request which is answered by it.
+var parity_cases = map[string]func(Client) (interface{}, Error){
+	"category": func(c Client) (interface{}, Error) { return c.Category(125) },
+	"category_children": func(c Client) (interface{}, Error) {
+		return c.CategoryChildren(13, time.Time{}, time.Time{})
+	},
+	"category_series": func(c Client) (interface{}, Error) {
+		return c.SeriesInCategory(NewCategorySeriesRequest(125))
+	},
+	"category_related": func(c Client) (interface{}, Error) {
+		return c.RelatedCategories(32073, time.Time{}, time.Time{})
+	},
+	"category_tags": func(c Client) (interface{}, Error) {
+		return c.CategoryTags(NewCategoryTagsRequest(125, TagNone, ""))
+	},
+	"category_related_tags": func(c Client) (interface{}, Error) {
+		return c.CategoryRelatedTags(NewCategoryRelatedTagsRequest(125, "services", "quarterly"))
+	},
+
+	"series":            func(c Client) (interface{}, Error) { return c.Series(NewSeriesRequest("GNPCA")) },
+	"series_categories": func(c Client) (interface{}, Error) { return c.CategoriesForSeries(NewSeriesRequest("EXJPUS")) },
+	"series_observations": func(c Client) (interface{}, Error) {
+		return c.SeriesObservations(NewSeriesObservationsRequest("GNPCA", time.Time{}, time.Time{}))
+	},
+	"series_observations_output2": func(c Client) (interface{}, Error) {
+		return c.SeriesVintageObservations(NewSeriesObservationsRequest("GNPCA", time.Time{}, time.Time{}))
+	},
+	"series_release": func(c Client) (interface{}, Error) { return c.SeriesRelease(NewSeriesRequest("GNPCA")) },
+	"series_search": func(c Client) (interface{}, Error) {
+		return c.SeriesSearch(NewSeriesSearchRequest("monetary", SearchFullText))
+	},
+	"series_search_tags": func(c Client) (interface{}, Error) {
+		return c.SeriesSearchTags(NewSeriesSearchTagsRequest("monetary"))
+	},
+	"series_search_related_tags": func(c Client) (interface{}, Error) {
+		return c.SeriesSearchRelatedTags(NewSeriesSearchTagsRequest("monetary", "frb"))
+	},
+	"series_tags":    func(c Client) (interface{}, Error) { return c.SeriesTags(NewSeriesTagsRequest("BOPGSTB")) },
+	"series_updates": func(c Client) (interface{}, Error) { return c.SeriesUpdates(NewSeriesUpdatesRequest(FilterMacro)) },
+	"series_vintagedates": func(c Client) (interface{}, Error) {
+		return c.SeriesVintageDates(NewSeriesVintageDatesRequest("GNPCA"))
+	},
+
+	"releases": func(c Client) (interface{}, Error) { return c.Releases(NewReleasesRequest()) },
+	"releases_dates": func(c Client) (interface{}, Error) {
+		return c.ReleasesDates(NewReleasesDatesRequest(time.Time{}, time.Time{}))
+	},
+	"release":         func(c Client) (interface{}, Error) { return c.Release(NewReleaseRequest(53)) },
+	"release_dates":   func(c Client) (interface{}, Error) { return c.ReleaseDates(NewReleaseDatesRequest(53)) },
+	"release_series":  func(c Client) (interface{}, Error) { return c.ReleaseSeries(NewReleaseSeriesRequest(53)) },
+	"release_sources": func(c Client) (interface{}, Error) { return c.ReleaseSources(NewReleaseRequest(53)) },
+	"release_tags": func(c Client) (interface{}, Error) {
+		return c.ReleaseTags(NewReleaseTagsRequest(53, TagNone, ""))
+	},
+	"release_related_tags": func(c Client) (interface{}, Error) {
+		return c.ReleaseRelatedTags(NewReleaseRelatedTagsRequest(53, "gdp"))
+	},
+	"release_tables": func(c Client) (interface{}, Error) { return c.ReleaseTables(NewReleaseTablesRequest(53)) },
+
+	"sources":         func(c Client) (interface{}, Error) { return c.Sources(NewSourcesRequest()) },
+	"source":          func(c Client) (interface{}, Error) { return c.Source(NewSourceRequest(999999)) },
+	"source_releases": func(c Client) (interface{}, Error) { return c.SourceReleases(NewSourceReleasesRequest(18)) },
+
+	"tags":         func(c Client) (interface{}, Error) { return c.Tags(NewTagsRequest(TagNone, "")) },
+	"related_tags": func(c Client) (interface{}, Error) { return c.RelatedTags(NewRelatedTagsRequest("monthly", "usa")) },
+	"tags_series":  func(c Client) (interface{}, Error) { return c.TagsSeries(NewTagsSeriesRequest("usa")) },
+
+	// shapes are GeoJSON whatever the format, and have no XML to compare
+	"geofred_series_group": func(c Client) (interface{}, Error) {
+		return c.GeoSeriesGroup(NewGeoSeriesRequest("WYPCPI"))
+	},
+	"geofred_series_data": func(c Client) (interface{}, Error) {
+		return c.GeoSeriesData(NewGeoSeriesRequest("WYPCPI"))
+	},
+	"geofred_regional_data": func(c Client) (interface{}, Error) {
+		group := SeriesGroup{Id: "882", RegionType: RegionState, Season: "NSA", Units: "Dollars"}
+		req := NewRegionalDataRequest(group, time.Date(2023, time.January, 1, 0, 0, 0, 0, time.UTC))
+		req.StartDate = time.Date(2022, time.January, 1, 0, 0, 0, 0, time.UTC)
+		return c.GeoRegionalData(req)
+	},
+}
+
+func TestParity_Fixtures(t *testing.T) {
+	fixtures, err := filepath.Glob(filepath.Join(PARITY_DIR, "*.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(fixtures) != len(parity_cases) {
+		t.Errorf("expected %d fixtures, found %d", len(parity_cases), len(fixtures))
+	}
+	for _, fixture := range fixtures {
+		name := strings.TrimSuffix(filepath.Base(fixture), ".json")
+		if _, exists := parity_cases[name]; !exists {
+			t.Errorf("fixture %s is not decoded by any test case", name)
+		}
+	}
+}
+
+func TestParity_JSONAndXML(t *testing.T) {
+	server := new_parity_server(t)
+	defer server.Close()
+	js_client := make_client(t, JSON, WithBaseURL(server.URL+"/fred"))
+	xml_client := make_client(t, XML, WithBaseURL(server.URL+"/fred"))
+
+	for name, call := range parity_cases {
+		js, js_err := call(js_client)
+		xml, xml_err := call(xml_client)
+
+		if js_err != nil || xml_err != nil {
+			var js_api, xml_api *APIError
+			if !errors.As(js_err, &js_api) || !errors.As(xml_err, &xml_api) {
+				t.Errorf("%s: expected errors from both formats, got: %v and %v", name, js_err, xml_err)
+				continue
+			}
+			if js_api.Type() != xml_api.Type() || js_api.ErrorCode() != xml_api.ErrorCode() ||
+				js_api.ErrorMessage() != xml_api.ErrorMessage() {
+				t.Errorf("%s: json and xml errors differ:\n%v (%d: %s)\n%v (%d: %s)", name,
+					js_api, js_api.ErrorCode(), js_api.ErrorMessage(),
+					xml_api, xml_api.ErrorCode(), xml_api.ErrorMessage())
+			}
+			if js_api.ErrorCode() == 0 || js_api.ErrorMessage() == "" {
+				t.Errorf("%s: expected the error code and message to be decoded, got: %v", name, js_api)
+			}
+			continue
+		}
+
+		if reflect.ValueOf(js).IsZero() {
+			t.Errorf("%s: decoded nothing from json", name)
+		}
+		if !equivalent(reflect.ValueOf(js), reflect.ValueOf(xml)) {
+			t.Errorf("%s: json and xml decode differently:\n%+v\n%+v", name, js, xml)
+		}
+	}
+}
@@ -0,0 +1,877 @@
+package gofred
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// A release of economic data, such as the Employment Situation.
+type Release struct {
+	Id           uint   `json:"id" xml:"id,attr"`
+	Start        Date   `json:"realtime_start" xml:"realtime_start,attr"`
+	End          Date   `json:"realtime_end" xml:"realtime_end,attr"`
+	Name         string `json:"name" xml:"name,attr"`
+	PressRelease bool   `json:"press_release" xml:"press_release,attr"`
+	Link         string `json:"link" xml:"link,attr"`
+	Notes        string `json:"notes" xml:"notes,attr"`
+}
+
+// A date on which a release was (or will be) published.
+//
+// `ReleaseName` is only given by `ReleasesDates`.
+type ReleaseDate struct {
+	ReleaseId   uint   `json:"release_id" xml:"release_id,attr"`
+	ReleaseName string `json:"release_name" xml:"release_name,attr"`
+	Date        Date   `json:"date" xml:",chardata"`
+}
+
+// Largest page `release/dates` accepts
+const MAX_RELEASE_DATES_LIMIT = 10000
+
+//==============================================================================
+//
+// GET: /fred/releases
+//
+//==============================================================================
+
+// Holds the data needed to request every `Release`.
+type ReleasesRequest struct {
+	baseRequest
+	DatedRequest
+	PagedRequest
+	OrderedRequest
+}
+
+func NewReleasesRequest() ReleasesRequest {
+	return ReleasesRequest{}
+}
+
+// Satisfies the `Request` interface.
+func (r ReleasesRequest) ToParams() url.Values {
+	v := r.baseRequest.ToParams()
+	r.DatedRequest.MergeParams(v)
+	r.PagedRequest.MergeParams(v)
+	r.OrderedRequest.MergeParams(v)
+	return v
+}
+
+// Satisfies the `Request` interface.
+func (r ReleasesRequest) Validate() error {
+	return validate_all(
+		r.DatedRequest.Validate(),
+		r.PagedRequest.Validate(),
+		r.OrderedRequest.Validate(),
+	)
+}
+
+type ReleasesResponse struct {
+	Start    Date      `json:"realtime_start" xml:"realtime_start,attr"`
+	End      Date      `json:"realtime_end" xml:"realtime_end,attr"`
+	Order    OrderType `json:"order_by" xml:"order_by,attr"`
+	Sort     SortType  `json:"sort_order" xml:"sort_order,attr"`
+	Count    uint      `json:"count" xml:"count,attr"`
+	Offset   uint      `json:"offset" xml:"offset,attr"`
+	Limit    uint      `json:"limit" xml:"limit,attr"`
+	Releases []Release `json:"releases" xml:"release"`
+}
+
+// Get every `Release` of economic data.
+func (c Client) Releases(req ReleasesRequest) (ReleasesResponse, Error) {
+	return c.ReleasesContext(context.Background(), req)
+}
+
+// Same as `Releases`, but the request is bound to the given context.
+func (c Client) ReleasesContext(ctx context.Context, req ReleasesRequest) (ReleasesResponse, Error) {
+	req.baseRequest = c.base_req
+	req_url := c.base_url
+	req_url.RawQuery = req.ToParams().Encode()
+	req_url.Path = fmt.Sprintf("%s/releases", req_url.Path)
+	if err := req.Validate(); err != nil {
+		return ReleasesResponse{}, c.invalid_request(req_url.String(), "releases", err)
+	}
+
+	body, err := c.get(ctx, "releases", req_url.String())
+	if err != nil {
+		return ReleasesResponse{}, err.Prefixf("error getting releases")
+	}
+
+	var result ReleasesResponse
+	err = c.unmarshal_body(req_url.String(), body, &result)
+	return result, err
+}
+
+// Iterate over every `Release`, fetching pages of `req.Limit` items starting
+// from `req.Offset` until every page has been read, an error occurs or `ctx`
+// is done.
+func (c Client) ReleasesAll(ctx context.Context, req ReleasesRequest) *ReleaseIterator {
+	it := &ReleaseIterator{}
+	it.pager = new_pager(ctx, req.Offset, func(ctx context.Context, offset uint) (uint, int, Error) {
+		req.Offset = offset
+		res, err := c.ReleasesContext(ctx, req)
+		if err != nil {
+			return 0, 0, err
+		}
+		it.page = res.Releases
+		return res.Count, len(res.Releases), nil
+	})
+	return it
+}
+
+//==============================================================================
+//
+// GET: /fred/releases/dates
+//
+//==============================================================================
+
+// Holds the data needed to request the release dates of every release.
+type ReleasesDatesRequest struct {
+	baseRequest
+	DatedRequest
+	PagedRequest
+	OrderedRequest
+
+	IncludeEmpty bool // include dates on which no data was released
+}
+
+func NewReleasesDatesRequest(start, end time.Time) ReleasesDatesRequest {
+	return ReleasesDatesRequest{
+		DatedRequest: DatedRequest{
+			Start: Date(start),
+			End:   Date(end),
+		},
+	}
+}
+
+// Satisfies the `Request` interface.
+func (r ReleasesDatesRequest) ToParams() url.Values {
+	v := r.baseRequest.ToParams()
+	r.DatedRequest.MergeParams(v)
+	r.PagedRequest.MergeParams(v)
+	r.OrderedRequest.MergeParams(v)
+
+	if r.IncludeEmpty {
+		v.Set("include_release_dates_with_no_data", "true")
+	}
+
+	return v
+}
+
+// Satisfies the `Request` interface.
+func (r ReleasesDatesRequest) Validate() error {
+	return validate_all(
+		r.DatedRequest.Validate(),
+		r.PagedRequest.Validate(),
+		r.OrderedRequest.Validate(),
+	)
+}
+
+type ReleaseDatesResponse struct {
+	Start  Date          `json:"realtime_start" xml:"realtime_start,attr"`
+	End    Date          `json:"realtime_end" xml:"realtime_end,attr"`
+	Order  OrderType     `json:"order_by" xml:"order_by,attr"`
+	Sort   SortType      `json:"sort_order" xml:"sort_order,attr"`
+	Count  uint          `json:"count" xml:"count,attr"`
+	Offset uint          `json:"offset" xml:"offset,attr"`
+	Limit  uint          `json:"limit" xml:"limit,attr"`
+	Dates  []ReleaseDate `json:"release_dates" xml:"release_date"`
+}
+
+// Get the release dates of every release within the realtime period.
+func (c Client) ReleasesDates(req ReleasesDatesRequest) (ReleaseDatesResponse, Error) {
+	return c.ReleasesDatesContext(context.Background(), req)
+}
+
+// Same as `ReleasesDates`, but the request is bound to the given context.
+func (c Client) ReleasesDatesContext(ctx context.Context, req ReleasesDatesRequest) (ReleaseDatesResponse, Error) {
+	req.baseRequest = c.base_req
+	req_url := c.base_url
+	req_url.RawQuery = req.ToParams().Encode()
+	req_url.Path = fmt.Sprintf("%s/releases/dates", req_url.Path)
+	if err := req.Validate(); err != nil {
+		return ReleaseDatesResponse{}, c.invalid_request(req_url.String(), "releases dates", err)
+	}
+
+	body, err := c.get(ctx, "releases dates", req_url.String())
+	if err != nil {
+		return ReleaseDatesResponse{}, err.Prefixf("error getting releases dates")
+	}
+
+	var result ReleaseDatesResponse
+	err = c.unmarshal_body(req_url.String(), body, &result)
+	return result, err
+}
+
+//==============================================================================
+//
+// GET: /fred/release
+//
+//==============================================================================
+
+// Holds the data needed to request information on a `Release`.
+type ReleaseRequest struct {
+	baseRequest
+	DatedRequest
+	Release uint
+}
+
+func NewReleaseRequest(release uint) ReleaseRequest {
+	return ReleaseRequest{
+		Release: release,
+	}
+}
+
+// Satisfies the `Request` interface.
+func (r ReleaseRequest) ToParams() url.Values {
+	v := r.baseRequest.ToParams()
+	r.DatedRequest.MergeParams(v)
+	v.Set("release_id", fmt.Sprint(r.Release))
+	return v
+}
+
+// Satisfies the `Request` interface.
+func (r ReleaseRequest) Validate() error {
+	return r.DatedRequest.Validate()
+}
+
+// Response type which _should_ contain only one release.
+type releaseResponse struct {
+	Start    Date      `json:"realtime_start" xml:"realtime_start,attr"`
+	End      Date      `json:"realtime_end" xml:"realtime_end,attr"`
+	Releases []Release `json:"releases" xml:"release"`
+}
+
+// Get the `Release` information for the given release ID.
+//
+// Asserts there is only one `Release` object in the result, and returns it.
+func (c Client) Release(req ReleaseRequest) (Release, Error) {
+	return c.ReleaseContext(context.Background(), req)
+}
+
+// Same as `Release`, but the request is bound to the given context.
+func (c Client) ReleaseContext(ctx context.Context, req ReleaseRequest) (Release, Error) {
+	req.baseRequest = c.base_req
+	req_url := c.base_url
+	req_url.RawQuery = req.ToParams().Encode()
+	req_url.Path = fmt.Sprintf("%s/release", req_url.Path)
+	if err := req.Validate(); err != nil {
+		return Release{}, c.invalid_request(req_url.String(), "release", err)
+	}
+
+	body, err := c.get(ctx, "release", req_url.String())
+	if err != nil {
+		return Release{}, err.Prefixf("error getting release %d:", req.Release)
+	}
+
+	// parse the correct format
+	var result releaseResponse
+	err = c.unmarshal_body(req_url.String(), body, &result)
+	if err != nil {
+		return Release{}, err.Prefixf("could not get release %d:", req.Release)
+	}
+
+	// pull out the singular release
+	switch len(result.Releases) {
+	case 0:
+		return Release{}, &APIError{
+			ty:  UnexpectedCount,
+			msg: fmt.Sprintf("received an empty release list"),
+		}
+	case 1:
+		return result.Releases[0], nil
+	default:
+		return Release{}, &APIError{
+			ty:  UnexpectedCount,
+			msg: fmt.Sprintf("expected only a single release, received %d", len(result.Releases)),
+		}
+	}
+}
+
+//==============================================================================
+//
+// GET: /fred/release/dates
+//
+//==============================================================================
+
+// Holds the data needed to request the dates a `Release` was published.
+type ReleaseDatesRequest struct {
+	baseRequest
+	DatedRequest
+	PagedRequest
+	OrderedRequest
+
+	Release      uint
+	IncludeEmpty bool // include dates on which no data was released
+}
+
+func NewReleaseDatesRequest(release uint) ReleaseDatesRequest {
+	return ReleaseDatesRequest{
+		Release: release,
+	}
+}
+
+// Satisfies the `Request` interface.
+func (r ReleaseDatesRequest) ToParams() url.Values {
+	v := r.baseRequest.ToParams()
+	r.DatedRequest.MergeParams(v)
+	r.PagedRequest.MergeParams(v)
+	r.OrderedRequest.MergeParams(v)
+
+	v.Set("release_id", fmt.Sprint(r.Release))
+	if r.IncludeEmpty {
+		v.Set("include_release_dates_with_no_data", "true")
+	}
+
+	return v
+}
+
+// Satisfies the `Request` interface.
+//
+// Release dates allow pages of up to `MAX_RELEASE_DATES_LIMIT`.
+func (r ReleaseDatesRequest) Validate() error {
+	return validate_all(
+		r.DatedRequest.Validate(),
+		r.PagedRequest.validate_limit(MAX_RELEASE_DATES_LIMIT),
+		r.OrderedRequest.Validate(),
+	)
+}
+
+// Get the dates the given release was published.
+func (c Client) ReleaseDates(req ReleaseDatesRequest) (ReleaseDatesResponse, Error) {
+	return c.ReleaseDatesContext(context.Background(), req)
+}
+
+// Same as `ReleaseDates`, but the request is bound to the given context.
+func (c Client) ReleaseDatesContext(ctx context.Context, req ReleaseDatesRequest) (ReleaseDatesResponse, Error) {
+	req.baseRequest = c.base_req
+	req_url := c.base_url
+	req_url.RawQuery = req.ToParams().Encode()
+	req_url.Path = fmt.Sprintf("%s/release/dates", req_url.Path)
+	if err := req.Validate(); err != nil {
+		return ReleaseDatesResponse{}, c.invalid_request(req_url.String(), "release dates", err)
+	}
+
+	body, err := c.get(ctx, "release dates", req_url.String())
+	if err != nil {
+		return ReleaseDatesResponse{}, err.Prefixf("error getting dates of release %d", req.Release)
+	}
+
+	var result ReleaseDatesResponse
+	err = c.unmarshal_body(req_url.String(), body, &result)
+	return result, err
+}
+
+//==============================================================================
+//
+// GET: /fred/release/series
+//
+//==============================================================================
+
+// Holds the data needed to request the `Series` in a release.
+type ReleaseSeriesRequest struct {
+	baseRequest
+	DatedRequest
+	PagedRequest
+	OrderedRequest
+	FilteredRequest
+	TaggedRequest
+
+	Release uint
+}
+
+func NewReleaseSeriesRequest(release uint) ReleaseSeriesRequest {
+	return ReleaseSeriesRequest{
+		Release: release,
+	}
+}
+
+// Satisfies the `Request` interface.
+func (r ReleaseSeriesRequest) ToParams() url.Values {
+	v := r.baseRequest.ToParams()
+	r.DatedRequest.MergeParams(v)
+	r.PagedRequest.MergeParams(v)
+	r.OrderedRequest.MergeParams(v)
+	r.FilteredRequest.MergeParams(v)
+	r.TaggedRequest.MergeParams(v)
+
+	v.Set("release_id", fmt.Sprint(r.Release))
+
+	return v
+}
+
+// Satisfies the `Request` interface.
+func (r ReleaseSeriesRequest) Validate() error {
+	return validate_all(
+		r.DatedRequest.Validate(),
+		r.PagedRequest.Validate(),
+		r.OrderedRequest.Validate(),
+		r.FilteredRequest.Validate(),
+		r.TaggedRequest.Validate(),
+	)
+}
+
+type ReleaseSeriesResponse struct {
+	Start  Date      `json:"realtime_start" xml:"realtime_start,attr"`
+	End    Date      `json:"realtime_end" xml:"realtime_end,attr"`
+	Order  OrderType `json:"order_by" xml:"order_by,attr"`
+	Sort   SortType  `json:"sort_order" xml:"sort_order,attr"`
+	Count  uint      `json:"count" xml:"count,attr"`
+	Offset uint      `json:"offset" xml:"offset,attr"`
+	Limit  uint      `json:"limit" xml:"limit,attr"`
+	Series []Series  `json:"seriess" xml:"series"`
+}
+
+// Get the `Series` in the given release.
+func (c Client) ReleaseSeries(req ReleaseSeriesRequest) (ReleaseSeriesResponse, Error) {
+	return c.ReleaseSeriesContext(context.Background(), req)
+}
+
+// Same as `ReleaseSeries`, but the request is bound to the given context.
+func (c Client) ReleaseSeriesContext(ctx context.Context, req ReleaseSeriesRequest) (ReleaseSeriesResponse, Error) {
+	req.baseRequest = c.base_req
+	req_url := c.base_url
+	req_url.RawQuery = req.ToParams().Encode()
+	req_url.Path = fmt.Sprintf("%s/release/series", req_url.Path)
+	if err := req.Validate(); err != nil {
+		return ReleaseSeriesResponse{}, c.invalid_request(req_url.String(), "release series", err)
+	}
+
+	body, err := c.get(ctx, "release series", req_url.String())
+	if err != nil {
+		return ReleaseSeriesResponse{}, err.Prefixf("error getting series in release %d", req.Release)
+	}
+
+	var result ReleaseSeriesResponse
+	err = c.unmarshal_body(req_url.String(), body, &result)
+	return result, err
+}
+
+// Iterate over every `Series` in the release, fetching pages of `req.Limit`
+// items starting from `req.Offset` until every page has been read, an error
+// occurs or `ctx` is done.
+func (c Client) ReleaseSeriesAll(ctx context.Context, req ReleaseSeriesRequest) *SeriesIterator {
+	it := &SeriesIterator{}
+	it.pager = new_pager(ctx, req.Offset, func(ctx context.Context, offset uint) (uint, int, Error) {
+		req.Offset = offset
+		res, err := c.ReleaseSeriesContext(ctx, req)
+		if err != nil {
+			return 0, 0, err
+		}
+		it.page = res.Series
+		return res.Count, len(res.Series), nil
+	})
+	return it
+}
+
+//==============================================================================
+//
+// GET: /fred/release/sources
+//
+//==============================================================================
+
+// Response type for the sources of a release.
+type releaseSourcesResponse struct {
+	Start   Date     `json:"realtime_start" xml:"realtime_start,attr"`
+	End     Date     `json:"realtime_end" xml:"realtime_end,attr"`
+	Sources []Source `json:"sources" xml:"source"`
+}
+
+// Get the `Source`s of the given release.
+func (c Client) ReleaseSources(req ReleaseRequest) ([]Source, Error) {
+	return c.ReleaseSourcesContext(context.Background(), req)
+}
+
+// Same as `ReleaseSources`, but the request is bound to the given context.
+func (c Client) ReleaseSourcesContext(ctx context.Context, req ReleaseRequest) ([]Source, Error) {
+	req.baseRequest = c.base_req
+	req_url := c.base_url
+	req_url.RawQuery = req.ToParams().Encode()
+	req_url.Path = fmt.Sprintf("%s/release/sources", req_url.Path)
+	if err := req.Validate(); err != nil {
+		return nil, c.invalid_request(req_url.String(), "release sources", err)
+	}
+
+	body, err := c.get(ctx, "release sources", req_url.String())
+	if err != nil {
+		return nil, err.Prefixf("error getting sources of release %d", req.Release)
+	}
+
+	var result releaseSourcesResponse
+	err = c.unmarshal_body(req_url.String(), body, &result)
+	if err != nil {
+		return nil, err.Prefixf("could not get sources of release %d", req.Release)
+	}
+
+	return result.Sources, nil
+}
+
+//==============================================================================
+//
+// GET: /fred/release/tags
+//
+//==============================================================================
+
+// Holds the data needed to request the `Tag`s of a release, or the tags related
+// to a set of tags within a release.
+type ReleaseTagsRequest struct {
+	baseRequest
+	DatedRequest
+	TaggedRequest
+	PagedRequest
+	OrderedRequest
+
+	Release    uint
+	TagGroupId TagId
+	Search     string
+}
+
+func NewReleaseTagsRequest(release uint, tag_id TagId, search string) ReleaseTagsRequest {
+	return ReleaseTagsRequest{
+		Release:    release,
+		TagGroupId: tag_id,
+		Search:     search,
+	}
+}
+
+func NewReleaseRelatedTagsRequest(release uint, tags ...string) ReleaseTagsRequest {
+	return ReleaseTagsRequest{
+		TaggedRequest: TaggedRequest{
+			Tags: tags,
+		},
+		Release: release,
+	}
+}
+
+// Satisfies the `Request` interface.
+func (r ReleaseTagsRequest) ToParams() url.Values {
+	v := r.baseRequest.ToParams()
+	r.DatedRequest.MergeParams(v)
+	r.TaggedRequest.MergeParams(v)
+	r.PagedRequest.MergeParams(v)
+	r.OrderedRequest.MergeParams(v)
+
+	v.Set("release_id", fmt.Sprint(r.Release))
+
+	if r.TagGroupId != TagNone {
+		v.Set("tag_group_id", r.TagGroupId.String())
+	}
+	if len(r.Search) > 0 {
+		v.Set("search_text", r.Search)
+	}
+
+	return v
+}
+
+// Satisfies the `Request` interface.
+func (r ReleaseTagsRequest) Validate() error {
+	return validate_all(
+		r.DatedRequest.Validate(),
+		r.TaggedRequest.Validate(),
+		r.PagedRequest.Validate(),
+		r.OrderedRequest.Validate(),
+		r.TagGroupId.validate(),
+	)
+}
+
+type ReleaseTagsResponse struct {
+	Start  Date      `json:"realtime_start" xml:"realtime_start,attr"`
+	End    Date      `json:"realtime_end" xml:"realtime_end,attr"`
+	Order  OrderType `json:"order_by" xml:"order_by,attr"`
+	Sort   SortType  `json:"sort_order" xml:"sort_order,attr"`
+	Count  uint      `json:"count" xml:"count,attr"`
+	Offset uint      `json:"offset" xml:"offset,attr"`
+	Limit  uint      `json:"limit" xml:"limit,attr"`
+	Tags   []Tag     `json:"tags" xml:"tag"`
+}
+
+// Get the `Tag`s of the series in the given release.
+func (c Client) ReleaseTags(req ReleaseTagsRequest) (ReleaseTagsResponse, Error) {
+	return c.ReleaseTagsContext(context.Background(), req)
+}
+
+// Same as `ReleaseTags`, but the request is bound to the given context.
+func (c Client) ReleaseTagsContext(ctx context.Context, req ReleaseTagsRequest) (ReleaseTagsResponse, Error) {
+	return c.release_tags(ctx, "release tags", "release/tags", false, req)
+}
+
+// Iterate over every `Tag` of the release, fetching pages of `req.Limit` items
+// starting from `req.Offset` until every page has been read, an error occurs or
+// `ctx` is done.
+func (c Client) ReleaseTagsAll(ctx context.Context, req ReleaseTagsRequest) *TagIterator {
+	it := &TagIterator{}
+	it.pager = new_pager(ctx, req.Offset, func(ctx context.Context, offset uint) (uint, int, Error) {
+		req.Offset = offset
+		res, err := c.ReleaseTagsContext(ctx, req)
+		if err != nil {
+			return 0, 0, err
+		}
+		it.page = res.Tags
+		return res.Count, len(res.Tags), nil
+	})
+	return it
+}
+
+//==============================================================================
+//
+// GET: /fred/release/related_tags
+//
+//==============================================================================
+
+// Get the `Tag`s related to `req.Tags` for the series in the given release.
+//
+// At least one tag must be given.
+func (c Client) ReleaseRelatedTags(req ReleaseTagsRequest) (ReleaseTagsResponse, Error) {
+	return c.ReleaseRelatedTagsContext(context.Background(), req)
+}
+
+// Same as `ReleaseRelatedTags`, but the request is bound to the given context.
+func (c Client) ReleaseRelatedTagsContext(ctx context.Context, req ReleaseTagsRequest) (ReleaseTagsResponse, Error) {
+	return c.release_tags(ctx, "release related tags", "release/related_tags", true, req)
+}
+
+// Shared implementation of `ReleaseTags` and `ReleaseRelatedTags`, the latter
+// needing tags.
+func (c Client) release_tags(ctx context.Context, desc, path string, related bool, req ReleaseTagsRequest) (ReleaseTagsResponse, Error) {
+	req.baseRequest = c.base_req
+	req_url := c.base_url
+	req_url.RawQuery = req.ToParams().Encode()
+	req_url.Path = fmt.Sprintf("%s/%s", req_url.Path, path)
+	if related && len(req.Tags) == 0 {
+		return ReleaseTagsResponse{}, c.invalid_request(req_url.String(), desc, fmt.Errorf("no tags given"))
+	}
+	if err := req.Validate(); err != nil {
+		return ReleaseTagsResponse{}, c.invalid_request(req_url.String(), desc, err)
+	}
+
+	body, err := c.get(ctx, desc, req_url.String())
+	if err != nil {
+		return ReleaseTagsResponse{}, err.Prefixf("error getting %s of release %d", desc, req.Release)
+	}
+
+	var result ReleaseTagsResponse
+	err = c.unmarshal_body(req_url.String(), body, &result)
+	return result, err
+}
+
+//==============================================================================
+//
+// GET: /fred/release/tables
+//
+//==============================================================================
+
+// Holds the data needed to request the table structure of a release.
+type ReleaseTablesRequest struct {
+	baseRequest
+
+	Release         uint
+	Element         uint // 0 for the root of the release's tables
+	IncludeValues   bool // include the observation value of each series element
+	ObservationDate time.Time
+}
+
+func NewReleaseTablesRequest(release uint) ReleaseTablesRequest {
+	return ReleaseTablesRequest{
+		Release: release,
+	}
+}
+
+// Satisfies the `Request` interface.
+func (r ReleaseTablesRequest) ToParams() url.Values {
+	v := r.baseRequest.ToParams()
+
+	v.Set("release_id", fmt.Sprint(r.Release))
+	if r.Element > 0 {
+		v.Set("element_id", fmt.Sprint(r.Element))
+	}
+	if r.IncludeValues {
+		v.Set("include_observation_values", "true")
+	}
+	if r.ObservationDate.IsZero() == false {
+		v.Set("observation_date", r.ObservationDate.Format(DATE_FORMAT))
+	}
+
+	return v
+}
+
+// Satisfies the `Request` interface.
+func (r ReleaseTablesRequest) Validate() error {
+	if !r.ObservationDate.IsZero() && !r.IncludeValues {
+		return fmt.Errorf("observation date given without including observation values")
+	}
+	return nil
+}
+
+// An element (a section header or a series) within a release table.
+type ReleaseTableElement struct {
+	Id               uint                  `json:"element_id" xml:"element_id,attr"`
+	ReleaseId        uint                  `json:"release_id" xml:"release_id,attr"`
+	SeriesId         string                `json:"series_id" xml:"series_id,attr"`
+	ParentId         uint                  `json:"parent_id" xml:"parent_id,attr"`
+	Line             string                `json:"line" xml:"line,attr"`
+	Type             string                `json:"type" xml:"type,attr"`
+	Name             string                `json:"name" xml:"name,attr"`
+	Level            string                `json:"level" xml:"level,attr"`
+	ObservationValue string                `json:"observation_value" xml:"observation_value,attr"`
+	ObservationDate  string                `json:"observation_date" xml:"observation_date,attr"`
+	Children         []ReleaseTableElement `json:"children" xml:"element"`
+}
+
+// FRED is inconsistent about quoting IDs in release tables, so they are
+// decoded from either strings or numbers.
+func (e *ReleaseTableElement) UnmarshalJSON(input []byte) error {
+	type plain ReleaseTableElement
+	var raw struct {
+		plain
+		Id        json.RawMessage `json:"element_id"`
+		ReleaseId json.RawMessage `json:"release_id"`
+		ParentId  json.RawMessage `json:"parent_id"`
+	}
+	if err := json.Unmarshal(input, &raw); err != nil {
+		return err
+	}
+
+	*e = ReleaseTableElement(raw.plain)
+	for dst, src := range map[*uint]json.RawMessage{
+		&e.Id:        raw.Id,
+		&e.ReleaseId: raw.ReleaseId,
+		&e.ParentId:  raw.ParentId,
+	} {
+		n, err := parse_loose_uint(src)
+		if err != nil {
+			return err
+		}
+		*dst = n
+	}
+
+	return nil
+}
+
+// The tables of a release, rooted at the requested element.
+type ReleaseTable struct {
+	Name      string
+	ElementId uint
+	ReleaseId uint
+	Elements  []ReleaseTableElement
+}
+
+// JSON responses key the elements by their ID, these are flattened into a slice
+// ordered by ID.
+func (t *ReleaseTable) UnmarshalJSON(input []byte) error {
+	var raw struct {
+		Name      string                         `json:"name"`
+		ElementId json.RawMessage                `json:"element_id"`
+		ReleaseId json.RawMessage                `json:"release_id"`
+		Elements  map[string]ReleaseTableElement `json:"elements"`
+	}
+	if err := json.Unmarshal(input, &raw); err != nil {
+		return err
+	}
+
+	var err error
+	t.Name = raw.Name
+	if t.ElementId, err = parse_loose_uint(raw.ElementId); err != nil {
+		return err
+	}
+	if t.ReleaseId, err = parse_loose_uint(raw.ReleaseId); err != nil {
+		return err
+	}
+
+	t.Elements = make([]ReleaseTableElement, 0, len(raw.Elements))
+	for _, elem := range raw.Elements {
+		t.Elements = append(t.Elements, elem)
+	}
+	sort.Slice(t.Elements, func(i, j int) bool { return t.Elements[i].Id < t.Elements[j].Id })
+
+	return nil
+}
+
+// XML form of `ReleaseTable`, where elements are listed rather than keyed.
+type releaseTableXML struct {
+	Name      string                `xml:"name,attr"`
+	ElementId string                `xml:"element_id,attr"`
+	ReleaseId string                `xml:"release_id,attr"`
+	Elements  []ReleaseTableElement `xml:"element"`
+}
+
+// Parses a JSON number or string holding a number, treating null and empty as 0.
+func parse_loose_uint(raw json.RawMessage) (uint, error) {
+	str := string(raw)
+	if len(str) > 0 && str[0] == '"' {
+		if err := json.Unmarshal(raw, &str); err != nil {
+			return 0, err
+		}
+	}
+	if str == "null" {
+		str = ""
+	}
+	return parse_id(str)
+}
+
+// Parses an ID, treating empty as 0.
+func parse_id(str string) (uint, error) {
+	if len(str) == 0 {
+		return 0, nil
+	}
+
+	n, err := strconv.ParseUint(str, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("could not parse id '%s': %v", str, err)
+	}
+	return uint(n), nil
+}
+
+// Get the table structure of the given release, optionally with the latest
+// observation of each series.
+func (c Client) ReleaseTables(req ReleaseTablesRequest) (ReleaseTable, Error) {
+	return c.ReleaseTablesContext(context.Background(), req)
+}
+
+// Same as `ReleaseTables`, but the request is bound to the given context.
+func (c Client) ReleaseTablesContext(ctx context.Context, req ReleaseTablesRequest) (ReleaseTable, Error) {
+	req.baseRequest = c.base_req
+	req_url := c.base_url
+	req_url.RawQuery = req.ToParams().Encode()
+	req_url.Path = fmt.Sprintf("%s/release/tables", req_url.Path)
+	if err := req.Validate(); err != nil {
+		return ReleaseTable{}, c.invalid_request(req_url.String(), "release tables", err)
+	}
+
+	body, err := c.get(ctx, "release tables", req_url.String())
+	if err != nil {
+		return ReleaseTable{}, err.Prefixf("error getting tables of release %d", req.Release)
+	}
+
+	if c.base_req.fmt != XML {
+		var result ReleaseTable
+		err = c.unmarshal_body(req_url.String(), body, &result)
+		return result, err
+	}
+
+	var raw releaseTableXML
+	if err = c.unmarshal_body(req_url.String(), body, &raw); err != nil {
+		return ReleaseTable{}, err
+	}
+
+	element_id, element_err := parse_id(raw.ElementId)
+	release_id, release_err := parse_id(raw.ReleaseId)
+	if parse_err := validate_all(element_err, release_err); parse_err != nil {
+		return ReleaseTable{}, c.request_error(req_url.String(), &APIError{
+			ty:  ParseError,
+			msg: fmt.Sprintf("failed to parse xml response: %v", parse_err),
+			err: parse_err,
+		})
+	}
+
+	// ordered by ID like the JSON elements
+	sort.Slice(raw.Elements, func(i, j int) bool { return raw.Elements[i].Id < raw.Elements[j].Id })
+	return ReleaseTable{
+		Name:      raw.Name,
+		ElementId: element_id,
+		ReleaseId: release_id,
+		Elements:  raw.Elements,
+	}, nil
+}
@@ -0,0 +1,315 @@
+package gofred
+
+import (
+	"testing"
+	"time"
+)
+
+const (
+	RELEASE_GDP        = 53
+	RELEASE_EMPLOYMENT = 50
+)
+
+//==============================================================================
+//
+// GET: /fred/releases
+//
+//==============================================================================
+
+func TestReleases_Sorted(t *testing.T) {
+	limit := 4
+	req := NewReleasesRequest()
+	req.Order = OrderName
+	req.Sort = SortAscending
+	req.Limit = uint(limit)
+
+	fake_test(t, func(client Client) {
+		res, err := client.Releases(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(res.Releases) != limit {
+			t.Errorf("did not limit request to %d entries, got: %d", limit, len(res.Releases))
+		}
+
+		last := ""
+		for _, r := range res.Releases {
+			if len(last) > 0 && r.Name < last {
+				t.Errorf("expected sorted by name, got '%s' after '%s'", r.Name, last)
+			}
+			last = r.Name
+		}
+	})
+}
+
+//==============================================================================
+//
+// GET: /fred/releases/dates
+//
+//==============================================================================
+
+func TestReleasesDates_2013(t *testing.T) {
+	start_date := time.Date(2013, time.January, 1, 0, 0, 0, 0, time.UTC)
+	end_date := time.Date(2013, time.December, 31, 0, 0, 0, 0, time.UTC)
+
+	req := NewReleasesDatesRequest(start_date, end_date)
+	req.Limit = 50
+
+	fake_test(t, func(client Client) {
+		res, err := client.ReleasesDates(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(res.Dates) == 0 {
+			t.Fatalf("got no release dates in response: %+v", res)
+		}
+
+		for _, d := range res.Dates {
+			if d.ReleaseId == 0 || len(d.ReleaseName) == 0 {
+				t.Errorf("expected release id and name, got: %+v", d)
+			}
+			if time.Time(d.Date).Before(start_date) || time.Time(d.Date).After(end_date) {
+				t.Errorf("expected a date in 2013, got: %+v", d)
+			}
+		}
+	})
+}
+
+//==============================================================================
+//
+// GET: /fred/release
+//
+//==============================================================================
+
+func TestRelease_GDP(t *testing.T) {
+	fake_test(t, func(client Client) {
+		res, err := client.Release(NewReleaseRequest(RELEASE_GDP))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if res.Id != RELEASE_GDP {
+			t.Errorf("expected release %d, got: %+v", RELEASE_GDP, res)
+		}
+
+		expect_name := "Gross National Product"
+		if res.Name != expect_name {
+			t.Errorf("expected name:\n%+v\ngot:\n%+v", expect_name, res.Name)
+		}
+
+		if !res.PressRelease {
+			t.Errorf("expected GDP to be a press release")
+		}
+	})
+}
+
+func TestRelease_Nonexistant(t *testing.T) {
+	fake_test(t, func(client Client) {
+		res, err := client.Release(NewReleaseRequest(999999))
+		if err == nil {
+			t.Fatalf("expected an error response, got: %+v", res)
+		}
+		if err.Type() != Invalid {
+			t.Errorf("expected type: %v, got: %v", Invalid, err.Type())
+		}
+	})
+}
+
+//==============================================================================
+//
+// GET: /fred/release/dates
+//
+//==============================================================================
+
+func TestReleaseDates_Employment(t *testing.T) {
+	limit := 12
+	req := NewReleaseDatesRequest(RELEASE_EMPLOYMENT)
+	req.Sort = SortDescending
+	req.Limit = uint(limit)
+
+	fake_test(t, func(client Client) {
+		res, err := client.ReleaseDates(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(res.Dates) != limit {
+			t.Errorf("did not limit request to %d entries, got: %d", limit, len(res.Dates))
+		}
+
+		var last time.Time
+		for _, d := range res.Dates {
+			if d.ReleaseId != RELEASE_EMPLOYMENT {
+				t.Errorf("expected dates of release %d, got: %+v", RELEASE_EMPLOYMENT, d)
+			}
+			if !last.IsZero() && time.Time(d.Date).After(last) {
+				t.Errorf("expected sorted descending, got %v after %v", time.Time(d.Date), last)
+			}
+			last = time.Time(d.Date)
+		}
+	})
+}
+
+//==============================================================================
+//
+// GET: /fred/release/series
+//
+//==============================================================================
+
+func TestReleaseSeries_GDP(t *testing.T) {
+	limit := 1
+	req := NewReleaseSeriesRequest(RELEASE_GDP)
+	req.Order = OrderId
+	req.Sort = SortAscending
+	req.Limit = uint(limit)
+
+	fake_test(t, func(client Client) {
+		res, err := client.ReleaseSeries(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(res.Series) != limit || res.Count != 2 {
+			t.Errorf("did not limit request to %d of 2 entries, got: %d of %d", limit, len(res.Series), res.Count)
+		}
+
+		last := ""
+		for _, s := range res.Series {
+			if len(last) > 0 && s.Id < last {
+				t.Errorf("expected sorted by id, got '%s' after '%s'", s.Id, last)
+			}
+			last = s.Id
+		}
+	})
+}
+
+//==============================================================================
+//
+// GET: /fred/release/sources
+//
+//==============================================================================
+
+func TestReleaseSources_GDP(t *testing.T) {
+	fake_test(t, func(client Client) {
+		sources, err := client.ReleaseSources(NewReleaseRequest(RELEASE_GDP))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		found := false
+		for _, s := range sources {
+			if s.Id == SOURCE_BEA {
+				found = true
+				break
+			}
+		}
+		if found == false {
+			t.Errorf("expected to find source ID: %d, in sources:\n%+v", SOURCE_BEA, sources)
+		}
+	})
+}
+
+//==============================================================================
+//
+// GET: /fred/release/tags
+//
+//==============================================================================
+
+func TestReleaseTags_GDP(t *testing.T) {
+	limit := 10
+	req := NewReleaseTagsRequest(RELEASE_GDP, TagNone, "")
+	req.Order = OrderName
+	req.Sort = SortAscending
+	req.Limit = uint(limit)
+
+	fake_test(t, func(client Client) {
+		res, err := client.ReleaseTags(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(res.Tags) != limit {
+			t.Errorf("did not limit request to %d entries, got: %d", limit, len(res.Tags))
+		}
+
+		last := ""
+		for _, tag := range res.Tags {
+			if len(last) > 0 && tag.Name < last {
+				t.Errorf("expected sorted by Name, got '%s' after '%s'", tag.Name, last)
+			}
+			last = tag.Name
+		}
+	})
+}
+
+//==============================================================================
+//
+// GET: /fred/release/related_tags
+//
+//==============================================================================
+
+func TestReleaseRelatedTags_GDP(t *testing.T) {
+	limit := 5
+	req := NewReleaseRelatedTagsRequest(RELEASE_GDP, "usa", "quarterly")
+	req.Limit = uint(limit)
+
+	fake_test(t, func(client Client) {
+		res, err := client.ReleaseRelatedTags(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(res.Tags) != limit {
+			t.Errorf("did not limit request to %d entries, got: %d", limit, len(res.Tags))
+		}
+	})
+}
+
+func TestReleaseRelatedTags_NoTags(t *testing.T) {
+	fake_test(t, func(client Client) {
+		res, err := client.ReleaseRelatedTags(NewReleaseRelatedTagsRequest(RELEASE_GDP))
+		if err == nil {
+			t.Fatalf("expected an error response, got: %+v", res)
+		}
+		if err.Type() != Invalid {
+			t.Errorf("expected type: %v, got: %v", Invalid, err.Type())
+		}
+	})
+}
+
+//==============================================================================
+//
+// GET: /fred/release/tables
+//
+//==============================================================================
+
+func TestReleaseTables_GDP(t *testing.T) {
+	fake_test(t, func(client Client) {
+		res, err := client.ReleaseTables(NewReleaseTablesRequest(RELEASE_GDP))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if res.ReleaseId != RELEASE_GDP {
+			t.Errorf("expected tables of release %d, got: %d", RELEASE_GDP, res.ReleaseId)
+		}
+		if len(res.Elements) != 2 {
+			t.Fatalf("expected 2 top level elements, got: %+v", res)
+		}
+
+		req := NewReleaseTablesRequest(RELEASE_GDP)
+		req.Element = res.Elements[0].Id
+		sub, err := client.ReleaseTables(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if sub.ElementId != req.Element || len(sub.Elements) != 1 || sub.Elements[0].SeriesId != SERIES_GNP_ANNUAL {
+			t.Errorf("expected the elements under %d, got: %+v", req.Element, sub)
+		}
+		if sub.Elements[0].ParentId != req.Element || sub.Elements[0].Type != "series" {
+			t.Errorf("expected a series within element %d, got: %+v", req.Element, sub.Elements[0])
+		}
+	})
+}
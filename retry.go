@@ -0,0 +1,136 @@
+package gofred
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math"
+	"math/rand"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+//==============================================================================
+// retry policy
+//==============================================================================
+
+// Describes how failed requests are retried.
+//
+// Only failures that are safe to repeat are retried: transport errors such as
+// timeouts or refused connections, rate limited (429) responses and server
+// (5xx) errors. Every other failure is returned immediately.
+type RetryPolicy struct {
+	MaxAttempts uint          // total attempts, including the first; 0 or 1 disables retries
+	BaseDelay   time.Duration // delay before the first retry, doubled on every subsequent one
+	MaxDelay    time.Duration // upper bound on the computed backoff; 0 means unbounded
+}
+
+// No retries, the first failure is returned to the caller.
+var NoRetry = RetryPolicy{MaxAttempts: 1}
+
+// A reasonable policy for batch jobs hitting FRED's rate limits.
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts: 5,
+	BaseDelay:   500 * time.Millisecond,
+	MaxDelay:    30 * time.Second,
+}
+
+// Retry failed requests made through the client according to the given policy.
+func WithRetryPolicy(policy RetryPolicy) ClientOption {
+	return func(c *Client) error {
+		c.retry = policy
+		return nil
+	}
+}
+
+// Whether a request which failed with the given status code (0 if no response
+// was received) and error should be attempted again.
+func (p RetryPolicy) retryable(ctx context.Context, status int, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+
+	switch {
+	case status == 0:
+		return is_transport_error(err)
+	case status == http.StatusTooManyRequests:
+		return true
+	case status >= 500:
+		return true
+	}
+
+	return false
+}
+
+// Whether the error is a failure to reach the server or read its response, which
+// may not happen again, rather than a request which could never be sent such as
+// one with an invalid URL.
+func is_transport_error(err error) bool {
+	if !errors.Is(err, ErrTransport) {
+		return false
+	}
+
+	// the client wraps every failure in a `url.Error`, whatever its cause
+	var url_err *url.Error
+	if errors.As(err, &url_err) {
+		err = url_err.Err
+	}
+	var net_err net.Error
+	return errors.As(err, &net_err) || errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF)
+}
+
+// Compute how long to wait before the retry following the given (zero-indexed) attempt.
+//
+// A `Retry-After` given by the server takes precedence; otherwise the delay grows
+// exponentially from `BaseDelay` and is jittered to avoid synchronized retries.
+func (p RetryPolicy) backoff(attempt uint, retry_after time.Duration) time.Duration {
+	if retry_after > 0 {
+		return retry_after
+	}
+	if p.BaseDelay <= 0 {
+		return 0
+	}
+
+	delay := p.BaseDelay
+	for i := uint(0); i < attempt && (p.MaxDelay <= 0 || delay < p.MaxDelay); i++ {
+		if delay > math.MaxInt64/2 {
+			break
+		}
+		delay *= 2
+	}
+	if p.MaxDelay > 0 && delay > p.MaxDelay {
+		delay = p.MaxDelay
+	}
+
+	// wait somewhere in [delay/2, delay)
+	half := int64(delay / 2)
+	if half <= 0 {
+		return delay
+	}
+	return time.Duration(half + rand.Int63n(half))
+}
+
+// Parses the `Retry-After` header, which is either a number of seconds or an HTTP date.
+func parse_retry_after(header string) time.Duration {
+	if len(header) == 0 {
+		return 0
+	}
+
+	if secs, err := strconv.Atoi(header); err == nil {
+		if secs < 0 {
+			return 0
+		}
+		return time.Duration(secs) * time.Second
+	}
+
+	if at, err := http.ParseTime(header); err == nil {
+		if wait := at.Sub(time.Now()); wait > 0 {
+			return wait
+		}
+	}
+
+	return 0
+}
@@ -0,0 +1,189 @@
+package gofred
+
+import (
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func retry_client(t *testing.T, url string, policy RetryPolicy) Client {
+	client, err := NewClient(API_KEY, JSON, WithBaseURL(url), WithRetryPolicy(policy))
+	if err != nil {
+		t.Fatalf("could not create client: %v", err)
+	}
+
+	return client
+}
+
+var fast_retry = RetryPolicy{
+	MaxAttempts: 3,
+	BaseDelay:   time.Millisecond,
+	MaxDelay:    5 * time.Millisecond,
+}
+
+func TestRetry_RateLimitedThenSuccess(t *testing.T) {
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if attempts == 1 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.Write([]byte(`{"categories":[{"id":125,"name":"Trade Balance","parent_id":13}]}`))
+	}))
+	defer server.Close()
+
+	cat, err := retry_client(t, server.URL, fast_retry).Category(125)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cat.Id != 125 {
+		t.Errorf("expected category 125, got: %+v", cat)
+	}
+	if attempts != 2 {
+		t.Errorf("expected %d attempts, got %d", 2, attempts)
+	}
+}
+
+func TestRetry_ServerErrorExhausted(t *testing.T) {
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		w.WriteHeader(http.StatusServiceUnavailable)
+		w.Write([]byte(`{"error_code":503,"error_message":"unavailable"}`))
+	}))
+	defer server.Close()
+
+	_, err := retry_client(t, server.URL, fast_retry).Category(125)
+	if err == nil {
+		t.Fatalf("expected an error response")
+	}
+	if err.Type() != UnknownError {
+		t.Errorf("expected type: %v, got: %v", UnknownError, err.Type())
+	}
+	if attempts != int(fast_retry.MaxAttempts) {
+		t.Errorf("expected %d attempts, got %d", fast_retry.MaxAttempts, attempts)
+	}
+}
+
+func TestRetry_RateLimitedType(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	_, err := retry_client(t, server.URL, NoRetry).Category(125)
+	if err == nil {
+		t.Fatalf("expected an error response")
+	}
+	if err.Type() != RateLimited {
+		t.Errorf("expected type: %v, got: %v", RateLimited, err.Type())
+	}
+}
+
+func TestRetry_InvalidNotRetried(t *testing.T) {
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"error_code":400,"error_message":"Bad Request."}`))
+	}))
+	defer server.Close()
+
+	_, err := retry_client(t, server.URL, fast_retry).Category(125)
+	if err == nil {
+		t.Fatalf("expected an error response")
+	}
+	if err.Type() != Invalid {
+		t.Errorf("expected type: %v, got: %v", Invalid, err.Type())
+	}
+	if attempts != 1 {
+		t.Errorf("expected a single attempt, got %d", attempts)
+	}
+}
+
+func TestRetry_Backoff(t *testing.T) {
+	policy := RetryPolicy{MaxAttempts: 10, BaseDelay: time.Second, MaxDelay: 8 * time.Second}
+
+	for attempt := uint(0); attempt < 10; attempt++ {
+		delay := policy.backoff(attempt, 0)
+		if delay < policy.BaseDelay/2 || delay > policy.MaxDelay {
+			t.Errorf("attempt %d: backoff %v out of bounds", attempt, delay)
+		}
+	}
+
+	if delay := policy.backoff(0, time.Minute); delay != time.Minute {
+		t.Errorf("expected Retry-After to take precedence, got: %v", delay)
+	}
+}
+
+func TestRetry_ParseRetryAfter(t *testing.T) {
+	if d := parse_retry_after("3"); d != 3*time.Second {
+		t.Errorf("expected 3s, got: %v", d)
+	}
+	if d := parse_retry_after(""); d != 0 {
+		t.Errorf("expected no delay, got: %v", d)
+	}
+
+	at := time.Now().Add(time.Hour).UTC().Format(http.TimeFormat)
+	if d := parse_retry_after(at); d <= 0 || d > time.Hour {
+		t.Errorf("expected a delay of up to an hour, got: %v", d)
+	}
+}
+
+// Fails every request with the given error, counting them.
+type failingTransport struct {
+	err      error
+	attempts int
+}
+
+func (f *failingTransport) RoundTrip(*http.Request) (*http.Response, error) {
+	f.attempts++
+	return nil, f.err
+}
+
+func TestRetry_TransportErrors(t *testing.T) {
+	tests := []struct {
+		err      error
+		attempts int
+	}{
+		{&net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")}, int(fast_retry.MaxAttempts)},
+		{io.ErrUnexpectedEOF, int(fast_retry.MaxAttempts)},
+		{errors.New("unsupported request"), 1},
+	}
+
+	for _, test := range tests {
+		transport := &failingTransport{err: test.err}
+		client, err := NewClient(API_KEY, JSON, WithRetryPolicy(fast_retry),
+			WithHTTPClient(&http.Client{Transport: transport}))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := client.Category(125); !errors.Is(err, ErrTransport) {
+			t.Errorf("%v: expected a transport error, got: %v", test.err, err)
+		}
+		if transport.attempts != test.attempts {
+			t.Errorf("%v: expected %d attempts, got %d", test.err, test.attempts, transport.attempts)
+		}
+	}
+
+	// a URL which cannot be requested fails before the backoff
+	slow_retry := RetryPolicy{MaxAttempts: 2, BaseDelay: time.Minute}
+	client, err := NewClient(API_KEY, JSON, WithRetryPolicy(slow_retry), WithBaseURL("ftp://example.com/fred"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := time.Now()
+	if _, err := client.Category(125); err == nil {
+		t.Errorf("expected an unsupported scheme to fail")
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("expected no retry, took %v", elapsed)
+	}
+}
@@ -0,0 +1,176 @@
+package gofred
+
+import (
+	"encoding/csv"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"time"
+)
+
+//==============================================================================
+// revisions
+//==============================================================================
+
+// The values of every observation of a series (rows) as published at each vintage
+// (columns).
+//
+// Cells are `NaN` where the observation had not been published yet, so the
+// matrix is usually triangular.
+type RevisionTriangle struct {
+	Dates    []Date
+	Vintages []Date
+	Values   [][]float64
+}
+
+// Revision statistics of a single observation.
+type ObservationRevisions struct {
+	Date         Date
+	FirstRelease float64
+	Latest       float64
+	Delta        float64 // `Latest - FirstRelease`
+	Revisions    int     // number of times the published value changed
+}
+
+// Index of the given date in the sorted dates, or where it would be inserted.
+func date_index(dates []Date, date time.Time) int {
+	return sort.Search(len(dates), func(i int) bool {
+		return !time.Time(dates[i]).Before(date)
+	})
+}
+
+// Sorts and removes duplicates from the given dates.
+func unique_dates(dates []Date) []Date {
+	sort.Slice(dates, func(i, j int) bool {
+		return time.Time(dates[i]).Before(time.Time(dates[j]))
+	})
+
+	unique := []Date{}
+	for _, date := range dates {
+		if len(unique) == 0 || !time.Time(unique[len(unique)-1]).Equal(time.Time(date)) {
+			unique = append(unique, date)
+		}
+	}
+	return unique
+}
+
+// Build the revision triangle of vintage observations, such as those returned
+// by `SeriesVintageObservations`.
+//
+// Every date a value was first published on is a vintage. Missing values are
+// treated as unpublished.
+func NewRevisionTriangle(points []VintageDataPoint) RevisionTriangle {
+	dates, vintages := []Date{}, []Date{}
+	for _, point := range points {
+		if point.Valid {
+			dates = append(dates, point.Date)
+			vintages = append(vintages, point.RealtimeStart)
+		}
+	}
+
+	t := RevisionTriangle{Dates: unique_dates(dates), Vintages: unique_dates(vintages)}
+	t.Values = make([][]float64, len(t.Dates))
+	for i := range t.Values {
+		t.Values[i] = make([]float64, len(t.Vintages))
+		for j := range t.Values[i] {
+			t.Values[i][j] = math.NaN()
+		}
+	}
+
+	for _, point := range points {
+		if !point.Valid {
+			continue
+		}
+		row := t.Values[date_index(t.Dates, time.Time(point.Date))]
+		for col := date_index(t.Vintages, time.Time(point.RealtimeStart)); col < len(t.Vintages); col++ {
+			if time.Time(t.Vintages[col]).After(time.Time(point.RealtimeEnd)) {
+				break
+			}
+			row[col] = point.Value
+		}
+	}
+
+	return t
+}
+
+// The value of the observation as published at the given vintage, if it was.
+func (t RevisionTriangle) Value(date, vintage time.Time) (float64, bool) {
+	row, col := date_index(t.Dates, date), date_index(t.Vintages, vintage)
+	if row == len(t.Dates) || !time.Time(t.Dates[row]).Equal(date) ||
+		col == len(t.Vintages) || !time.Time(t.Vintages[col]).Equal(vintage) {
+		return 0, false
+	}
+
+	value := t.Values[row][col]
+	return value, !math.IsNaN(value)
+}
+
+// Revision statistics for every observation, in date order.
+func (t RevisionTriangle) Revisions() []ObservationRevisions {
+	stats := make([]ObservationRevisions, 0, len(t.Dates))
+	for i, row := range t.Values {
+		s := ObservationRevisions{Date: t.Dates[i]}
+		published := false
+		for _, value := range row {
+			if math.IsNaN(value) {
+				continue
+			}
+			if !published {
+				s.FirstRelease = value
+				published = true
+			} else if value != s.Latest {
+				s.Revisions++
+			}
+			s.Latest = value
+		}
+		s.Delta = s.Latest - s.FirstRelease
+		stats = append(stats, s)
+	}
+	return stats
+}
+
+// Mean absolute difference between the first release and latest value of every observation.
+func (t RevisionTriangle) MeanAbsoluteRevision() float64 {
+	stats := t.Revisions()
+	if len(stats) == 0 {
+		return 0
+	}
+
+	sum := 0.0
+	for _, s := range stats {
+		sum += math.Abs(s.Delta)
+	}
+	return sum / float64(len(stats))
+}
+
+// Write the triangle as CSV, one row per observation date and one column per
+// vintage. Unpublished cells are left empty.
+func (t RevisionTriangle) WriteCSV(w io.Writer) error {
+	out := csv.NewWriter(w)
+
+	header := make([]string, len(t.Vintages)+1)
+	header[0] = "date"
+	for i, vintage := range t.Vintages {
+		header[i+1] = time.Time(vintage).Format(DATE_FORMAT)
+	}
+	if err := out.Write(header); err != nil {
+		return err
+	}
+
+	for i, row := range t.Values {
+		record := make([]string, len(row)+1)
+		record[0] = time.Time(t.Dates[i]).Format(DATE_FORMAT)
+		for j, value := range row {
+			if !math.IsNaN(value) {
+				record[j+1] = strconv.FormatFloat(value, 'f', -1, 64)
+			}
+		}
+		if err := out.Write(record); err != nil {
+			return err
+		}
+	}
+
+	out.Flush()
+	return out.Error()
+}
@@ -0,0 +1,62 @@
+package gofred
+
+import (
+	"bytes"
+	"math"
+	"testing"
+)
+
+func TestRevisionTriangle(t *testing.T) {
+	jan, feb := Date(vintage_date(t, "2000-01-01")), Date(vintage_date(t, "2000-02-01"))
+	v1, v2, v3 := vintage_date(t, "2001-01-15"), vintage_date(t, "2001-02-15"), vintage_date(t, "2001-03-15")
+	latest := Date(vintage_date(t, REALTIME_LATEST))
+
+	triangle := NewRevisionTriangle([]VintageDataPoint{
+		{DataPoint{jan, 1.0, true}, Date(v1), Date(v2.AddDate(0, 0, -1))},
+		{DataPoint{jan, 1.2, true}, Date(v2), Date(v3.AddDate(0, 0, -1))},
+		{DataPoint{jan, 1.5, true}, Date(v3), latest},
+		{DataPoint{feb, 0, false}, Date(v1), Date(v2.AddDate(0, 0, -1))},
+		{DataPoint{feb, 2.0, true}, Date(v2), latest},
+	})
+
+	if len(triangle.Dates) != 2 || len(triangle.Vintages) != 3 {
+		t.Fatalf("expected a 2x3 triangle, got: %+v", triangle)
+	}
+	if value, ok := triangle.Value(vintage_date(t, "2000-01-01"), v2); !ok || value != 1.2 {
+		t.Errorf("expected 1.2 at the second vintage, got: %v (%v)", value, ok)
+	}
+	if _, ok := triangle.Value(vintage_date(t, "2000-02-01"), v1); ok {
+		t.Errorf("expected february to be unpublished at the first vintage")
+	}
+	if !math.IsNaN(triangle.Values[1][0]) || triangle.Values[1][2] != 2.0 {
+		t.Errorf("unexpected february row: %v", triangle.Values[1])
+	}
+
+	expect := []ObservationRevisions{
+		{Date: jan, FirstRelease: 1.0, Latest: 1.5, Delta: 0.5, Revisions: 2},
+		{Date: feb, FirstRelease: 2.0, Latest: 2.0, Delta: 0, Revisions: 0},
+	}
+	stats := triangle.Revisions()
+	if len(stats) != len(expect) {
+		t.Fatalf("expected %d observations, got: %+v", len(expect), stats)
+	}
+	for i := range expect {
+		if stats[i] != expect[i] {
+			t.Errorf("expected revisions:\n%+v\ngot:\n%+v", expect[i], stats[i])
+		}
+	}
+	if mean := triangle.MeanAbsoluteRevision(); mean != 0.25 {
+		t.Errorf("expected a mean absolute revision of 0.25, got: %v", mean)
+	}
+
+	var out bytes.Buffer
+	if err := triangle.WriteCSV(&out); err != nil {
+		t.Fatal(err)
+	}
+	expect_csv := "date,2001-01-15,2001-02-15,2001-03-15\n" +
+		"2000-01-01,1,1.2,1.5\n" +
+		"2000-02-01,,2,2\n"
+	if out.String() != expect_csv {
+		t.Errorf("expected csv:\n%s\ngot:\n%s", expect_csv, out.String())
+	}
+}
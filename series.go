@@ -1,9 +1,11 @@
 package gofred
 
 import (
+	"context"
 	"encoding/xml"
 	"fmt"
 	"net/url"
+	"strings"
 	"time"
 )
 
@@ -58,6 +60,14 @@ func (r SeriesRequest) ToParams() url.Values {
 	return v
 }
 
+// Satisfies the `Request` interface.
+func (r SeriesRequest) Validate() error {
+	if len(r.Series) == 0 {
+		return fmt.Errorf("no series id given")
+	}
+	return r.DatedRequest.Validate()
+}
+
 // Response type which _should_ contain only one category.
 type seriesResponse struct {
 	Start   Date     `json:"realtime_start" xml:"realtime_start,attr"`
@@ -72,22 +82,29 @@ type seriesResponse struct {
 // Asserts there is only one `Series` object in the result, and returns it.
 //
 func (c Client) Series(req SeriesRequest) (Series, Error) { // TODO: add a SeriesById(string) for simplicity
-	req.baseRequest = c.base_req
+	return c.SeriesContext(context.Background(), req)
+}
 
+// Same as `Series`, but the request is bound to the given context.
+func (c Client) SeriesContext(ctx context.Context, req SeriesRequest) (Series, Error) {
+	req.baseRequest = c.base_req
 	req_url := c.base_url
 	req_url.RawQuery = req.ToParams().Encode()
 	req_url.Path = fmt.Sprintf("%s/series", req_url.Path)
+	if err := req.Validate(); err != nil {
+		return Series{}, c.invalid_request(req_url.String(), "series", err)
+	}
 
-	body, err := c.get("series", req_url.String())
+	body, err := c.get(ctx, "series", req_url.String())
 	if err != nil {
-		return Series{}, err.Prefixf("error getting series %s: %v", req.Series, err)
+		return Series{}, err.Prefixf("error getting series %s:", req.Series)
 	}
 
 	// parse the correct format
 	var result seriesResponse
-	err = c.unmarshal_body(body, &result)
+	err = c.unmarshal_body(req_url.String(), body, &result)
 	if err != nil {
-		return Series{}, err.Prefixf("could not get series %s: %v", req.Series, err)
+		return Series{}, err.Prefixf("could not get series %s:", req.Series)
 	}
 
 	// pull out the singular category
@@ -98,6 +115,7 @@ func (c Client) Series(req SeriesRequest) (Series, Error) { // TODO: add a Serie
 			msg: fmt.Sprintf("received an empty series list"),
 		}
 	case 1:
+		c.InvalidateSeries(result.Series[0])
 		return result.Series[0], nil
 	default:
 		return Series{}, &APIError{
@@ -119,22 +137,29 @@ type seriesCategoriesResponse struct {
 }
 
 func (c Client) CategoriesForSeries(req SeriesRequest) ([]Category, Error) {
-	req.baseRequest = c.base_req
+	return c.CategoriesForSeriesContext(context.Background(), req)
+}
 
+// Same as `CategoriesForSeries`, but the request is bound to the given context.
+func (c Client) CategoriesForSeriesContext(ctx context.Context, req SeriesRequest) ([]Category, Error) {
+	req.baseRequest = c.base_req
 	req_url := c.base_url
 	req_url.RawQuery = req.ToParams().Encode()
 	req_url.Path = fmt.Sprintf("%s/series/categories", req_url.Path)
+	if err := req.Validate(); err != nil {
+		return nil, c.invalid_request(req_url.String(), "series categories", err)
+	}
 
-	body, err := c.get("series categories", req_url.String())
+	body, err := c.get(ctx, "series categories", req_url.String())
 	if err != nil {
-		return nil, err.Prefixf("error getting series' categories %s: %v", req.Series, err)
+		return nil, err.Prefixf("error getting series' categories %s:", req.Series)
 	}
 
 	// parse the correct format
 	var result seriesCategoriesResponse
-	err = c.unmarshal_body(body, &result)
+	err = c.unmarshal_body(req_url.String(), body, &result)
 	if err != nil {
-		return nil, err.Prefixf("could not get series' categories %s: %v", req.Series, err)
+		return nil, err.Prefixf("could not get series' categories %s:", req.Series)
 	}
 
 	return result.Categories, nil
@@ -146,6 +171,26 @@ func (c Client) CategoriesForSeries(req SeriesRequest) ([]Category, Error) {
 //
 //==============================================================================
 
+// How observations are combined when aggregating to a lower frequency.
+type AggregationMethod string
+
+const (
+	AggregateAverage     AggregationMethod = "avg"
+	AggregateSum         AggregationMethod = "sum"
+	AggregateEndOfPeriod AggregationMethod = "eop"
+)
+
+// Which observations are returned across vintages.
+type OutputType uint8
+
+const (
+	OutputDefault            OutputType = 0 // let FRED decide, same as `OutputByRealtimePeriod`
+	OutputByRealtimePeriod   OutputType = 1
+	OutputByVintageAll       OutputType = 2 // every observation, one column per vintage
+	OutputByVintageNew       OutputType = 3 // only new and revised observations per vintage
+	OutputInitialReleaseOnly OutputType = 4
+)
+
 type SeriesObservationsRequest struct {
 	baseRequest
 	DatedRequest
@@ -154,6 +199,18 @@ type SeriesObservationsRequest struct {
 	Series           string
 	ObservationStart time.Time
 	ObservationEnd   time.Time
+
+	Units UnitType // transformation FRED applies, `UnitLinear` for the raw values
+	Sort  SortType
+
+	// Aggregate to a lower frequency, both must be set together. Since `Daily`
+	// is the zero value, a frequency is only sent along with a method.
+	Frequency   Frequency
+	Aggregation AggregationMethod
+
+	Output OutputType
+	// Request the observations as of these dates rather than a realtime period.
+	VintageDates []time.Time
 }
 
 func NewSeriesObservationsRequest(series string, start, end time.Time) SeriesObservationsRequest {
@@ -178,9 +235,79 @@ func (r SeriesObservationsRequest) ToParams() url.Values {
 		v.Set("observation_end", r.ObservationEnd.Format(DATE_FORMAT))
 	}
 
+	if r.Units != UnitLinear {
+		v.Set("units", r.Units.ShortString())
+	}
+	if len(r.Sort) > 0 {
+		v.Set("sort_order", string(r.Sort))
+	}
+	if len(r.Aggregation) > 0 {
+		v.Set("frequency", r.Frequency.String())
+		v.Set("aggregation_method", string(r.Aggregation))
+	}
+	if r.Output != OutputDefault {
+		v.Set("output_type", fmt.Sprint(uint8(r.Output)))
+	}
+	if len(r.VintageDates) > 0 {
+		dates := make([]string, len(r.VintageDates))
+		for i, d := range r.VintageDates {
+			dates[i] = d.Format(DATE_FORMAT)
+		}
+		v.Set("vintage_dates", strings.Join(dates, ","))
+	}
+
 	return v
 }
 
+// Satisfies the `Request` interface.
+//
+// Observations allow pages of up to `MAX_OBSERVATIONS_LIMIT`.
+func (r SeriesObservationsRequest) Validate() error {
+	if len(r.Series) == 0 {
+		return fmt.Errorf("no series id given")
+	}
+	if !r.ObservationStart.IsZero() && !r.ObservationEnd.IsZero() && r.ObservationEnd.Before(r.ObservationStart) {
+		return fmt.Errorf("observation end %s is before observation start %s",
+			r.ObservationEnd.Format(DATE_FORMAT), r.ObservationStart.Format(DATE_FORMAT))
+	}
+
+	if r.Units > UnitNaturalLog {
+		return fmt.Errorf("unknown units %d", r.Units)
+	}
+
+	switch r.Aggregation {
+	case "":
+		if r.Frequency != Daily { // the zero value, i.e. unset
+			return fmt.Errorf("frequency %s given without an aggregation method", r.Frequency.LongString())
+		}
+	case AggregateAverage, AggregateSum, AggregateEndOfPeriod:
+		if r.Frequency >= UnknownFrequency {
+			return fmt.Errorf("cannot aggregate to an unknown frequency")
+		}
+	default:
+		return fmt.Errorf("unknown aggregation method '%s'", r.Aggregation)
+	}
+
+	if r.Output > OutputInitialReleaseOnly {
+		return fmt.Errorf("unknown output type %d", r.Output)
+	}
+
+	if len(r.VintageDates) > 0 && (!time.Time(r.Start).IsZero() || !time.Time(r.End).IsZero()) {
+		return fmt.Errorf("vintage dates and a realtime period cannot both be given")
+	}
+	for i := 1; i < len(r.VintageDates); i++ {
+		if r.VintageDates[i].Before(r.VintageDates[i-1]) {
+			return fmt.Errorf("vintage dates must be in ascending order")
+		}
+	}
+
+	return validate_all(
+		r.DatedRequest.Validate(),
+		r.PagedRequest.validate_limit(MAX_OBSERVATIONS_LIMIT),
+		OrderedRequest{Sort: r.Sort}.Validate(),
+	)
+}
+
 // Response type which _should_ contain only one category.
 type SeriesObservationsResponse struct {
 	Start            Date      `json:"realtime_start" xml:"realtime_start,attr"`
@@ -194,33 +321,116 @@ type SeriesObservationsResponse struct {
 	Limit            uint      `json:"limit" xml:"limit,attr"`
 
 	Units        UnitType    `json:"units" xml:"units,attr"`
-	Observations []DataPoint `json:"observations" xml:"observations,attr"`
+	Observations []DataPoint `json:"observations" xml:"observation"`
 }
 
 func (c Client) SeriesObservations(req SeriesObservationsRequest) (SeriesObservationsResponse, Error) {
-	req.baseRequest = c.base_req
+	return c.SeriesObservationsContext(context.Background(), req)
+}
 
+// Same as `SeriesObservations`, but the request is bound to the given context.
+func (c Client) SeriesObservationsContext(ctx context.Context, req SeriesObservationsRequest) (SeriesObservationsResponse, Error) {
+	req.baseRequest = c.base_req
 	req_url := c.base_url
 	req_url.RawQuery = req.ToParams().Encode()
 	req_url.Path = fmt.Sprintf("%s/series/observations", req_url.Path)
+	if err := req.Validate(); err != nil {
+		return SeriesObservationsResponse{}, c.invalid_request(req_url.String(), "series observations", err)
+	}
 
-	body, err := c.get("series observations", req_url.String())
+	body, err := c.get(ctx, "series observations", req_url.String())
 	if err != nil {
-		return SeriesObservationsResponse{}, err.Prefixf("error getting series %s: %v",
-			req.Series, err)
+		return SeriesObservationsResponse{}, err.Prefixf("error getting series %s:", req.Series)
 	}
 
 	// parse the correct format
 	var result SeriesObservationsResponse
-	err = c.unmarshal_body(body, &result)
+	err = c.unmarshal_body(req_url.String(), body, &result)
 	if err != nil {
-		return SeriesObservationsResponse{}, err.Prefixf("could not get series observations %s: %v",
-			req.Series, err)
+		return SeriesObservationsResponse{}, err.Prefixf("could not get series observations %s:", req.Series)
 	}
 
 	return result, err
 }
 
+// Iterate over every observation of the series, fetching pages of `req.Limit` items
+// starting from `req.Offset` until every page has been read, an error occurs or
+// `ctx` is done.
+func (c Client) SeriesObservationsAll(ctx context.Context, req SeriesObservationsRequest) *ObservationIterator {
+	it := &ObservationIterator{}
+	it.pager = new_pager(ctx, req.Offset, func(ctx context.Context, offset uint) (uint, int, Error) {
+		req.Offset = offset
+		res, err := c.SeriesObservationsContext(ctx, req)
+		if err != nil {
+			return 0, 0, err
+		}
+		it.page = res.Observations
+		return res.Count, len(res.Observations), nil
+	})
+	return it
+}
+
+//==============================================================================
+//
+// GET: /fred/series/release
+//
+//==============================================================================
+
+// Response type which _should_ contain only one release.
+type seriesReleaseResponse struct {
+	Start    Date      `json:"realtime_start" xml:"realtime_start,attr"`
+	End      Date      `json:"realtime_end" xml:"realtime_end,attr"`
+	Releases []Release `json:"releases" xml:"release"`
+}
+
+//
+// Get the `Release` the given series belongs to.
+//
+// Asserts there is only one `Release` object in the result, and returns it.
+//
+func (c Client) SeriesRelease(req SeriesRequest) (Release, Error) {
+	return c.SeriesReleaseContext(context.Background(), req)
+}
+
+// Same as `SeriesRelease`, but the request is bound to the given context.
+func (c Client) SeriesReleaseContext(ctx context.Context, req SeriesRequest) (Release, Error) {
+	req.baseRequest = c.base_req
+	req_url := c.base_url
+	req_url.RawQuery = req.ToParams().Encode()
+	req_url.Path = fmt.Sprintf("%s/series/release", req_url.Path)
+	if err := req.Validate(); err != nil {
+		return Release{}, c.invalid_request(req_url.String(), "series release", err)
+	}
+
+	body, err := c.get(ctx, "series release", req_url.String())
+	if err != nil {
+		return Release{}, err.Prefixf("error getting release of series %s:", req.Series)
+	}
+
+	// parse the correct format
+	var result seriesReleaseResponse
+	err = c.unmarshal_body(req_url.String(), body, &result)
+	if err != nil {
+		return Release{}, err.Prefixf("could not get release of series %s:", req.Series)
+	}
+
+	// pull out the singular release
+	switch len(result.Releases) {
+	case 0:
+		return Release{}, &APIError{
+			ty:  UnexpectedCount,
+			msg: fmt.Sprintf("received an empty release list"),
+		}
+	case 1:
+		return result.Releases[0], nil
+	default:
+		return Release{}, &APIError{
+			ty:  UnexpectedCount,
+			msg: fmt.Sprintf("expected only a single release, received %d", len(result.Releases)),
+		}
+	}
+}
+
 //==============================================================================
 //
 // GET: /fred/series/search
@@ -255,13 +465,32 @@ func (r SeriesSearchRequest) ToParams() url.Values {
 	r.TaggedRequest.MergeParams(v)
 
 	v.Set("search_text", r.Search)
-	if r.SearchType != SearchTypeNone {
+	if len(r.SearchType) > 0 && r.SearchType != SearchTypeNone {
 		v.Set("search_type", string(r.SearchType))
 	}
 
 	return v
 }
 
+// Satisfies the `Request` interface.
+func (r SeriesSearchRequest) Validate() error {
+	if len(r.Search) == 0 {
+		return fmt.Errorf("no search text given")
+	}
+	switch r.SearchType {
+	case "", SearchTypeNone, SearchFullText, SearchSeriesId:
+	default:
+		return fmt.Errorf("unknown search type '%s'", r.SearchType)
+	}
+	return validate_all(
+		r.DatedRequest.Validate(),
+		r.PagedRequest.Validate(),
+		r.OrderedRequest.Validate(),
+		r.FilteredRequest.Validate(),
+		r.TaggedRequest.Validate(),
+	)
+}
+
 type SeriesSearchResponse struct {
 	Start  Date      `json:"realtime_start" xml:"realtime_start,attr"`
 	End    Date      `json:"realtime_end" xml:"realtime_end,attr"`
@@ -270,24 +499,31 @@ type SeriesSearchResponse struct {
 	Count  uint      `json:"count" xml:"count,attr"`
 	Offset uint      `json:"offset" xml:"offset,attr"`
 	Limit  uint      `json:"limit" xml:"limit,attr"`
-	Series []Series  `json:"seriess" xml:"seriess"`
+	Series []Series  `json:"seriess" xml:"series"`
 }
 
 func (c Client) SeriesSearch(req SeriesSearchRequest) (SeriesSearchResponse, Error) {
-	req.baseRequest = c.base_req
+	return c.SeriesSearchContext(context.Background(), req)
+}
 
+// Same as `SeriesSearch`, but the request is bound to the given context.
+func (c Client) SeriesSearchContext(ctx context.Context, req SeriesSearchRequest) (SeriesSearchResponse, Error) {
+	req.baseRequest = c.base_req
 	req_url := c.base_url
 	req_url.RawQuery = req.ToParams().Encode()
 	req_url.Path = fmt.Sprintf("%s/series/search", req_url.Path)
+	if err := req.Validate(); err != nil {
+		return SeriesSearchResponse{}, c.invalid_request(req_url.String(), "series search", err)
+	}
 
-	body, err := c.get("series search", req_url.String())
+	body, err := c.get(ctx, "series search", req_url.String())
 	if err != nil {
 		return SeriesSearchResponse{}, err.Prefixf("error searching series '%s'", req.Search)
 	}
 
 	// parse the correct format
 	var result SeriesSearchResponse
-	err = c.unmarshal_body(body, &result)
+	err = c.unmarshal_body(req_url.String(), body, &result)
 	if err != nil {
 		return SeriesSearchResponse{}, err.Prefixf("could not search series '%s'", req.Search)
 	}
@@ -295,6 +531,23 @@ func (c Client) SeriesSearch(req SeriesSearchRequest) (SeriesSearchResponse, Err
 	return result, err
 }
 
+// Iterate over every `Series` matching the search, fetching pages of `req.Limit` items
+// starting from `req.Offset` until every page has been read, an error occurs or
+// `ctx` is done.
+func (c Client) SeriesSearchAll(ctx context.Context, req SeriesSearchRequest) *SeriesIterator {
+	it := &SeriesIterator{}
+	it.pager = new_pager(ctx, req.Offset, func(ctx context.Context, offset uint) (uint, int, Error) {
+		req.Offset = offset
+		res, err := c.SeriesSearchContext(ctx, req)
+		if err != nil {
+			return 0, 0, err
+		}
+		it.page = res.Series
+		return res.Count, len(res.Series), nil
+	})
+	return it
+}
+
 //==============================================================================
 //
 // GET: /fred/series/search/tags
@@ -340,6 +593,20 @@ func (r SeriesSearchTagsRequest) ToParams() url.Values {
 	return v
 }
 
+// Satisfies the `Request` interface.
+func (r SeriesSearchTagsRequest) Validate() error {
+	if len(r.SeriesSearch) == 0 {
+		return fmt.Errorf("no series search text given")
+	}
+	return validate_all(
+		r.DatedRequest.Validate(),
+		r.TaggedRequest.Validate(),
+		r.PagedRequest.Validate(),
+		r.OrderedRequest.Validate(),
+		r.TagGroup.validate(),
+	)
+}
+
 type SeriesSearchTagsResponse struct {
 	Start  Date      `json:"realtime_start" xml:"realtime_start,attr"`
 	End    Date      `json:"realtime_end" xml:"realtime_end,attr"`
@@ -348,24 +615,31 @@ type SeriesSearchTagsResponse struct {
 	Count  uint      `json:"count" xml:"count,attr"`
 	Offset uint      `json:"offset" xml:"offset,attr"`
 	Limit  uint      `json:"limit" xml:"limit,attr"`
-	Tags   []Tag     `json:"tags" xml:"tags,attr"`
+	Tags   []Tag     `json:"tags" xml:"tag"`
 }
 
 func (c Client) SeriesSearchTags(req SeriesSearchTagsRequest) (SeriesSearchTagsResponse, Error) {
-	req.baseRequest = c.base_req
+	return c.SeriesSearchTagsContext(context.Background(), req)
+}
 
+// Same as `SeriesSearchTags`, but the request is bound to the given context.
+func (c Client) SeriesSearchTagsContext(ctx context.Context, req SeriesSearchTagsRequest) (SeriesSearchTagsResponse, Error) {
+	req.baseRequest = c.base_req
 	req_url := c.base_url
 	req_url.RawQuery = req.ToParams().Encode()
 	req_url.Path = fmt.Sprintf("%s/series/search/tags", req_url.Path)
+	if err := req.Validate(); err != nil {
+		return SeriesSearchTagsResponse{}, c.invalid_request(req_url.String(), "series tag search", err)
+	}
 
-	body, err := c.get("series tag search", req_url.String())
+	body, err := c.get(ctx, "series tag search", req_url.String())
 	if err != nil {
 		return SeriesSearchTagsResponse{}, err.Prefixf("error searching series tags '%s'", req.SeriesSearch)
 	}
 
 	// parse the correct format
 	var result SeriesSearchTagsResponse
-	err = c.unmarshal_body(body, &result)
+	err = c.unmarshal_body(req_url.String(), body, &result)
 	if err != nil {
 		return SeriesSearchTagsResponse{}, err.Prefixf("could not search series tags '%s'", req.SeriesSearch)
 	}
@@ -373,6 +647,23 @@ func (c Client) SeriesSearchTags(req SeriesSearchTagsRequest) (SeriesSearchTagsR
 	return result, err
 }
 
+// Iterate over every `Tag` for the series matching the search, fetching pages of `req.Limit` items
+// starting from `req.Offset` until every page has been read, an error occurs or
+// `ctx` is done.
+func (c Client) SeriesSearchTagsAll(ctx context.Context, req SeriesSearchTagsRequest) *TagIterator {
+	it := &TagIterator{}
+	it.pager = new_pager(ctx, req.Offset, func(ctx context.Context, offset uint) (uint, int, Error) {
+		req.Offset = offset
+		res, err := c.SeriesSearchTagsContext(ctx, req)
+		if err != nil {
+			return 0, 0, err
+		}
+		it.page = res.Tags
+		return res.Count, len(res.Tags), nil
+	})
+	return it
+}
+
 //==============================================================================
 //
 // GET: /fred/series/search/related_tags
@@ -380,21 +671,28 @@ func (c Client) SeriesSearchTags(req SeriesSearchTagsRequest) (SeriesSearchTagsR
 //==============================================================================
 
 func (c Client) SeriesSearchRelatedTags(req SeriesSearchTagsRequest) (SeriesSearchTagsResponse, Error) {
-	req.baseRequest = c.base_req
+	return c.SeriesSearchRelatedTagsContext(context.Background(), req)
+}
 
+// Same as `SeriesSearchRelatedTags`, but the request is bound to the given context.
+func (c Client) SeriesSearchRelatedTagsContext(ctx context.Context, req SeriesSearchTagsRequest) (SeriesSearchTagsResponse, Error) {
+	req.baseRequest = c.base_req
 	req_url := c.base_url
 	req_url.RawQuery = req.ToParams().Encode()
 	req_url.Path = fmt.Sprintf("%s/series/search/related_tags", req_url.Path)
+	if err := req.Validate(); err != nil {
+		return SeriesSearchTagsResponse{}, c.invalid_request(req_url.String(), "series related tags", err)
+	}
 
 	var result SeriesSearchTagsResponse
 
-	body, err := c.get("series related tags", req_url.String())
+	body, err := c.get(ctx, "series related tags", req_url.String())
 	if err != nil {
 		return result, err.Prefixf("error searching series related tags '%s'", req.SeriesSearch)
 	}
 
 	// parse the correct format
-	err = c.unmarshal_body(body, &result)
+	err = c.unmarshal_body(req_url.String(), body, &result)
 	if err != nil {
 		return result, err.Prefixf("could not search series related tags '%s'", req.SeriesSearch)
 	}
@@ -402,6 +700,23 @@ func (c Client) SeriesSearchRelatedTags(req SeriesSearchTagsRequest) (SeriesSear
 	return result, err
 }
 
+// Iterate over every `Tag` related to the series matching the search, fetching pages of `req.Limit` items
+// starting from `req.Offset` until every page has been read, an error occurs or
+// `ctx` is done.
+func (c Client) SeriesSearchRelatedTagsAll(ctx context.Context, req SeriesSearchTagsRequest) *TagIterator {
+	it := &TagIterator{}
+	it.pager = new_pager(ctx, req.Offset, func(ctx context.Context, offset uint) (uint, int, Error) {
+		req.Offset = offset
+		res, err := c.SeriesSearchRelatedTagsContext(ctx, req)
+		if err != nil {
+			return 0, 0, err
+		}
+		it.page = res.Tags
+		return res.Count, len(res.Tags), nil
+	})
+	return it
+}
+
 //==============================================================================
 //
 // GET: /fred/series/tags
@@ -423,6 +738,17 @@ func (r SeriesTagsRequest) ToParams() url.Values {
 	return v
 }
 
+// Satisfies the `Request` interface.
+func (r SeriesTagsRequest) Validate() error {
+	if len(r.Series) == 0 {
+		return fmt.Errorf("no series id given")
+	}
+	return validate_all(
+		r.DatedRequest.Validate(),
+		r.OrderedRequest.Validate(),
+	)
+}
+
 func NewSeriesTagsRequest(series string) SeriesTagsRequest {
 	return SeriesTagsRequest{
 		Series: series,
@@ -437,25 +763,32 @@ type SeriesTagsResponse struct {
 	Count  uint      `json:"count" xml:"count,attr"`
 	Offset uint      `json:"offset" xml:"offset,attr"`
 	Limit  uint      `json:"limit" xml:"limit,attr"`
-	Tags   []Tag     `json:"tags" xml:"tags,attr"`
+	Tags   []Tag     `json:"tags" xml:"tag"`
 }
 
 func (c Client) SeriesTags(req SeriesTagsRequest) (SeriesTagsResponse, Error) {
-	req.baseRequest = c.base_req
+	return c.SeriesTagsContext(context.Background(), req)
+}
 
+// Same as `SeriesTags`, but the request is bound to the given context.
+func (c Client) SeriesTagsContext(ctx context.Context, req SeriesTagsRequest) (SeriesTagsResponse, Error) {
+	req.baseRequest = c.base_req
 	req_url := c.base_url
 	req_url.RawQuery = req.ToParams().Encode()
 	req_url.Path = fmt.Sprintf("%s/series/tags", req_url.Path)
+	if err := req.Validate(); err != nil {
+		return SeriesTagsResponse{}, c.invalid_request(req_url.String(), "series tags", err)
+	}
 
 	var result SeriesTagsResponse
 
-	body, err := c.get("series tags", req_url.String())
+	body, err := c.get(ctx, "series tags", req_url.String())
 	if err != nil {
 		return result, err.Prefixf("error searching series tags '%s'", req.Series)
 	}
 
 	// parse the correct format
-	err = c.unmarshal_body(body, &result)
+	err = c.unmarshal_body(req_url.String(), body, &result)
 	if err != nil {
 		return result, err.Prefixf("could not search series tags '%s'", req.Series)
 	}
@@ -480,10 +813,25 @@ func (r SeriesUpdatesRequest) ToParams() url.Values {
 	v := r.baseRequest.ToParams()
 	r.DatedRequest.MergeParams(v)
 	r.PagedRequest.MergeParams(v)
-	v.Set("filter_value", string(r.Filter))
+	if len(r.Filter) > 0 {
+		v.Set("filter_value", string(r.Filter))
+	}
 	return v
 }
 
+// Satisfies the `Request` interface.
+func (r SeriesUpdatesRequest) Validate() error {
+	switch r.Filter {
+	case "", FilterAll, FilterMacro, FilterRegional:
+	default:
+		return fmt.Errorf("unknown series updates filter '%s'", r.Filter)
+	}
+	return validate_all(
+		r.DatedRequest.Validate(),
+		r.PagedRequest.Validate(),
+	)
+}
+
 func NewSeriesUpdatesRequest(filter FilterType) SeriesUpdatesRequest {
 	return SeriesUpdatesRequest{
 		Filter: filter,
@@ -500,28 +848,162 @@ type SeriesUpdatesResponse struct {
 	Count          uint       `json:"count" xml:"count,attr"`
 	Offset         uint       `json:"offset" xml:"offset,attr"`
 	Limit          uint       `json:"limit" xml:"limit,attr"`
-	Series         []Series   `json:"seriess" xml:"seriess"`
+	Series         []Series   `json:"seriess" xml:"series"`
 }
 
 func (c Client) SeriesUpdates(req SeriesUpdatesRequest) (SeriesUpdatesResponse, Error) {
-	req.baseRequest = c.base_req
+	return c.SeriesUpdatesContext(context.Background(), req)
+}
 
+// Same as `SeriesUpdates`, but the request is bound to the given context.
+func (c Client) SeriesUpdatesContext(ctx context.Context, req SeriesUpdatesRequest) (SeriesUpdatesResponse, Error) {
+	req.baseRequest = c.base_req
 	req_url := c.base_url
 	req_url.RawQuery = req.ToParams().Encode()
 	req_url.Path = fmt.Sprintf("%s/series/updates", req_url.Path)
+	if err := req.Validate(); err != nil {
+		return SeriesUpdatesResponse{}, c.invalid_request(req_url.String(), "series updates", err)
+	}
 
 	var result SeriesUpdatesResponse
 
-	body, err := c.get("series updates", req_url.String())
+	body, err := c.get(ctx, "series updates", req_url.String())
 	if err != nil {
 		return result, err.Prefixf("error searching series updates")
 	}
 
 	// parse the correct format
-	err = c.unmarshal_body(body, &result)
+	err = c.unmarshal_body(req_url.String(), body, &result)
 	if err != nil {
 		return result, err.Prefixf("could not search series updates")
 	}
 
 	return result, err
 }
+
+// Iterate over every recently updated `Series`, fetching pages of `req.Limit` items
+// starting from `req.Offset` until every page has been read, an error occurs or
+// `ctx` is done.
+func (c Client) SeriesUpdatesAll(ctx context.Context, req SeriesUpdatesRequest) *SeriesIterator {
+	it := &SeriesIterator{}
+	it.pager = new_pager(ctx, req.Offset, func(ctx context.Context, offset uint) (uint, int, Error) {
+		req.Offset = offset
+		res, err := c.SeriesUpdatesContext(ctx, req)
+		if err != nil {
+			return 0, 0, err
+		}
+		it.page = res.Series
+		return res.Count, len(res.Series), nil
+	})
+	return it
+}
+
+//==============================================================================
+//
+// GET: /fred/series/vintagedates
+//
+//==============================================================================
+
+// Largest page `series/vintagedates` accepts
+const MAX_VINTAGE_DATES_LIMIT = 10000
+
+// Holds the data needed to request the dates a series was revised.
+type SeriesVintageDatesRequest struct {
+	baseRequest
+	DatedRequest
+	PagedRequest
+	OrderedRequest
+
+	Series string
+}
+
+func NewSeriesVintageDatesRequest(series string) SeriesVintageDatesRequest {
+	return SeriesVintageDatesRequest{
+		Series: series,
+	}
+}
+
+// Satisfies the `Request` interface.
+func (r SeriesVintageDatesRequest) ToParams() url.Values {
+	v := r.baseRequest.ToParams()
+	r.DatedRequest.MergeParams(v)
+	r.PagedRequest.MergeParams(v)
+	r.OrderedRequest.MergeParams(v)
+	v.Set("series_id", r.Series)
+	return v
+}
+
+// Satisfies the `Request` interface.
+//
+// Vintage dates allow pages of up to `MAX_VINTAGE_DATES_LIMIT`.
+func (r SeriesVintageDatesRequest) Validate() error {
+	if len(r.Series) == 0 {
+		return fmt.Errorf("no series id given")
+	}
+	return validate_all(
+		r.DatedRequest.Validate(),
+		r.PagedRequest.validate_limit(MAX_VINTAGE_DATES_LIMIT),
+		r.OrderedRequest.Validate(),
+	)
+}
+
+type SeriesVintageDatesResponse struct {
+	Start  Date      `json:"realtime_start" xml:"realtime_start,attr"`
+	End    Date      `json:"realtime_end" xml:"realtime_end,attr"`
+	Order  OrderType `json:"order_by" xml:"order_by,attr"`
+	Sort   SortType  `json:"sort_order" xml:"sort_order,attr"`
+	Count  uint      `json:"count" xml:"count,attr"`
+	Offset uint      `json:"offset" xml:"offset,attr"`
+	Limit  uint      `json:"limit" xml:"limit,attr"`
+	Dates  []Date    `json:"vintage_dates" xml:"vintage_date"`
+}
+
+//
+// Get every date on which the given series was revised or had new data released.
+//
+func (c Client) SeriesVintageDates(req SeriesVintageDatesRequest) (SeriesVintageDatesResponse, Error) {
+	return c.SeriesVintageDatesContext(context.Background(), req)
+}
+
+// Same as `SeriesVintageDates`, but the request is bound to the given context.
+func (c Client) SeriesVintageDatesContext(ctx context.Context, req SeriesVintageDatesRequest) (SeriesVintageDatesResponse, Error) {
+	req.baseRequest = c.base_req
+	req_url := c.base_url
+	req_url.RawQuery = req.ToParams().Encode()
+	req_url.Path = fmt.Sprintf("%s/series/vintagedates", req_url.Path)
+	if err := req.Validate(); err != nil {
+		return SeriesVintageDatesResponse{}, c.invalid_request(req_url.String(), "series vintage dates", err)
+	}
+
+	var result SeriesVintageDatesResponse
+
+	body, err := c.get(ctx, "series vintage dates", req_url.String())
+	if err != nil {
+		return result, err.Prefixf("error getting vintage dates of series '%s'", req.Series)
+	}
+
+	// parse the correct format
+	err = c.unmarshal_body(req_url.String(), body, &result)
+	if err != nil {
+		return result, err.Prefixf("could not get vintage dates of series '%s'", req.Series)
+	}
+
+	return result, err
+}
+
+// Iterate over every vintage date of the series, fetching pages of `req.Limit`
+// dates starting from `req.Offset` until every page has been read, an error
+// occurs or `ctx` is done.
+func (c Client) SeriesVintageDatesAll(ctx context.Context, req SeriesVintageDatesRequest) *DateIterator {
+	it := &DateIterator{}
+	it.pager = new_pager(ctx, req.Offset, func(ctx context.Context, offset uint) (uint, int, Error) {
+		req.Offset = offset
+		res, err := c.SeriesVintageDatesContext(ctx, req)
+		if err != nil {
+			return 0, 0, err
+		}
+		it.page = res.Dates
+		return res.Count, len(res.Dates), nil
+	})
+	return it
+}
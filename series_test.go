@@ -1,6 +1,7 @@
 package gofred
 
 import (
+	"net/http"
 	"testing"
 	"time"
 )
@@ -17,7 +18,7 @@ const (
 //==============================================================================
 
 func TestSeries_AnnualGNP(t *testing.T) {
-	mux_test(t, func(client Client) {
+	fake_test(t, func(client Client) {
 		req := SeriesRequest{
 			Series: SERIES_GNP_ANNUAL,
 		}
@@ -51,13 +52,13 @@ func TestSeries_AnnualGNP(t *testing.T) {
 }
 
 func TestSeries_Nonexistant(t *testing.T) {
-	mux_test(t, func(client Client) {
+	fake_test(t, func(client Client) {
 		series, err := client.Series(NewSeriesRequest("ABCD"))
 		if err == nil {
 			t.Fatalf("expected an error response, got: %+v", series)
 		}
 		if err.Type() != Invalid {
-			t.Errorf("expected type:", Invalid, ", got:", err.Type())
+			t.Errorf("expected type: %v, got: %v", Invalid, err.Type())
 		}
 	})
 }
@@ -73,7 +74,7 @@ func TestSeriesCategories_EchangeJpUs(t *testing.T) {
 		Series: SERIES_EXCHANGE_JP_US,
 	}
 
-	mux_test(t, func(client Client) {
+	fake_test(t, func(client Client) {
 		res, err := client.CategoriesForSeries(req)
 		if err != nil {
 			t.Fatal(err)
@@ -112,7 +113,7 @@ func TestSeriesObservations_GrossNationalProduct(t *testing.T) {
 	req := NewSeriesObservationsRequest(SERIES_GNP_ANNUAL, time.Unix(0, 0), time.Now().Add(-time.Hour*24))
 	req.Limit = uint(limit)
 
-	mux_test(t, func(client Client) {
+	fake_test(t, func(client Client) {
 		res, err := client.SeriesObservations(req)
 		if err != nil {
 			t.Fatal(err)
@@ -124,6 +125,120 @@ func TestSeriesObservations_GrossNationalProduct(t *testing.T) {
 	})
 }
 
+func TestSeriesObservations_QuarterlyAverageChange(t *testing.T) {
+	req := NewSeriesObservationsRequest(SERIES_EXCHANGE_JP_US, time.Date(2000, time.January, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(2001, time.January, 1, 0, 0, 0, 0, time.UTC))
+	req.Frequency = Quarterly
+	req.Aggregation = AggregateAverage
+	req.Units = UnitPercentChangeFromYearAgo
+
+	sent := &queryRecorder{}
+	fake_test(t, func(client Client) {
+		res, err := client.SeriesObservations(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		query := sent.last()
+		for param, expect := range map[string]string{"units": "pc1", "frequency": "q", "aggregation_method": "avg"} {
+			if query.Get(param) != expect {
+				t.Errorf("expected %s=%s to be sent, got: %s", param, expect, query.Get(param))
+			}
+		}
+		if res.Units != UnitPercentChangeFromYearAgo {
+			t.Errorf("expected units %v, got: %v", UnitPercentChangeFromYearAgo, res.Units)
+		}
+
+		// 2000Q1 through 2001Q1
+		if len(res.Observations) != 5 {
+			t.Fatalf("expected %d quarterly observations, found %d: %+v", 5, len(res.Observations), res.Observations)
+		}
+
+		// 2000Q1 against 1999Q1, from the monthly values
+		monthly, err := client.SeriesObservations(NewSeriesObservationsRequest(SERIES_EXCHANGE_JP_US,
+			time.Date(1999, time.January, 1, 0, 0, 0, 0, time.UTC), time.Date(2000, time.March, 1, 0, 0, 0, 0, time.UTC)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		average := func(points []DataPoint) float64 {
+			return (points[0].Value + points[1].Value + points[2].Value) / 3
+		}
+		expect := (average(monthly.Observations[12:15])/average(monthly.Observations[0:3]) - 1) * 100
+		if first := res.Observations[0]; !first.Valid || !close_to(first.Value, expect, 1e-9) {
+			t.Errorf("expected %v for 2000Q1, got: %+v", expect, first)
+		}
+	}, WithHTTPClient(&http.Client{Transport: sent}))
+}
+
+func TestSeriesObservationsRequest_Params(t *testing.T) {
+	vintage := time.Date(2010, time.June, 1, 0, 0, 0, 0, time.UTC)
+
+	req := NewSeriesObservationsRequest(SERIES_GNP_ANNUAL, time.Time{}, time.Time{})
+	req.Units = UnitPercentChangeFromYearAgo
+	req.Frequency = Quarterly
+	req.Aggregation = AggregateEndOfPeriod
+	req.Output = OutputByVintageNew
+	req.Sort = SortDescending
+	req.VintageDates = []time.Time{vintage, vintage.AddDate(1, 0, 0)}
+
+	if err := req.Validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	v := req.ToParams()
+	expect := map[string]string{
+		"units":              "pc1",
+		"frequency":          "q",
+		"aggregation_method": "eop",
+		"output_type":        "3",
+		"sort_order":         "desc",
+		"vintage_dates":      "2010-06-01,2011-06-01",
+	}
+	for param, value := range expect {
+		if v.Get(param) != value {
+			t.Errorf("expected %s=%s, got: %s", param, value, v.Get(param))
+		}
+	}
+
+	plain := NewSeriesObservationsRequest(SERIES_GNP_ANNUAL, time.Time{}, time.Time{}).ToParams()
+	for param := range expect {
+		if _, exists := plain[param]; exists {
+			t.Errorf("expected %s to be omitted by default, got: %s", param, plain.Get(param))
+		}
+	}
+
+	invalid := []SeriesObservationsRequest{req, req, req, req}
+	invalid[0].Aggregation = ""
+	invalid[1].Aggregation = "median"
+	invalid[2].Output = 5
+	invalid[3].Start = Date(vintage)
+	for _, r := range invalid {
+		if err := r.Validate(); err == nil {
+			t.Errorf("expected request to be invalid: %+v", r)
+		}
+	}
+}
+
+//==============================================================================
+//
+// GET: /fred/series/release
+//
+//==============================================================================
+
+func TestSeriesRelease_AnnualGNP(t *testing.T) {
+	fake_test(t, func(client Client) {
+		res, err := client.SeriesRelease(NewSeriesRequest(SERIES_GNP_ANNUAL))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		expect_name := "Gross National Product"
+		if res.Name != expect_name {
+			t.Errorf("expected release:\n%+v\ngot:\n%+v", expect_name, res.Name)
+		}
+	})
+}
+
 //==============================================================================
 //
 // GET: /fred/series/search
@@ -136,7 +251,7 @@ func TestSeriesSearch_Monetary(t *testing.T) {
 	req.Limit = uint(limit)
 	req.Order = OrderLastUpdated
 
-	mux_test(t, func(client Client) {
+	fake_test(t, func(client Client) {
 		res, err := client.SeriesSearch(req)
 		if err != nil {
 			t.Fatal(err)
@@ -169,7 +284,7 @@ func TestSeriesSearchTags_Monetary_Tag30yr(t *testing.T) {
 	req.Order = OrderPopularity
 	req.Sort = SortAscending
 
-	mux_test(t, func(client Client) {
+	fake_test(t, func(client Client) {
 		res, err := client.SeriesSearchTags(req)
 		if err != nil {
 			t.Fatal(err)
@@ -202,7 +317,7 @@ func TestSeriesSearchRelatedTags_Monetary_Tagfrb(t *testing.T) {
 	req.Order = OrderPopularity
 	req.Sort = SortAscending
 
-	mux_test(t, func(client Client) {
+	fake_test(t, func(client Client) {
 		res, err := client.SeriesSearchRelatedTags(req)
 		if err != nil {
 			t.Fatal(err)
@@ -233,7 +348,7 @@ func TestSeriesTags_JPUS_Exchange(t *testing.T) {
 	req.Order = OrderPopularity
 	req.Sort = SortAscending
 
-	mux_test(t, func(client Client) {
+	fake_test(t, func(client Client) {
 		res, err := client.SeriesTags(req)
 		if err != nil {
 			t.Fatal(err)
@@ -258,7 +373,7 @@ func TestSeriesTags_JPUS_Exchange(t *testing.T) {
 func TestSeriesUpdates_Macro(t *testing.T) {
 	req := NewSeriesUpdatesRequest(FilterMacro)
 
-	mux_test(t, func(client Client) {
+	fake_test(t, func(client Client) {
 		_, err := client.SeriesUpdates(req)
 		if err != nil {
 			t.Fatal(err)
@@ -267,3 +382,42 @@ func TestSeriesUpdates_Macro(t *testing.T) {
 		// TODO: can we deterministically test this endpoint?
 	})
 }
+
+//==============================================================================
+//
+// GET: /fred/series/vintagedates
+//
+//==============================================================================
+
+func TestSeriesVintageDates_AnnualGNP(t *testing.T) {
+	limit := 20
+	req := NewSeriesVintageDatesRequest(SERIES_GNP_ANNUAL)
+	req.Sort = SortAscending
+	req.Limit = uint(limit)
+
+	fake_test(t, func(client Client) {
+		res, err := client.SeriesVintageDates(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(res.Dates) != limit {
+			t.Fatalf("did not limit request to %d entries, got: %d", limit, len(res.Dates))
+		}
+
+		expect_first, form_err := time.Parse(DATE_FORMAT, "1958-12-21")
+		if form_err != nil {
+			t.Fatalf("could not create expected first vintage: %v", form_err)
+		}
+		if time.Time(res.Dates[0]) != expect_first {
+			t.Errorf("incorrect first vintage: expected %v, got %v", expect_first, time.Time(res.Dates[0]))
+		}
+
+		for i := 1; i < len(res.Dates); i++ {
+			if !time.Time(res.Dates[i]).After(time.Time(res.Dates[i-1])) {
+				t.Errorf("should be sorted by date, got: %v after: %v",
+					time.Time(res.Dates[i]), time.Time(res.Dates[i-1]))
+			}
+		}
+	})
+}
@@ -0,0 +1,274 @@
+package gofred
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+)
+
+// A source of economic data, such as the Bureau of Labor Statistics.
+type Source struct {
+	Id    uint   `json:"id" xml:"id,attr"`
+	Start Date   `json:"realtime_start" xml:"realtime_start,attr"`
+	End   Date   `json:"realtime_end" xml:"realtime_end,attr"`
+	Name  string `json:"name" xml:"name,attr"`
+	Link  string `json:"link" xml:"link,attr"`
+	Notes string `json:"notes" xml:"notes,attr"`
+}
+
+//==============================================================================
+//
+// GET: /fred/sources
+//
+//==============================================================================
+
+// Holds the data needed to request every `Source`.
+type SourcesRequest struct {
+	baseRequest
+	DatedRequest
+	PagedRequest
+	OrderedRequest
+}
+
+func NewSourcesRequest() SourcesRequest {
+	return SourcesRequest{}
+}
+
+// Satisfies the `Request` interface.
+func (r SourcesRequest) ToParams() url.Values {
+	v := r.baseRequest.ToParams()
+	r.DatedRequest.MergeParams(v)
+	r.PagedRequest.MergeParams(v)
+	r.OrderedRequest.MergeParams(v)
+	return v
+}
+
+// Satisfies the `Request` interface.
+func (r SourcesRequest) Validate() error {
+	return validate_all(
+		r.DatedRequest.Validate(),
+		r.PagedRequest.Validate(),
+		r.OrderedRequest.Validate(),
+	)
+}
+
+type SourcesResponse struct {
+	Start   Date      `json:"realtime_start" xml:"realtime_start,attr"`
+	End     Date      `json:"realtime_end" xml:"realtime_end,attr"`
+	Order   OrderType `json:"order_by" xml:"order_by,attr"`
+	Sort    SortType  `json:"sort_order" xml:"sort_order,attr"`
+	Count   uint      `json:"count" xml:"count,attr"`
+	Offset  uint      `json:"offset" xml:"offset,attr"`
+	Limit   uint      `json:"limit" xml:"limit,attr"`
+	Sources []Source  `json:"sources" xml:"source"`
+}
+
+// Get every `Source` of economic data.
+func (c Client) Sources(req SourcesRequest) (SourcesResponse, Error) {
+	return c.SourcesContext(context.Background(), req)
+}
+
+// Same as `Sources`, but the request is bound to the given context.
+func (c Client) SourcesContext(ctx context.Context, req SourcesRequest) (SourcesResponse, Error) {
+	req.baseRequest = c.base_req
+	req_url := c.base_url
+	req_url.RawQuery = req.ToParams().Encode()
+	req_url.Path = fmt.Sprintf("%s/sources", req_url.Path)
+	if err := req.Validate(); err != nil {
+		return SourcesResponse{}, c.invalid_request(req_url.String(), "sources", err)
+	}
+
+	body, err := c.get(ctx, "sources", req_url.String())
+	if err != nil {
+		return SourcesResponse{}, err.Prefixf("error getting sources")
+	}
+
+	var result SourcesResponse
+	err = c.unmarshal_body(req_url.String(), body, &result)
+	return result, err
+}
+
+// Iterate over every `Source`, fetching pages of `req.Limit` items starting
+// from `req.Offset` until every page has been read, an error occurs or `ctx`
+// is done.
+func (c Client) SourcesAll(ctx context.Context, req SourcesRequest) *SourceIterator {
+	it := &SourceIterator{}
+	it.pager = new_pager(ctx, req.Offset, func(ctx context.Context, offset uint) (uint, int, Error) {
+		req.Offset = offset
+		res, err := c.SourcesContext(ctx, req)
+		if err != nil {
+			return 0, 0, err
+		}
+		it.page = res.Sources
+		return res.Count, len(res.Sources), nil
+	})
+	return it
+}
+
+//==============================================================================
+//
+// GET: /fred/source
+//
+//==============================================================================
+
+// Holds the data needed to request information on a `Source`.
+type SourceRequest struct {
+	baseRequest
+	DatedRequest
+	Source uint
+}
+
+func NewSourceRequest(source uint) SourceRequest {
+	return SourceRequest{
+		Source: source,
+	}
+}
+
+// Satisfies the `Request` interface.
+func (r SourceRequest) ToParams() url.Values {
+	v := r.baseRequest.ToParams()
+	r.DatedRequest.MergeParams(v)
+	v.Set("source_id", fmt.Sprint(r.Source))
+	return v
+}
+
+// Satisfies the `Request` interface.
+func (r SourceRequest) Validate() error {
+	return r.DatedRequest.Validate()
+}
+
+// Response type which _should_ contain only one source.
+type sourceResponse struct {
+	Start   Date     `json:"realtime_start" xml:"realtime_start,attr"`
+	End     Date     `json:"realtime_end" xml:"realtime_end,attr"`
+	Sources []Source `json:"sources" xml:"source"`
+}
+
+// Get the `Source` information for the given source ID.
+//
+// Asserts there is only one `Source` object in the result, and returns it.
+func (c Client) Source(req SourceRequest) (Source, Error) {
+	return c.SourceContext(context.Background(), req)
+}
+
+// Same as `Source`, but the request is bound to the given context.
+func (c Client) SourceContext(ctx context.Context, req SourceRequest) (Source, Error) {
+	req.baseRequest = c.base_req
+	req_url := c.base_url
+	req_url.RawQuery = req.ToParams().Encode()
+	req_url.Path = fmt.Sprintf("%s/source", req_url.Path)
+	if err := req.Validate(); err != nil {
+		return Source{}, c.invalid_request(req_url.String(), "source", err)
+	}
+
+	body, err := c.get(ctx, "source", req_url.String())
+	if err != nil {
+		return Source{}, err.Prefixf("error getting source %d:", req.Source)
+	}
+
+	// parse the correct format
+	var result sourceResponse
+	err = c.unmarshal_body(req_url.String(), body, &result)
+	if err != nil {
+		return Source{}, err.Prefixf("could not get source %d:", req.Source)
+	}
+
+	// pull out the singular source
+	switch len(result.Sources) {
+	case 0:
+		return Source{}, &APIError{
+			ty:  UnexpectedCount,
+			msg: fmt.Sprintf("received an empty source list"),
+		}
+	case 1:
+		return result.Sources[0], nil
+	default:
+		return Source{}, &APIError{
+			ty:  UnexpectedCount,
+			msg: fmt.Sprintf("expected only a single source, received %d", len(result.Sources)),
+		}
+	}
+}
+
+//==============================================================================
+//
+// GET: /fred/source/releases
+//
+//==============================================================================
+
+// Holds the data needed to request the `Release`s published by a source.
+type SourceReleasesRequest struct {
+	baseRequest
+	DatedRequest
+	PagedRequest
+	OrderedRequest
+
+	Source uint
+}
+
+func NewSourceReleasesRequest(source uint) SourceReleasesRequest {
+	return SourceReleasesRequest{
+		Source: source,
+	}
+}
+
+// Satisfies the `Request` interface.
+func (r SourceReleasesRequest) ToParams() url.Values {
+	v := r.baseRequest.ToParams()
+	r.DatedRequest.MergeParams(v)
+	r.PagedRequest.MergeParams(v)
+	r.OrderedRequest.MergeParams(v)
+	v.Set("source_id", fmt.Sprint(r.Source))
+	return v
+}
+
+// Satisfies the `Request` interface.
+func (r SourceReleasesRequest) Validate() error {
+	return validate_all(
+		r.DatedRequest.Validate(),
+		r.PagedRequest.Validate(),
+		r.OrderedRequest.Validate(),
+	)
+}
+
+// Get the `Release`s published by the given source.
+func (c Client) SourceReleases(req SourceReleasesRequest) (ReleasesResponse, Error) {
+	return c.SourceReleasesContext(context.Background(), req)
+}
+
+// Same as `SourceReleases`, but the request is bound to the given context.
+func (c Client) SourceReleasesContext(ctx context.Context, req SourceReleasesRequest) (ReleasesResponse, Error) {
+	req.baseRequest = c.base_req
+	req_url := c.base_url
+	req_url.RawQuery = req.ToParams().Encode()
+	req_url.Path = fmt.Sprintf("%s/source/releases", req_url.Path)
+	if err := req.Validate(); err != nil {
+		return ReleasesResponse{}, c.invalid_request(req_url.String(), "source releases", err)
+	}
+
+	body, err := c.get(ctx, "source releases", req_url.String())
+	if err != nil {
+		return ReleasesResponse{}, err.Prefixf("error getting releases of source %d", req.Source)
+	}
+
+	var result ReleasesResponse
+	err = c.unmarshal_body(req_url.String(), body, &result)
+	return result, err
+}
+
+// Iterate over every `Release` published by the source, fetching pages of
+// `req.Limit` items starting from `req.Offset` until every page has been read,
+// an error occurs or `ctx` is done.
+func (c Client) SourceReleasesAll(ctx context.Context, req SourceReleasesRequest) *ReleaseIterator {
+	it := &ReleaseIterator{}
+	it.pager = new_pager(ctx, req.Offset, func(ctx context.Context, offset uint) (uint, int, Error) {
+		req.Offset = offset
+		res, err := c.SourceReleasesContext(ctx, req)
+		if err != nil {
+			return 0, 0, err
+		}
+		it.page = res.Releases
+		return res.Count, len(res.Releases), nil
+	})
+	return it
+}
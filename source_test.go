@@ -0,0 +1,100 @@
+package gofred
+
+import (
+	"testing"
+)
+
+const (
+	SOURCE_BEA = 18
+)
+
+//==============================================================================
+//
+// GET: /fred/sources
+//
+//==============================================================================
+
+func TestSources_Sorted(t *testing.T) {
+	limit := 3
+	req := NewSourcesRequest()
+	req.Order = OrderSourceId
+	req.Sort = SortAscending
+	req.Limit = uint(limit)
+
+	fake_test(t, func(client Client) {
+		res, err := client.Sources(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(res.Sources) != limit {
+			t.Errorf("did not limit request to %d entries, got: %d", limit, len(res.Sources))
+		}
+
+		last := uint(0)
+		for _, s := range res.Sources {
+			if s.Id < last {
+				t.Errorf("expected sorted by id, got %d after %d", s.Id, last)
+			}
+			last = s.Id
+		}
+	})
+}
+
+//==============================================================================
+//
+// GET: /fred/source
+//
+//==============================================================================
+
+func TestSource_BEA(t *testing.T) {
+	fake_test(t, func(client Client) {
+		res, err := client.Source(NewSourceRequest(SOURCE_BEA))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		expect_name := "U.S. Bureau of Economic Analysis"
+		if res.Id != SOURCE_BEA || res.Name != expect_name {
+			t.Errorf("expected source %d '%s', got: %+v", SOURCE_BEA, expect_name, res)
+		}
+	})
+}
+
+func TestSource_Nonexistant(t *testing.T) {
+	fake_test(t, func(client Client) {
+		res, err := client.Source(NewSourceRequest(999999))
+		if err == nil {
+			t.Fatalf("expected an error response, got: %+v", res)
+		}
+		if err.Type() != Invalid {
+			t.Errorf("expected type: %v, got: %v", Invalid, err.Type())
+		}
+	})
+}
+
+//==============================================================================
+//
+// GET: /fred/source/releases
+//
+//==============================================================================
+
+func TestSourceReleases_BEA(t *testing.T) {
+	fake_test(t, func(client Client) {
+		res, err := client.SourceReleases(NewSourceReleasesRequest(SOURCE_BEA))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		found := false
+		for _, r := range res.Releases {
+			if r.Id == RELEASE_GDP {
+				found = true
+				break
+			}
+		}
+		if found == false {
+			t.Errorf("expected to find release ID: %d, in releases:\n%+v", RELEASE_GDP, res.Releases)
+		}
+	})
+}
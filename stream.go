@@ -0,0 +1,254 @@
+package gofred
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/url"
+)
+
+//==============================================================================
+// streaming
+//==============================================================================
+
+// Decodes an observations response one `<observation>` or `observations` entry
+// at a time, handing each row to `row` until it returns false.
+//
+// Only the current row is held in memory. The other fields of the response are
+// decoded into `header` once the rows have been read (or `row` stopped them).
+func (c Client) decode_rows(r io.Reader, header interface{}, row func(vintageRow) (bool, error)) error {
+	switch c.base_req.fmt {
+	case JSON:
+		return decode_json_rows(r, header, row)
+	case XML:
+		return decode_xml_rows(r, header, row)
+	}
+	return fmt.Errorf("unknown request/response type: %v", c.base_req.fmt)
+}
+
+func expect_json_delim(dec *json.Decoder, delim json.Delim) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if tok != delim {
+		return fmt.Errorf("expected '%v', got: %v", delim, tok)
+	}
+	return nil
+}
+
+func decode_json_rows(r io.Reader, header interface{}, row func(vintageRow) (bool, error)) error {
+	dec := json.NewDecoder(r)
+	if err := expect_json_delim(dec, '{'); err != nil {
+		return err
+	}
+
+	// fields other than the rows are small, keep them to decode the header from
+	fields := map[string]json.RawMessage{}
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		key, _ := tok.(string)
+
+		if key != "observations" {
+			var raw json.RawMessage
+			if err := dec.Decode(&raw); err != nil {
+				return err
+			}
+			fields[key] = raw
+			continue
+		}
+
+		if err := expect_json_delim(dec, '['); err != nil {
+			return err
+		}
+		for dec.More() {
+			var next vintageRow
+			if err := dec.Decode(&next); err != nil {
+				return err
+			}
+			if more, err := row(next); err != nil || !more {
+				return decode_json_header(fields, header, err)
+			}
+		}
+		if err := expect_json_delim(dec, ']'); err != nil {
+			return err
+		}
+	}
+
+	return decode_json_header(fields, header, nil)
+}
+
+func decode_json_header(fields map[string]json.RawMessage, header interface{}, err error) error {
+	if err != nil {
+		return err
+	}
+
+	raw, err := json.Marshal(fields)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(raw, header)
+}
+
+func decode_xml_rows(r io.Reader, header interface{}, row func(vintageRow) (bool, error)) error {
+	dec := xml.NewDecoder(r)
+	root := true
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		start, ok := tok.(xml.StartElement)
+		if !ok {
+			continue
+		}
+
+		// the header is the attributes of the root element, decode it on its own
+		if root {
+			root = false
+			var buf bytes.Buffer
+			enc := xml.NewEncoder(&buf)
+			if err := enc.EncodeToken(start); err != nil {
+				return err
+			}
+			if err := enc.EncodeToken(start.End()); err != nil {
+				return err
+			}
+			if err := enc.Flush(); err != nil {
+				return err
+			}
+			if err := xml.Unmarshal(buf.Bytes(), header); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if start.Name.Local != "observation" {
+			if err := dec.Skip(); err != nil {
+				return err
+			}
+			continue
+		}
+
+		var next vintageRow
+		if err := dec.DecodeElement(&next, &start); err != nil {
+			return err
+		}
+		if more, err := row(next); err != nil || !more {
+			return err
+		}
+	}
+}
+
+// The URL of the observations request.
+func (c Client) observations_url(req SeriesObservationsRequest) url.URL {
+	req_url := c.base_url
+	req_url.RawQuery = req.ToParams().Encode()
+	req_url.Path = fmt.Sprintf("%s/series/observations", req_url.Path)
+	return req_url
+}
+
+// Streams the observations of the request, passing each row to `row`.
+func (c Client) stream_observations(ctx context.Context, desc string, req SeriesObservationsRequest, header interface{}, row func(vintageRow) (bool, error)) Error {
+	req_url := c.observations_url(req)
+	return c.stream(ctx, desc, req_url.String(), func(body io.Reader) Error {
+		if err := c.decode_rows(body, header, row); err != nil {
+			return c.request_error(req_url.String(), &APIError{
+				ty:  ParseError,
+				msg: fmt.Sprintf("failed to parse %s %s response: %v", c.base_req.fmt, desc, err),
+				err: err,
+			})
+		}
+		return nil
+	})
+}
+
+//==============================================================================
+//
+// GET: /fred/series/observations (streamed)
+//
+//==============================================================================
+
+// Same as `SeriesObservationsContext`, but observations are decoded as they are
+// received and passed to `fn` one at a time, rather than being collected in the
+// response. Memory use is bounded whatever the number of observations.
+//
+// Returning false from `fn` stops the stream. The returned response holds every
+// field but `Observations`.
+//
+// Use `SeriesVintageObservationsStream` for output types other than `OutputByRealtimePeriod`.
+func (c Client) SeriesObservationsStream(ctx context.Context, req SeriesObservationsRequest, fn func(DataPoint) bool) (SeriesObservationsResponse, Error) {
+	req.baseRequest = c.base_req
+	req_url := c.observations_url(req)
+	if err := req.Validate(); err != nil {
+		return SeriesObservationsResponse{}, c.invalid_request(req_url.String(), "series observations", err)
+	}
+	switch req.Output {
+	case OutputDefault, OutputByRealtimePeriod:
+	default:
+		return SeriesObservationsResponse{}, c.invalid_request(req_url.String(), "series observations",
+			fmt.Errorf("output type %d has a column per vintage, stream it as vintages", req.Output))
+	}
+
+	var result SeriesObservationsResponse
+	err := c.stream_observations(ctx, "series observations", req, &result, func(row vintageRow) (bool, error) {
+		if len(row.cells) > 0 {
+			return false, fmt.Errorf("observation on %v has a column per vintage", row.date)
+		}
+		point, err := parse_data_point(row.date, row.value)
+		if err != nil {
+			return false, err
+		}
+		return fn(point), nil
+	})
+	if err != nil {
+		return SeriesObservationsResponse{}, err.Prefixf("could not stream series observations %s:", req.Series)
+	}
+
+	return result, nil
+}
+
+// Same as `SeriesVintageObservationsContext`, but observations are decoded as
+// they are received and passed to `fn` one at a time, rather than being collected
+// in the response. Memory use is bounded whatever the number of observations.
+//
+// Points are passed in the order FRED returns observations, each observation's
+// vintages ordered by realtime start. Returning false from `fn` stops the stream.
+// The returned response holds every field but `Observations`.
+func (c Client) SeriesVintageObservationsStream(ctx context.Context, req SeriesObservationsRequest, fn func(VintageDataPoint) bool) (SeriesVintageObservationsResponse, Error) {
+	req = req.with_vintage_defaults()
+	req.baseRequest = c.base_req
+	req_url := c.observations_url(req)
+	if err := req.Validate(); err != nil {
+		return SeriesVintageObservationsResponse{}, c.invalid_request(req_url.String(), "series vintage observations", err)
+	}
+
+	var result SeriesVintageObservationsResponse
+	err := c.stream_observations(ctx, "series vintage observations", req, &result, func(row vintageRow) (bool, error) {
+		points, err := row.points(req.Output)
+		if err != nil {
+			return false, err
+		}
+		for _, point := range points {
+			if !fn(point) {
+				return false, nil
+			}
+		}
+		return true, nil
+	})
+	if err != nil {
+		return SeriesVintageObservationsResponse{}, err.Prefixf("could not stream vintages of series %s:", req.Series)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,143 @@
+package gofred
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// Serves `total` daily observations starting 2000-01-01, every tenth missing.
+func new_stream_test_server(total int) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Date(2000, time.January, 1, 0, 0, 0, 0, time.UTC)
+		value := func(i int) string {
+			if i%10 == 9 {
+				return "."
+			}
+			return fmt.Sprint(i)
+		}
+
+		if r.URL.Query().Get("file_type") == "xml" {
+			fmt.Fprintf(w, `<observations count="%d" units="lin" realtime_start="2020-01-01">`, total)
+			for i := 0; i < total; i++ {
+				fmt.Fprintf(w, `<observation realtime_start="2020-01-01" realtime_end="2020-01-01" date="%s" value="%s"/>`,
+					start.AddDate(0, 0, i).Format(DATE_FORMAT), value(i))
+			}
+			fmt.Fprint(w, `</observations>`)
+			return
+		}
+
+		fmt.Fprintf(w, `{"realtime_start":"2020-01-01","count":%d,"units":"lin","observations":[`, total)
+		for i := 0; i < total; i++ {
+			if i > 0 {
+				fmt.Fprint(w, ",")
+			}
+			fmt.Fprintf(w, `{"realtime_start":"2020-01-01","realtime_end":"2020-01-01","date":"%s","value":"%s"}`,
+				start.AddDate(0, 0, i).Format(DATE_FORMAT), value(i))
+		}
+		fmt.Fprint(w, `],"limit":100000}`)
+	}))
+}
+
+func TestSeriesObservationsStream(t *testing.T) {
+	total := 5000
+	server := new_stream_test_server(total)
+	defer server.Close()
+
+	for _, format := range []ResponseFormat{JSON, XML} {
+		client, err := NewClient(API_KEY, format, WithBaseURL(server.URL))
+		if err != nil {
+			t.Fatalf("could not create client: %v", err)
+		}
+
+		seen, valid := 0, 0
+		var last DataPoint
+		res, api_err := client.SeriesObservationsStream(context.Background(),
+			NewSeriesObservationsRequest(SERIES_GNP_ANNUAL, time.Time{}, time.Time{}), func(point DataPoint) bool {
+				seen++
+				if point.Valid {
+					valid++
+				}
+				last = point
+				return true
+			})
+		if api_err != nil {
+			t.Fatal(api_err)
+		}
+
+		if seen != total || valid != total-total/10 {
+			t.Errorf("%v: expected %d points, %d valid, got %d, %d valid", format, total, total-total/10, seen, valid)
+		}
+		if last.Valid || time.Time(last.Date).Format(DATE_FORMAT) != "2013-09-08" {
+			t.Errorf("%v: unexpected last point: %+v", format, last)
+		}
+		if res.Count != uint(total) || res.Units != UnitLinear || res.Observations != nil {
+			t.Errorf("%v: unexpected response header: %+v", format, res)
+		}
+	}
+}
+
+func TestSeriesObservationsStream_Stop(t *testing.T) {
+	server := new_stream_test_server(100)
+	defer server.Close()
+
+	client, err := NewClient(API_KEY, JSON, WithBaseURL(server.URL))
+	if err != nil {
+		t.Fatalf("could not create client: %v", err)
+	}
+
+	seen := 0
+	_, api_err := client.SeriesObservationsStream(context.Background(),
+		NewSeriesObservationsRequest(SERIES_GNP_ANNUAL, time.Time{}, time.Time{}), func(point DataPoint) bool {
+			seen++
+			return seen < 3
+		})
+	if api_err != nil {
+		t.Fatal(api_err)
+	}
+	if seen != 3 {
+		t.Errorf("expected the stream to stop after %d points, saw %d", 3, seen)
+	}
+}
+
+func TestSeriesVintageObservationsStream(t *testing.T) {
+	js := `{"output_type":2,"observations":[
+		{"date":"2000-01-01","GNPCA_20010115":"1.0","GNPCA_20010215":"1.0","GNPCA_20010315":"1.5"},
+		{"date":"2000-02-01","GNPCA_20010115":".","GNPCA_20010215":"2.0","GNPCA_20010315":"2.0"}]}`
+	server := new_vintage_test_server(t, "2", js, "")
+	defer server.Close()
+
+	client, err := NewClient(API_KEY, JSON, WithBaseURL(server.URL))
+	if err != nil {
+		t.Fatalf("could not create client: %v", err)
+	}
+
+	req := NewSeriesObservationsRequest(SERIES_GNP_ANNUAL, time.Time{}, time.Time{})
+	streamed := []VintageDataPoint{}
+	res, api_err := client.SeriesVintageObservationsStream(context.Background(), req, func(point VintageDataPoint) bool {
+		streamed = append(streamed, point)
+		return true
+	})
+	if api_err != nil {
+		t.Fatal(api_err)
+	}
+	if res.Output != OutputByVintageAll {
+		t.Errorf("expected output type %d, got: %d", OutputByVintageAll, res.Output)
+	}
+
+	buffered, api_err := client.SeriesVintageObservations(req)
+	if api_err != nil {
+		t.Fatal(api_err)
+	}
+	check_vintage_points(t, buffered.Observations, streamed)
+
+	// columns cannot be streamed as plain observations
+	req.Output = OutputByVintageAll
+	_, api_err = client.SeriesObservationsStream(context.Background(), req, func(DataPoint) bool { return true })
+	if api_err == nil {
+		t.Errorf("expected vintage columns to be refused")
+	}
+}
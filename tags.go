@@ -1,10 +1,258 @@
 package gofred
 
+import (
+	"context"
+	"fmt"
+	"net/url"
+)
+
 type Tag struct {
-	Name        string   `json:"name" xml:"name"`
-	GroupId     TagId    `json:"group_id" xml:"group_id"`
-	Notes       string   `json:"notes" xml:"notes"`
-	Created     DateTime `json:"created" xml:"created"`
-	Popularity  uint     `json:"popularity" xml:"popularity"`
-	SeriesCount uint     `json:"series_count" xml:"series_count"`
+	Name        string   `json:"name" xml:"name,attr"`
+	GroupId     TagId    `json:"group_id" xml:"group_id,attr"`
+	Notes       string   `json:"notes" xml:"notes,attr"`
+	Created     DateTime `json:"created" xml:"created,attr"`
+	Popularity  uint     `json:"popularity" xml:"popularity,attr"`
+	SeriesCount uint     `json:"series_count" xml:"series_count,attr"`
+}
+
+//==============================================================================
+//
+// GET: /fred/tags
+//
+//==============================================================================
+
+// Holds the data needed to request every `Tag`, or the tags related to a set of tags.
+type TagsRequest struct {
+	baseRequest
+	DatedRequest
+	TaggedRequest
+	PagedRequest
+	OrderedRequest
+
+	TagGroupId TagId
+	Search     string
+}
+
+func NewTagsRequest(tag_id TagId, search string) TagsRequest {
+	return TagsRequest{
+		TagGroupId: tag_id,
+		Search:     search,
+	}
+}
+
+func NewRelatedTagsRequest(tags ...string) TagsRequest {
+	return TagsRequest{
+		TaggedRequest: TaggedRequest{
+			Tags: tags,
+		},
+	}
+}
+
+// Satisfies the `Request` interface.
+func (r TagsRequest) ToParams() url.Values {
+	v := r.baseRequest.ToParams()
+	r.DatedRequest.MergeParams(v)
+	r.TaggedRequest.MergeParams(v)
+	r.PagedRequest.MergeParams(v)
+	r.OrderedRequest.MergeParams(v)
+
+	if r.TagGroupId != TagNone {
+		v.Set("tag_group_id", r.TagGroupId.String())
+	}
+	if len(r.Search) > 0 {
+		v.Set("search_text", r.Search)
+	}
+
+	return v
+}
+
+// Satisfies the `Request` interface.
+func (r TagsRequest) Validate() error {
+	return validate_all(
+		r.DatedRequest.Validate(),
+		r.TaggedRequest.Validate(),
+		r.PagedRequest.Validate(),
+		r.OrderedRequest.Validate(),
+		r.TagGroupId.validate(),
+	)
+}
+
+type TagsResponse struct {
+	Start  Date      `json:"realtime_start" xml:"realtime_start,attr"`
+	End    Date      `json:"realtime_end" xml:"realtime_end,attr"`
+	Order  OrderType `json:"order_by" xml:"order_by,attr"`
+	Sort   SortType  `json:"sort_order" xml:"sort_order,attr"`
+	Count  uint      `json:"count" xml:"count,attr"`
+	Offset uint      `json:"offset" xml:"offset,attr"`
+	Limit  uint      `json:"limit" xml:"limit,attr"`
+	Tags   []Tag     `json:"tags" xml:"tag"`
+}
+
+// Get every `Tag`, optionally restricted to a group, a search or a set of names.
+func (c Client) Tags(req TagsRequest) (TagsResponse, Error) {
+	return c.TagsContext(context.Background(), req)
+}
+
+// Same as `Tags`, but the request is bound to the given context.
+func (c Client) TagsContext(ctx context.Context, req TagsRequest) (TagsResponse, Error) {
+	return c.tags(ctx, "tags", "tags", false, req)
+}
+
+// Iterate over every `Tag`, fetching pages of `req.Limit` items starting from
+// `req.Offset` until every page has been read, an error occurs or `ctx` is done.
+func (c Client) TagsAll(ctx context.Context, req TagsRequest) *TagIterator {
+	it := &TagIterator{}
+	it.pager = new_pager(ctx, req.Offset, func(ctx context.Context, offset uint) (uint, int, Error) {
+		req.Offset = offset
+		res, err := c.TagsContext(ctx, req)
+		if err != nil {
+			return 0, 0, err
+		}
+		it.page = res.Tags
+		return res.Count, len(res.Tags), nil
+	})
+	return it
+}
+
+//==============================================================================
+//
+// GET: /fred/related_tags
+//
+//==============================================================================
+
+// Get the `Tag`s related to `req.Tags`, i.e. the tags assigned to series that
+// match all of `req.Tags` and none of `req.Exclude`.
+//
+// At least one tag must be given.
+func (c Client) RelatedTags(req TagsRequest) (TagsResponse, Error) {
+	return c.RelatedTagsContext(context.Background(), req)
+}
+
+// Same as `RelatedTags`, but the request is bound to the given context.
+func (c Client) RelatedTagsContext(ctx context.Context, req TagsRequest) (TagsResponse, Error) {
+	return c.tags(ctx, "related tags", "related_tags", true, req)
+}
+
+// Shared implementation of `Tags` and `RelatedTags`, the latter needing tags.
+func (c Client) tags(ctx context.Context, desc, path string, related bool, req TagsRequest) (TagsResponse, Error) {
+	req.baseRequest = c.base_req
+	req_url := c.base_url
+	req_url.RawQuery = req.ToParams().Encode()
+	req_url.Path = fmt.Sprintf("%s/%s", req_url.Path, path)
+	if related && len(req.Tags) == 0 {
+		return TagsResponse{}, c.invalid_request(req_url.String(), desc, fmt.Errorf("no tags given"))
+	}
+	if err := req.Validate(); err != nil {
+		return TagsResponse{}, c.invalid_request(req_url.String(), desc, err)
+	}
+
+	body, err := c.get(ctx, desc, req_url.String())
+	if err != nil {
+		return TagsResponse{}, err.Prefixf("error getting %s", desc)
+	}
+
+	var result TagsResponse
+	err = c.unmarshal_body(req_url.String(), body, &result)
+	return result, err
+}
+
+//==============================================================================
+//
+// GET: /fred/tags/series
+//
+//==============================================================================
+
+// Holds the data needed to request the `Series` matching a set of tags.
+type TagsSeriesRequest struct {
+	baseRequest
+	DatedRequest
+	TaggedRequest
+	PagedRequest
+	OrderedRequest
+}
+
+func NewTagsSeriesRequest(tags ...string) TagsSeriesRequest {
+	return TagsSeriesRequest{
+		TaggedRequest: TaggedRequest{
+			Tags: tags,
+		},
+	}
+}
+
+// Satisfies the `Request` interface.
+func (r TagsSeriesRequest) ToParams() url.Values {
+	v := r.baseRequest.ToParams()
+	r.DatedRequest.MergeParams(v)
+	r.TaggedRequest.MergeParams(v)
+	r.PagedRequest.MergeParams(v)
+	r.OrderedRequest.MergeParams(v)
+	return v
+}
+
+// Satisfies the `Request` interface.
+//
+// At least one tag must be given.
+func (r TagsSeriesRequest) Validate() error {
+	if len(r.Tags) == 0 {
+		return fmt.Errorf("no tags given")
+	}
+	return validate_all(
+		r.DatedRequest.Validate(),
+		r.TaggedRequest.Validate(),
+		r.PagedRequest.Validate(),
+		r.OrderedRequest.Validate(),
+	)
+}
+
+type TagsSeriesResponse struct {
+	Start  Date      `json:"realtime_start" xml:"realtime_start,attr"`
+	End    Date      `json:"realtime_end" xml:"realtime_end,attr"`
+	Order  OrderType `json:"order_by" xml:"order_by,attr"`
+	Sort   SortType  `json:"sort_order" xml:"sort_order,attr"`
+	Count  uint      `json:"count" xml:"count,attr"`
+	Offset uint      `json:"offset" xml:"offset,attr"`
+	Limit  uint      `json:"limit" xml:"limit,attr"`
+	Series []Series  `json:"seriess" xml:"series"`
+}
+
+// Get the `Series` matching all of `req.Tags` and none of `req.Exclude`.
+func (c Client) TagsSeries(req TagsSeriesRequest) (TagsSeriesResponse, Error) {
+	return c.TagsSeriesContext(context.Background(), req)
+}
+
+// Same as `TagsSeries`, but the request is bound to the given context.
+func (c Client) TagsSeriesContext(ctx context.Context, req TagsSeriesRequest) (TagsSeriesResponse, Error) {
+	req.baseRequest = c.base_req
+	req_url := c.base_url
+	req_url.RawQuery = req.ToParams().Encode()
+	req_url.Path = fmt.Sprintf("%s/tags/series", req_url.Path)
+	if err := req.Validate(); err != nil {
+		return TagsSeriesResponse{}, c.invalid_request(req_url.String(), "tags series", err)
+	}
+
+	body, err := c.get(ctx, "tags series", req_url.String())
+	if err != nil {
+		return TagsSeriesResponse{}, err.Prefixf("error getting series for tags")
+	}
+
+	var result TagsSeriesResponse
+	err = c.unmarshal_body(req_url.String(), body, &result)
+	return result, err
+}
+
+// Iterate over every `Series` matching the tags, fetching pages of `req.Limit`
+// items starting from `req.Offset` until every page has been read, an error
+// occurs or `ctx` is done.
+func (c Client) TagsSeriesAll(ctx context.Context, req TagsSeriesRequest) *SeriesIterator {
+	it := &SeriesIterator{}
+	it.pager = new_pager(ctx, req.Offset, func(ctx context.Context, offset uint) (uint, int, Error) {
+		req.Offset = offset
+		res, err := c.TagsSeriesContext(ctx, req)
+		if err != nil {
+			return 0, 0, err
+		}
+		it.page = res.Series
+		return res.Count, len(res.Series), nil
+	})
+	return it
 }
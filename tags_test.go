@@ -0,0 +1,154 @@
+package gofred
+
+import (
+	"strings"
+	"testing"
+)
+
+//==============================================================================
+//
+// GET: /fred/tags
+//
+//==============================================================================
+
+func TestTags_Search(t *testing.T) {
+	limit := 10
+	req := NewTagsRequest(TagGeography, "")
+	req.Order = OrderName
+	req.Sort = SortAscending
+	req.Limit = uint(limit)
+
+	fake_test(t, func(client Client) {
+		res, err := client.Tags(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(res.Tags) != limit {
+			t.Errorf("did not limit request to %d entries, got: %d", limit, len(res.Tags))
+		}
+
+		last := ""
+		for _, tag := range res.Tags {
+			if len(last) > 0 && tag.Name < last {
+				t.Errorf("expected sorted by Name, got '%s' after '%s'", tag.Name, last)
+			}
+			last = tag.Name
+		}
+	})
+}
+
+//==============================================================================
+//
+// GET: /fred/related_tags
+//
+//==============================================================================
+
+func TestRelatedTags_MonthlyUSA(t *testing.T) {
+	limit := 5
+	req := NewRelatedTagsRequest("monthly", "usa")
+	req.Limit = uint(limit)
+
+	fake_test(t, func(client Client) {
+		res, err := client.RelatedTags(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(res.Tags) != limit {
+			t.Errorf("did not limit request to %d entries, got: %d", limit, len(res.Tags))
+		}
+
+		for _, tag := range res.Tags {
+			if tag.Name == "monthly" || tag.Name == "usa" {
+				t.Errorf("expected only tags related to the given tags, got: %s", tag.Name)
+			}
+		}
+	})
+}
+
+func TestRelatedTags_NoTags(t *testing.T) {
+	fake_test(t, func(client Client) {
+		res, err := client.RelatedTags(NewRelatedTagsRequest())
+		if err == nil {
+			t.Fatalf("expected an error response, got: %+v", res)
+		}
+		if err.Type() != Invalid {
+			t.Errorf("expected type: %v, got: %v", Invalid, err.Type())
+		}
+	})
+}
+
+//==============================================================================
+//
+// GET: /fred/tags/series
+//
+//==============================================================================
+
+func TestTagsSeries_MonthlyUSA(t *testing.T) {
+	limit := 10
+	req := NewTagsSeriesRequest("usa", "nsa", "monthly")
+	req.Order = OrderId
+	req.Sort = SortAscending
+	req.Limit = uint(limit)
+
+	fake_test(t, func(client Client) {
+		res, err := client.TagsSeries(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(res.Series) != limit {
+			t.Errorf("did not limit request to %d entries, got: %d", limit, len(res.Series))
+		}
+
+		for _, s := range res.Series {
+			if s.Frequency != Monthly {
+				t.Errorf("expected monthly series, got: %v", s.Frequency)
+			}
+			if s.SeasonallyAdjusted {
+				t.Errorf("expected series not seasonally adjusted, got: %s", s.Id)
+			}
+			if len(strings.TrimSpace(s.Title)) == 0 {
+				t.Errorf("expected a series title, got: %+v", s)
+			}
+		}
+	})
+}
+
+func TestTagsSeries_Exclude(t *testing.T) {
+	req := NewTagsSeriesRequest("monthly", "nation")
+	req.Exclude = []string{"usa"}
+
+	fake_test(t, func(client Client) {
+		res, err := client.TagsSeries(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(res.Series) != 1 || res.Series[0].Id != SERIES_EXCHANGE_JP_US {
+			t.Errorf("expected only the series not tagged usa, got: %+v", res.Series)
+		}
+	})
+}
+
+func TestRelatedTags_Exclude(t *testing.T) {
+	req := NewRelatedTagsRequest("monthly")
+	req.Exclude = []string{"japan"}
+
+	fake_test(t, func(client Client) {
+		res, err := client.RelatedTags(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(res.Tags) == 0 {
+			t.Fatalf("expected tags related to monthly series")
+		}
+		for _, tag := range res.Tags {
+			if tag.Name == "h10" || tag.Name == "exchange rate" {
+				t.Errorf("expected no tags of the excluded series, got: %s", tag.Name)
+			}
+		}
+	})
+}
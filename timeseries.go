@@ -0,0 +1,201 @@
+package gofred
+
+import (
+	"context"
+	"sort"
+	"time"
+)
+
+//==============================================================================
+// time series
+//==============================================================================
+
+// The observations of a series, ordered by date, along with its metadata.
+//
+// `Frequency` and `Units` are those of the values, which differ from the
+// series' own when FRED aggregated or transformed them.
+type TimeSeries struct {
+	Series    Series
+	Frequency Frequency
+	Units     UnitType
+	Points    []DataPoint // one per date, missing values included
+}
+
+// Build a time series from observations in any order.
+//
+// The points are copied and sorted by date, the last point of a date winning.
+// The frequency is the series', and the units linear.
+func NewTimeSeries(series Series, points []DataPoint) TimeSeries {
+	sorted := append([]DataPoint{}, points...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return time.Time(sorted[i].Date).Before(time.Time(sorted[j].Date))
+	})
+
+	unique := sorted[:0]
+	for _, point := range sorted {
+		if n := len(unique); n > 0 && time.Time(unique[n-1].Date).Equal(time.Time(point.Date)) {
+			unique[n-1] = point
+			continue
+		}
+		unique = append(unique, point)
+	}
+
+	return TimeSeries{Series: series, Frequency: series.Frequency, Units: UnitLinear, Points: unique}
+}
+
+// The observations of the response as a time series of the given series.
+//
+// If the observations were aggregated, set `Frequency` to that requested.
+func (r SeriesObservationsResponse) TimeSeries(series Series) TimeSeries {
+	ts := NewTimeSeries(series, r.Observations)
+	ts.Units = r.Units
+	return ts
+}
+
+// The observations of the response as they were published on the given date.
+func (r SeriesVintageObservationsResponse) TimeSeries(series Series, as_of time.Time) TimeSeries {
+	ts := r.Snapshot(as_of).TimeSeries(series)
+	ts.Units = r.Units
+	return ts
+}
+
+// The snapshot's observations as a time series of the given series.
+func (s SeriesSnapshot) TimeSeries(series Series) TimeSeries {
+	return NewTimeSeries(series, s.Observations)
+}
+
+// Fetch the series along with every observation matching the request, walking
+// every page.
+//
+// The frequency is the one requested if the observations are aggregated.
+func (c Client) TimeSeries(ctx context.Context, req SeriesObservationsRequest) (TimeSeries, Error) {
+	series_req := NewSeriesRequest(req.Series)
+	series_req.DatedRequest = req.DatedRequest
+	series, err := c.SeriesContext(ctx, series_req)
+	if err != nil {
+		return TimeSeries{}, err
+	}
+
+	points := []DataPoint{}
+	it := c.SeriesObservationsAll(ctx, req)
+	for it.Next() {
+		points = append(points, it.Observation())
+	}
+	if err := it.Err(); err != nil {
+		return TimeSeries{}, err.Prefixf("could not get observations of series %s:", req.Series)
+	}
+
+	ts := NewTimeSeries(series, points)
+	ts.Units = req.Units
+	if len(req.Aggregation) > 0 {
+		ts.Frequency = req.Frequency
+	}
+	return ts, nil
+}
+
+// Number of observations, missing ones included.
+func (ts TimeSeries) Len() int {
+	return len(ts.Points)
+}
+
+// Index of the first point on or after the date, `Len()` if there is none.
+func (ts TimeSeries) index(date time.Time) int {
+	return sort.Search(len(ts.Points), func(i int) bool {
+		return !time.Time(ts.Points[i].Date).Before(date)
+	})
+}
+
+// The point on the given date, if any. It may be a missing value.
+func (ts TimeSeries) At(date time.Time) (DataPoint, bool) {
+	i := ts.index(date)
+	if i < len(ts.Points) && time.Time(ts.Points[i].Date).Equal(date) {
+		return ts.Points[i], true
+	}
+	return DataPoint{}, false
+}
+
+// The value on the given date, false if there is no point or it is missing.
+func (ts TimeSeries) Value(date time.Time) (float64, bool) {
+	point, found := ts.At(date)
+	return point.Value, found && point.Valid
+}
+
+// The points from `start` through `end`, either of which can be zero to leave
+// that side open.
+//
+// The result shares its points with the time series.
+func (ts TimeSeries) Slice(start, end time.Time) TimeSeries {
+	from, to := 0, len(ts.Points)
+	if !start.IsZero() {
+		from = ts.index(start)
+	}
+	if !end.IsZero() {
+		to = sort.Search(len(ts.Points), func(i int) bool {
+			return time.Time(ts.Points[i].Date).After(end)
+		})
+	}
+	if to < from {
+		to = from
+	}
+
+	sliced := ts
+	sliced.Points = ts.Points[from:to:to]
+	return sliced
+}
+
+// Calls `fn` with every point which is not missing, in order, until it returns false.
+func (ts TimeSeries) Each(fn func(DataPoint) bool) {
+	for _, point := range ts.Points {
+		if point.Valid && !fn(point) {
+			return
+		}
+	}
+}
+
+// The points which are not missing.
+func (ts TimeSeries) Valid() []DataPoint {
+	valid := []DataPoint{}
+	ts.Each(func(point DataPoint) bool {
+		valid = append(valid, point)
+		return true
+	})
+	return valid
+}
+
+// The latest point which is not missing, if any.
+func (ts TimeSeries) Last() (DataPoint, bool) {
+	for i := len(ts.Points) - 1; i >= 0; i-- {
+		if ts.Points[i].Valid {
+			return ts.Points[i], true
+		}
+	}
+	return DataPoint{}, false
+}
+
+// Restricts each time series to the dates every one of them has a value on,
+// so their points line up one to one.
+func Align(series ...TimeSeries) []TimeSeries {
+	aligned := make([]TimeSeries, len(series))
+	for i, ts := range series {
+		aligned[i] = ts
+		aligned[i].Points = []DataPoint{}
+	}
+	if len(series) == 0 {
+		return aligned
+	}
+
+	series[0].Each(func(point DataPoint) bool {
+		date := time.Time(point.Date)
+		for _, other := range series[1:] {
+			if _, ok := other.Value(date); !ok {
+				return true
+			}
+		}
+		for i, ts := range series {
+			found, _ := ts.At(date)
+			aligned[i].Points = append(aligned[i].Points, found)
+		}
+		return true
+	})
+	return aligned
+}
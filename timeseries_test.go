@@ -0,0 +1,119 @@
+package gofred
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func time_series_point(t *testing.T, date string, value float64, valid bool) DataPoint {
+	return DataPoint{Date(vintage_date(t, date)), value, valid}
+}
+
+func TestNewTimeSeries(t *testing.T) {
+	series := Series{Id: SERIES_GNP_ANNUAL, Frequency: Annual}
+	ts := NewTimeSeries(series, []DataPoint{
+		time_series_point(t, "2002-01-01", 3, true),
+		time_series_point(t, "2000-01-01", 1, true),
+		time_series_point(t, "2001-01-01", 0, false),
+		time_series_point(t, "2000-01-01", 1.5, true),
+		time_series_point(t, "2003-01-01", 4, true),
+	})
+
+	if ts.Frequency != Annual || ts.Units != UnitLinear || ts.Series.Id != SERIES_GNP_ANNUAL {
+		t.Errorf("expected the series' metadata, got: %+v", ts)
+	}
+	check_data_points(t, []DataPoint{
+		time_series_point(t, "2000-01-01", 1.5, true),
+		time_series_point(t, "2001-01-01", 0, false),
+		time_series_point(t, "2002-01-01", 3, true),
+		time_series_point(t, "2003-01-01", 4, true),
+	}, ts.Points)
+
+	if point, found := ts.At(vintage_date(t, "2001-01-01")); !found || point.Valid {
+		t.Errorf("expected the missing point, got: %+v (%v)", point, found)
+	}
+	if _, found := ts.At(vintage_date(t, "2001-06-01")); found {
+		t.Errorf("expected no point between observations")
+	}
+	if value, ok := ts.Value(vintage_date(t, "2002-01-01")); !ok || value != 3 {
+		t.Errorf("expected 3, got: %v (%v)", value, ok)
+	}
+	if _, ok := ts.Value(vintage_date(t, "2001-01-01")); ok {
+		t.Errorf("expected no value for a missing point")
+	}
+
+	sliced := ts.Slice(vintage_date(t, "2000-06-01"), vintage_date(t, "2002-01-01"))
+	check_data_points(t, ts.Points[1:3], sliced.Points)
+	if sliced.Series.Id != SERIES_GNP_ANNUAL {
+		t.Errorf("expected slices to keep the metadata")
+	}
+	if open := ts.Slice(time.Time{}, vintage_date(t, "2000-01-01")); open.Len() != 1 {
+		t.Errorf("expected only the first point, got: %+v", open.Points)
+	}
+	if empty := ts.Slice(vintage_date(t, "2004-01-01"), time.Time{}); empty.Len() != 0 {
+		t.Errorf("expected no points after the last, got: %+v", empty.Points)
+	}
+
+	check_data_points(t, []DataPoint{ts.Points[0], ts.Points[2], ts.Points[3]}, ts.Valid())
+	seen := 0
+	ts.Each(func(DataPoint) bool {
+		seen++
+		return seen < 2
+	})
+	if seen != 2 {
+		t.Errorf("expected iteration to stop after 2 points, got: %d", seen)
+	}
+	if last, ok := ts.Last(); !ok || last.Value != 4 {
+		t.Errorf("expected the last value, got: %+v", last)
+	}
+}
+
+func TestAlign(t *testing.T) {
+	a := NewTimeSeries(Series{Id: "A"}, []DataPoint{
+		time_series_point(t, "2000-01-01", 1, true),
+		time_series_point(t, "2000-02-01", 2, true),
+		time_series_point(t, "2000-03-01", 3, true),
+		time_series_point(t, "2000-04-01", 4, true),
+	})
+	b := NewTimeSeries(Series{Id: "B"}, []DataPoint{
+		time_series_point(t, "2000-02-01", 20, true),
+		time_series_point(t, "2000-03-01", 0, false),
+		time_series_point(t, "2000-04-01", 40, true),
+		time_series_point(t, "2000-05-01", 50, true),
+	})
+
+	aligned := Align(a, b)
+	if len(aligned) != 2 || aligned[0].Series.Id != "A" || aligned[1].Series.Id != "B" {
+		t.Fatalf("expected both series in order, got: %+v", aligned)
+	}
+	check_data_points(t, []DataPoint{a.Points[1], a.Points[3]}, aligned[0].Points)
+	check_data_points(t, []DataPoint{b.Points[0], b.Points[2]}, aligned[1].Points)
+}
+
+func TestClientTimeSeries(t *testing.T) {
+	fake_test(t, func(client Client) {
+		req := NewSeriesObservationsRequest(SERIES_GNP_ANNUAL, time.Time{}, time.Time{})
+		req.Limit = 25
+		ts, err := client.TimeSeries(context.Background(), req)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if ts.Series.Title != "Real Gross National Product" || ts.Frequency != Annual {
+			t.Errorf("expected the series' metadata, got: %+v", ts.Series)
+		}
+		if ts.Len() <= 25 {
+			t.Errorf("expected every page, got %d points", ts.Len())
+		}
+		if _, ok := ts.Value(vintage_date(t, "1929-01-01")); !ok {
+			t.Errorf("expected a value for the first year")
+		}
+
+		res, err := client.SeriesObservations(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		check_data_points(t, res.Observations, res.TimeSeries(ts.Series).Points)
+	})
+}
@@ -0,0 +1,125 @@
+package gofred
+
+import (
+	"fmt"
+	"math"
+)
+
+//==============================================================================
+// unit transformations
+//==============================================================================
+
+// Number of observations per year FRED uses to compute year-ago and annualized
+// rates, false for an unknown frequency.
+//
+// Daily series count 260 observations a year, having none on weekends.
+func (f Frequency) PeriodsPerYear() (int, bool) {
+	switch f {
+	case Daily:
+		return 260, true
+	case Weekly, WeeklyEndingFriday, WeeklyEndingThursday, WeeklyEndingWednesday,
+		WeeklyEndingTuesday, WeeklyEndingMonday, WeeklyEndingSunday, WeeklyEndingSaturday:
+		return 52, true
+	case Biweekly, BiweeklyEndingWednesday, BiweeklyEndingMonday:
+		return 26, true
+	case Monthly:
+		return 12, true
+	case Quarterly:
+		return 4, true
+	case Semiannual:
+		return 2, true
+	case Annual:
+		return 1, true
+	}
+	return 0, false
+}
+
+// Transform linear values into the given units, with the formulas FRED documents
+// for its `units` parameter:
+//
+//	chg  x(t) - x(t-1)
+//	ch1  x(t) - x(t-n)
+//	pch  (x(t)/x(t-1) - 1) * 100
+//	pc1  (x(t)/x(t-n) - 1) * 100
+//	pca  ((x(t)/x(t-1))^n - 1) * 100
+//	cch  (ln x(t) - ln x(t-1)) * 100
+//	cca  (ln x(t) - ln x(t-1)) * 100 * n
+//	log  ln x(t)
+//
+// where `t-1` is the previous observation and `n` the number of observations
+// per year of the series' `Frequency`. Every date is kept, values without a
+// previous or year-ago value, or whose result is undefined, are missing.
+//
+// Lags count observations, not dates, as FRED's 260 observations a year for
+// daily series imply: a holiday of a daily series is a missing observation, so
+// the changes to and from it are missing and the year-ago value is always 260
+// observations back.
+//
+// FRED rounds the values it sends, so compare them with some tolerance.
+func (ts TimeSeries) Transform(units UnitType) (TimeSeries, error) {
+	if ts.Units != UnitLinear {
+		return TimeSeries{}, fmt.Errorf("cannot transform values already in units %v", ts.Units)
+	}
+
+	per_year, known := ts.Frequency.PeriodsPerYear()
+	lag := 1
+	switch units {
+	case UnitChangeFromYearAgo, UnitPercentChangeFromYearAgo:
+		lag = per_year
+		fallthrough
+	case UnitCompoundedAnnualRateOfChange, UnitContinuouslyCompoundedAnnualRateOfChange:
+		if !known {
+			return TimeSeries{}, fmt.Errorf("cannot compute %v for frequency %v", units, ts.Frequency)
+		}
+	case UnitLinear, UnitChange, UnitPercentChange, UnitContinuouslyCompoundedRateOfChange, UnitNaturalLog:
+	default:
+		return TimeSeries{}, fmt.Errorf("unknown units %d", units)
+	}
+
+	result := ts
+	result.Units = units
+	result.Points = make([]DataPoint, len(ts.Points))
+	for i, point := range ts.Points {
+		result.Points[i] = DataPoint{Date: point.Date}
+
+		var value float64
+		if units == UnitLinear || units == UnitNaturalLog {
+			if !point.Valid {
+				continue
+			}
+			value = transform_value(units, point.Value, 0, per_year)
+		} else {
+			if i < lag || !point.Valid || !ts.Points[i-lag].Valid {
+				continue
+			}
+			value = transform_value(units, point.Value, ts.Points[i-lag].Value, per_year)
+		}
+
+		if !math.IsNaN(value) && !math.IsInf(value, 0) {
+			result.Points[i].Value, result.Points[i].Valid = value, true
+		}
+	}
+	return result, nil
+}
+
+// The transformed value of `x` given the previous or year-ago value `prev`.
+//
+// Undefined results, e.g. from dividing by zero or the log of a negative value,
+// are NaN or infinite.
+func transform_value(units UnitType, x, prev float64, per_year int) float64 {
+	switch units {
+	case UnitChange, UnitChangeFromYearAgo:
+		return x - prev
+	case UnitPercentChange, UnitPercentChangeFromYearAgo:
+		return (x/prev - 1) * 100
+	case UnitCompoundedAnnualRateOfChange:
+		return (math.Pow(x/prev, float64(per_year)) - 1) * 100
+	case UnitContinuouslyCompoundedRateOfChange:
+		return (math.Log(x) - math.Log(prev)) * 100
+	case UnitContinuouslyCompoundedAnnualRateOfChange:
+		return (math.Log(x) - math.Log(prev)) * 100 * float64(per_year)
+	case UnitNaturalLog:
+		return math.Log(x)
+	}
+	return x
+}
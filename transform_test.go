@@ -0,0 +1,227 @@
+package gofred
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+)
+
+const SERIES_EXCHANGE_JP_US_DAILY = "DEXJPUS"
+
+func close_to(a, b, tolerance float64) bool {
+	return math.Abs(a-b) <= tolerance*math.Max(1, math.Abs(b))
+}
+
+// Quarterly values growing by 1% a quarter, with a missing value.
+func quarterly_growth(t *testing.T) TimeSeries {
+	return NewTimeSeries(Series{Id: "Q", Frequency: Quarterly}, []DataPoint{
+		time_series_point(t, "2000-01-01", 100, true),
+		time_series_point(t, "2000-04-01", 101, true),
+		time_series_point(t, "2000-07-01", 102.01, true),
+		time_series_point(t, "2000-10-01", 0, false),
+		time_series_point(t, "2001-01-01", 104.060401, true),
+		time_series_point(t, "2001-04-01", 105.10100501, true),
+	})
+}
+
+func TestTransform(t *testing.T) {
+	ts := quarterly_growth(t)
+	nan := math.NaN()
+	tests := []struct {
+		units  UnitType
+		expect []float64 // NaN for missing values
+	}{
+		{UnitLinear, []float64{100, 101, 102.01, nan, 104.060401, 105.10100501}},
+		{UnitChange, []float64{nan, 1, 1.01, nan, nan, 1.04060401}},
+		{UnitChangeFromYearAgo, []float64{nan, nan, nan, nan, 4.060401, 4.10100501}},
+		{UnitPercentChange, []float64{nan, 1, 1, nan, nan, 1}},
+		{UnitPercentChangeFromYearAgo, []float64{nan, nan, nan, nan, 4.060401, 4.060401}},
+		{UnitCompoundedAnnualRateOfChange, []float64{nan, 4.060401, 4.060401, nan, nan, 4.060401}},
+		{UnitContinuouslyCompoundedRateOfChange, []float64{nan, 100 * math.Log(1.01), 100 * math.Log(1.01), nan, nan, 100 * math.Log(1.01)}},
+		{UnitContinuouslyCompoundedAnnualRateOfChange, []float64{nan, 400 * math.Log(1.01), 400 * math.Log(1.01), nan, nan, 400 * math.Log(1.01)}},
+		{UnitNaturalLog, []float64{math.Log(100), math.Log(101), math.Log(102.01), nan, math.Log(104.060401), math.Log(105.10100501)}},
+	}
+
+	for _, test := range tests {
+		transformed, err := ts.Transform(test.units)
+		if err != nil {
+			t.Fatalf("%v: %v", test.units, err)
+		}
+		if transformed.Units != test.units || transformed.Len() != ts.Len() {
+			t.Errorf("%v: expected every date in the new units, got: %+v", test.units, transformed)
+			continue
+		}
+
+		for i, expect := range test.expect {
+			point := transformed.Points[i]
+			if point.Date != ts.Points[i].Date {
+				t.Errorf("%v, point %d: expected date %v, got: %v", test.units, i, ts.Points[i].Date, point.Date)
+			}
+			if math.IsNaN(expect) {
+				if point.Valid {
+					t.Errorf("%v, point %d: expected a missing value, got: %v", test.units, i, point.Value)
+				}
+			} else if !point.Valid || !close_to(point.Value, expect, 1e-9) {
+				t.Errorf("%v, point %d: expected %v, got: %+v", test.units, i, expect, point)
+			}
+		}
+	}
+
+	if ts.Points[1].Value != 101 {
+		t.Errorf("expected the original time series to be left alone")
+	}
+}
+
+func TestTransform_Errors(t *testing.T) {
+	ts := quarterly_growth(t)
+	changed, err := ts.Transform(UnitChange)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := changed.Transform(UnitPercentChange); err == nil {
+		t.Errorf("expected transformed values to not be transformed again")
+	}
+	if _, err := ts.Transform(UnitNaturalLog + 1); err == nil {
+		t.Errorf("expected unknown units to fail")
+	}
+
+	ts.Frequency = UnknownFrequency
+	if _, err := ts.Transform(UnitPercentChangeFromYearAgo); err == nil {
+		t.Errorf("expected year-ago changes to need a frequency")
+	}
+	if _, err := ts.Transform(UnitPercentChange); err != nil {
+		t.Errorf("expected period changes to not need a frequency, got: %v", err)
+	}
+
+	// undefined values are missing
+	zero := NewTimeSeries(Series{Frequency: Annual}, []DataPoint{
+		time_series_point(t, "2000-01-01", 0, true),
+		time_series_point(t, "2001-01-01", 5, true),
+	})
+	for _, units := range []UnitType{UnitPercentChange, UnitContinuouslyCompoundedRateOfChange, UnitNaturalLog} {
+		transformed, err := zero.Transform(units)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if transformed.Points[0].Valid || (units != UnitNaturalLog && transformed.Points[1].Valid) {
+			t.Errorf("%v: expected undefined values to be missing, got: %+v", units, transformed.Points)
+		}
+	}
+}
+
+func TestFrequency_PeriodsPerYear(t *testing.T) {
+	expect := map[Frequency]int{Daily: 260, WeeklyEndingFriday: 52, Biweekly: 26, Monthly: 12, Quarterly: 4, Semiannual: 2, Annual: 1}
+	for freq, n := range expect {
+		if got, ok := freq.PeriodsPerYear(); !ok || got != n {
+			t.Errorf("%v: expected %d periods per year, got: %d", freq.LongString(), n, got)
+		}
+	}
+	if _, ok := Frequency(UnknownFrequency).PeriodsPerYear(); ok {
+		t.Errorf("expected no periods per year for an unknown frequency")
+	}
+}
+
+// Daily values, one per weekday from 2001-01-01, with holidays missing.
+func daily_with_holidays(t *testing.T, count int, holidays ...int) TimeSeries {
+	points := []DataPoint{}
+	missing := map[int]bool{}
+	for _, i := range holidays {
+		missing[i] = true
+	}
+	for date := vintage_date(t, "2001-01-01"); len(points) < count; date = date.AddDate(0, 0, 1) {
+		if date.Weekday() == time.Saturday || date.Weekday() == time.Sunday {
+			continue
+		}
+		i := len(points)
+		points = append(points, DataPoint{Date(date), 100 + float64(i), !missing[i]})
+	}
+	return NewTimeSeries(Series{Id: "D", Frequency: Daily}, points)
+}
+
+// Holidays are observations of a daily series, missing ones: they count towards
+// the 260 observations of a year, and the changes to and from them are missing.
+// This pins the documented behavior of `Transform`, TestTransform_MatchesFREDDaily
+// checks it against FRED.
+func TestTransform_DailyHolidays(t *testing.T) {
+	ts := daily_with_holidays(t, 270, 2, 5)
+
+	changed, err := ts.Transform(UnitChange)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, valid := range []bool{false, true, false, false, true, false, false, true} {
+		if changed.Points[i].Valid != valid || (valid && changed.Points[i].Value != 1) {
+			t.Errorf("chg, point %d: expected valid: %v, got: %+v", i, valid, changed.Points[i])
+		}
+	}
+
+	year_ago, err := ts.Transform(UnitChangeFromYearAgo)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, point := range year_ago.Points {
+		valid := i >= 260 && i != 262 && i != 265
+		if point.Valid != valid || (valid && point.Value != 260) {
+			t.Errorf("ch1, point %d: expected valid: %v, got: %+v", i, valid, point)
+		}
+	}
+}
+
+// Every local transformation of the series matches the values FRED sends for
+// the same units from `start` through `end`, either of which can be zero.
+//
+// The local values are computed from two more years of observations, so those
+// from a year ago are known from the first date on.
+func check_transforms_match_fred(t *testing.T, client Client, series string, start, end time.Time) {
+	from := start
+	if !from.IsZero() {
+		from = from.AddDate(-2, 0, 0)
+	}
+	ts, err := client.TimeSeries(context.Background(), NewSeriesObservationsRequest(series, from, end))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req := NewSeriesObservationsRequest(series, start, end)
+	for units := UnitChange; units <= UnitNaturalLog; units++ {
+		req.Units = units
+		res, err := client.SeriesObservations(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(res.Observations) == 0 {
+			t.Fatalf("%s %v: no observations from FRED", series, units)
+		}
+
+		local, transform_err := ts.Transform(units)
+		if transform_err != nil {
+			t.Fatal(transform_err)
+		}
+		for _, fred := range res.Observations {
+			point, found := local.At(time.Time(fred.Date))
+			if !found || point.Valid != fred.Valid || (fred.Valid && !close_to(point.Value, fred.Value, 1e-3)) {
+				t.Errorf("%s %v on %s: expected %+v, got: %+v", series, units, time.Time(fred.Date).Format(DATE_FORMAT), fred, point)
+			}
+		}
+	}
+}
+
+// Every local transformation matches the values FRED sends for the same units.
+//
+// Skipped until the responses are recorded with GOFRED_RECORD=1.
+func TestTransform_MatchesFRED(t *testing.T) {
+	mux_test(t, func(client Client) {
+		check_transforms_match_fred(t, client, SERIES_EXCHANGE_JP_US, time.Time{}, time.Time{})
+	})
+}
+
+// Daily values around Thanksgiving, Christmas and New Year's Day, which are
+// missing, match FRED's for every unit.
+//
+// Skipped until the responses are recorded with GOFRED_RECORD=1.
+func TestTransform_MatchesFREDDaily(t *testing.T) {
+	mux_test(t, func(client Client) {
+		check_transforms_match_fred(t, client, SERIES_EXCHANGE_JP_US_DAILY, vintage_date(t, "2023-11-20"), vintage_date(t, "2024-01-05"))
+	})
+}
@@ -0,0 +1,399 @@
+package gofred
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+//==============================================================================
+// vintages
+//==============================================================================
+
+// Format of the vintage suffix in observation columns, e.g. `GNPCA_20090731`.
+const VINTAGE_COLUMN_FORMAT = "20060102"
+
+// An observation along with the realtime period during which it was the
+// published value.
+//
+// `RealtimeEnd` is `REALTIME_LATEST` while the value is still current.
+type VintageDataPoint struct {
+	DataPoint
+	RealtimeStart Date
+	RealtimeEnd   Date
+}
+
+// Whether the value was the published one on the given date.
+func (d VintageDataPoint) KnownOn(day time.Time) bool {
+	return !day.Before(time.Time(d.RealtimeStart)) && !day.After(time.Time(d.RealtimeEnd))
+}
+
+// A value of a single vintage column in an observation.
+type vintageCell struct {
+	vintage Date
+	value   string
+}
+
+// A single observation as returned when requesting vintages.
+//
+// With `OutputByRealtimePeriod` the row carries its realtime period and value,
+// for the other output types it carries one cell per vintage column.
+type vintageRow struct {
+	date  Date
+	start Date
+	end   Date
+	value string
+	cells []vintageCell
+}
+
+func (r *vintageRow) UnmarshalJSON(input []byte) error {
+	var as_map map[string]string
+	if err := json.Unmarshal(input, &as_map); err != nil {
+		return err
+	}
+	return r.parse(as_map)
+}
+
+// Observations are `<observation>` elements with every field as an attribute.
+func (r *vintageRow) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	as_map := make(map[string]string, len(start.Attr))
+	for _, attr := range start.Attr {
+		as_map[attr.Name.Local] = attr.Value
+	}
+	if err := d.Skip(); err != nil {
+		return err
+	}
+	return r.parse(as_map)
+}
+
+func (r *vintageRow) parse(fields map[string]string) error {
+	*r = vintageRow{}
+	for key, value := range fields {
+		var err error
+		switch key {
+		case "date":
+			err = r.date.UnmarshalText([]byte(value))
+		case "realtime_start":
+			err = r.start.UnmarshalText([]byte(value))
+		case "realtime_end":
+			err = r.end.UnmarshalText([]byte(value))
+		case "value":
+			r.value = value
+		default:
+			split := strings.LastIndex(key, "_")
+			if split < 0 {
+				return fmt.Errorf("unexpected observation field '%s'", key)
+			}
+			var vintage time.Time
+			vintage, err = time.Parse(VINTAGE_COLUMN_FORMAT, key[split+1:])
+			r.cells = append(r.cells, vintageCell{vintage: Date(vintage), value: value})
+		}
+		if err != nil {
+			return fmt.Errorf("could not parse observation field '%s': %v", key, err)
+		}
+	}
+
+	if time.Time(r.date).IsZero() {
+		return fmt.Errorf("no date in observation")
+	}
+	sort.Slice(r.cells, func(i, j int) bool {
+		return time.Time(r.cells[i].vintage).Before(time.Time(r.cells[j].vintage))
+	})
+	return nil
+}
+
+// Builds a data point from the given value, which is invalid if missing (".").
+func parse_data_point(date Date, value string) (DataPoint, error) {
+	point := DataPoint{Date: date}
+	if value == "." {
+		return point, nil
+	}
+
+	var err error
+	point.Value, err = strconv.ParseFloat(value, 64)
+	if err != nil {
+		return point, fmt.Errorf("could not parse '%s': %v", value, err)
+	}
+	point.Valid = true
+	return point, nil
+}
+
+// Turns the rows of a vintage response into one point per value and realtime period.
+//
+// Points are ordered by date, then realtime start.
+func vintage_points(rows []vintageRow, output OutputType) ([]VintageDataPoint, error) {
+	points := []VintageDataPoint{}
+	for _, row := range rows {
+		row_points, err := row.points(output)
+		if err != nil {
+			return nil, err
+		}
+		points = append(points, row_points...)
+	}
+
+	sort.SliceStable(points, func(i, j int) bool {
+		a, b := points[i], points[j]
+		if !time.Time(a.Date).Equal(time.Time(b.Date)) {
+			return time.Time(a.Date).Before(time.Time(b.Date))
+		}
+		return time.Time(a.RealtimeStart).Before(time.Time(b.RealtimeStart))
+	})
+	return points, nil
+}
+
+// Turns the row into one point per value and realtime period, ordered by realtime start.
+//
+// With `OutputByVintageAll` every column is a full vintage, so a value holds until
+// the next column. With the other column outputs only new or revised values are
+// given, so a value holds until the next value given for the same date. Missing
+// (".") cells are never points, but with `OutputByRealtimePeriod` a missing value
+// is kept as an invalid point.
+func (row vintageRow) points(output OutputType) ([]VintageDataPoint, error) {
+	if len(row.cells) == 0 {
+		point, err := parse_data_point(row.date, row.value)
+		if err != nil {
+			return nil, err
+		}
+		return []VintageDataPoint{{DataPoint: point, RealtimeStart: row.start, RealtimeEnd: row.end}}, nil
+	}
+
+	cells := row.cells
+	if output != OutputByVintageAll {
+		cells = []vintageCell{}
+		for _, cell := range row.cells {
+			if cell.value != "." {
+				cells = append(cells, cell)
+			}
+		}
+	}
+
+	latest, _ := time.Parse(DATE_FORMAT, REALTIME_LATEST)
+	points := []VintageDataPoint{}
+	for i, cell := range cells {
+		if cell.value == "." {
+			continue
+		}
+
+		point, err := parse_data_point(row.date, cell.value)
+		if err != nil {
+			return nil, err
+		}
+
+		end := latest
+		if i+1 < len(cells) {
+			end = time.Time(cells[i+1].vintage).AddDate(0, 0, -1)
+		}
+
+		// unchanged across consecutive vintages, extend the previous period
+		if len(points) > 0 {
+			prev := &points[len(points)-1]
+			next_day := time.Time(prev.RealtimeEnd).AddDate(0, 0, 1)
+			if prev.Value == point.Value && next_day.Equal(time.Time(cell.vintage)) {
+				prev.RealtimeEnd = Date(end)
+				continue
+			}
+		}
+
+		points = append(points, VintageDataPoint{DataPoint: point, RealtimeStart: cell.vintage, RealtimeEnd: Date(end)})
+	}
+	return points, nil
+}
+
+//==============================================================================
+//
+// GET: /fred/series/observations (vintages)
+//
+//==============================================================================
+
+type SeriesVintageObservationsResponse struct {
+	Start            Date       `json:"realtime_start" xml:"realtime_start,attr"`
+	End              Date       `json:"realtime_end" xml:"realtime_end,attr"`
+	ObservationStart Date       `json:"observation_start" xml:"observation_start,attr"`
+	ObservationEnd   Date       `json:"observation_end" xml:"observation_end,attr"`
+	Order            OrderType  `json:"order_by" xml:"order_by,attr"`
+	Sort             SortType   `json:"sort_order" xml:"sort_order,attr"`
+	Count            uint       `json:"count" xml:"count,attr"`
+	Offset           uint       `json:"offset" xml:"offset,attr"`
+	Limit            uint       `json:"limit" xml:"limit,attr"`
+	Units            UnitType   `json:"units" xml:"units,attr"`
+	Output           OutputType `json:"output_type" xml:"output_type,attr"`
+
+	Observations []VintageDataPoint `json:"-" xml:"-"`
+}
+
+// Raw response, whose rows are turned into `Observations`.
+type seriesVintageObservationsResponse struct {
+	SeriesVintageObservationsResponse
+	Rows []vintageRow `json:"observations" xml:"observation"`
+}
+
+// Defaults the output type to `OutputByVintageAll` and, without vintage dates,
+// the realtime period to every vintage FRED knows.
+func (r SeriesObservationsRequest) with_vintage_defaults() SeriesObservationsRequest {
+	if r.Output == OutputDefault {
+		r.Output = OutputByVintageAll
+	}
+	if len(r.VintageDates) == 0 && time.Time(r.Start).IsZero() && time.Time(r.End).IsZero() {
+		earliest, _ := time.Parse(DATE_FORMAT, REALTIME_EARLIEST)
+		latest, _ := time.Parse(DATE_FORMAT, REALTIME_LATEST)
+		r.Start, r.End = Date(earliest), Date(latest)
+	}
+	return r
+}
+
+// Get every vintage of every observation of the series.
+//
+// Unless set, the output type defaults to `OutputByVintageAll` and, if no vintage
+// dates are given either, the realtime period spans every vintage FRED knows.
+func (c Client) SeriesVintageObservations(req SeriesObservationsRequest) (SeriesVintageObservationsResponse, Error) {
+	return c.SeriesVintageObservationsContext(context.Background(), req)
+}
+
+// Same as `SeriesVintageObservations`, but the request is bound to the given context.
+func (c Client) SeriesVintageObservationsContext(ctx context.Context, req SeriesObservationsRequest) (SeriesVintageObservationsResponse, Error) {
+	req = req.with_vintage_defaults()
+	req.baseRequest = c.base_req
+	req_url := c.base_url
+	req_url.RawQuery = req.ToParams().Encode()
+	req_url.Path = fmt.Sprintf("%s/series/observations", req_url.Path)
+	if err := req.Validate(); err != nil {
+		return SeriesVintageObservationsResponse{}, c.invalid_request(req_url.String(), "series vintage observations", err)
+	}
+
+	body, err := c.get(ctx, "series vintage observations", req_url.String())
+	if err != nil {
+		return SeriesVintageObservationsResponse{}, err.Prefixf("error getting vintages of series %s:", req.Series)
+	}
+
+	// parse the correct format
+	var result seriesVintageObservationsResponse
+	err = c.unmarshal_body(req_url.String(), body, &result)
+	if err != nil {
+		return SeriesVintageObservationsResponse{}, err.Prefixf("could not get vintages of series %s:", req.Series)
+	}
+
+	output := result.Output
+	if output == OutputDefault {
+		output = req.Output
+	}
+	points, parse_err := vintage_points(result.Rows, output)
+	if parse_err != nil {
+		return SeriesVintageObservationsResponse{}, c.request_error(req_url.String(), &APIError{
+			ty:  ParseError,
+			msg: fmt.Sprintf("could not get vintages of series %s: %v", req.Series, parse_err),
+			err: parse_err,
+		})
+	}
+
+	res := result.SeriesVintageObservationsResponse
+	res.Observations = points
+	return res, nil
+}
+
+// The observations of the response as they were published on the given date.
+func (r SeriesVintageObservationsResponse) Snapshot(day time.Time) SeriesSnapshot {
+	return snapshot_of(r.Observations, day)
+}
+
+//==============================================================================
+// point in time
+//==============================================================================
+
+// The observations of a series as they were published on a given date.
+type SeriesSnapshot struct {
+	AsOf         Date
+	Observations []DataPoint
+}
+
+// Picks the points published on the given date, keeping their order.
+func snapshot_of(points []VintageDataPoint, day time.Time) SeriesSnapshot {
+	snapshot := SeriesSnapshot{AsOf: Date(day), Observations: []DataPoint{}}
+	for _, point := range points {
+		if point.KnownOn(day) {
+			snapshot.Observations = append(snapshot.Observations, point.DataPoint)
+		}
+	}
+	return snapshot
+}
+
+// Fetches every realtime period of the series' observations overlapping
+// `start` through `end`, walking every page.
+func (c Client) realtime_periods(ctx context.Context, series string, start, end time.Time) ([]VintageDataPoint, Error) {
+	req := NewSeriesObservationsRequest(series, time.Time{}, time.Time{})
+	req.Output = OutputByRealtimePeriod
+	req.Start, req.End = Date(start), Date(end)
+
+	points := []VintageDataPoint{}
+	for {
+		res, err := c.SeriesVintageObservationsContext(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+
+		// realtime periods are never merged, so each row is one point
+		points = append(points, res.Observations...)
+		req.Offset += uint(len(res.Observations))
+		if len(res.Observations) == 0 || req.Offset >= res.Count {
+			return points, nil
+		}
+	}
+}
+
+// Get the observations of the series exactly as they were published on `as_of`,
+// without any later revisions.
+func (c Client) SeriesAsOf(ctx context.Context, series string, as_of time.Time) (SeriesSnapshot, Error) {
+	if as_of.IsZero() {
+		return SeriesSnapshot{}, c.invalid_request("", "series as of", fmt.Errorf("no as of date given"))
+	}
+
+	points, err := c.realtime_periods(ctx, series, as_of, as_of)
+	if err != nil {
+		return SeriesSnapshot{}, err.Prefixf("could not get series %s as of %s:", series, as_of.Format(DATE_FORMAT))
+	}
+	return snapshot_of(points, as_of), nil
+}
+
+// Get one snapshot of the series for each of its vintage dates from `start`
+// through `end`, oldest first.
+//
+// Rather than one request per vintage, only the vintage dates and the realtime
+// periods of the observations are requested.
+func (c Client) SeriesAsOfRange(ctx context.Context, series string, start, end time.Time) ([]SeriesSnapshot, Error) {
+	if start.IsZero() || end.IsZero() {
+		return nil, c.invalid_request("", "series as of range", fmt.Errorf("both a start and end date must be given"))
+	}
+
+	dates_req := NewSeriesVintageDatesRequest(series)
+	dates_req.Start, dates_req.End = Date(start), Date(end)
+	dates_req.Sort = SortAscending
+	dates_req.Limit = MAX_VINTAGE_DATES_LIMIT
+
+	dates := []Date{}
+	it := c.SeriesVintageDatesAll(ctx, dates_req)
+	for it.Next() {
+		dates = append(dates, it.Date())
+	}
+	if err := it.Err(); err != nil {
+		return nil, err.Prefixf("could not get vintages of series %s:", series)
+	}
+	if len(dates) == 0 {
+		return []SeriesSnapshot{}, nil
+	}
+
+	points, err := c.realtime_periods(ctx, series, start, end)
+	if err != nil {
+		return nil, err.Prefixf("could not get series %s from %s to %s:", series,
+			start.Format(DATE_FORMAT), end.Format(DATE_FORMAT))
+	}
+
+	snapshots := make([]SeriesSnapshot, len(dates))
+	for i, date := range dates {
+		snapshots[i] = snapshot_of(points, time.Time(date))
+	}
+	return snapshots, nil
+}
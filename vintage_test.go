@@ -0,0 +1,212 @@
+package gofred
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func vintage_date(t *testing.T, str string) time.Time {
+	date, err := time.Parse(DATE_FORMAT, str)
+	if err != nil {
+		t.Fatalf("could not create date %s: %v", str, err)
+	}
+	return date
+}
+
+// Serves the given body for both formats, checking the vintage params are sent.
+func new_vintage_test_server(t *testing.T, output string, js, xml string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if q.Get("output_type") != output {
+			t.Errorf("expected output_type=%s, got: %s", output, q.Get("output_type"))
+		}
+		if q.Get("realtime_start") != REALTIME_EARLIEST || q.Get("realtime_end") != REALTIME_LATEST {
+			t.Errorf("expected every vintage to be requested, got: %s to %s",
+				q.Get("realtime_start"), q.Get("realtime_end"))
+		}
+
+		if q.Get("file_type") == "xml" {
+			w.Write([]byte(xml))
+			return
+		}
+		w.Write([]byte(js))
+	}))
+}
+
+func check_vintage_points(t *testing.T, expect, got []VintageDataPoint) {
+	if len(got) != len(expect) {
+		t.Fatalf("expected %d points, got %d: %+v", len(expect), len(got), got)
+	}
+	for i := range expect {
+		if got[i] != expect[i] {
+			t.Errorf("point %d: expected:\n%+v\ngot:\n%+v", i, expect[i], got[i])
+		}
+	}
+}
+
+func TestSeriesVintageObservations_ByVintageAll(t *testing.T) {
+	js := `{"output_type":2,"observations":[
+		{"date":"2000-01-01","GNPCA_20010115":"1.0","GNPCA_20010215":"1.0","GNPCA_20010315":"1.5"},
+		{"date":"2000-02-01","GNPCA_20010115":".","GNPCA_20010215":"2.0","GNPCA_20010315":"2.0"}]}`
+	xml := `<observations output_type="2">
+		<observation date="2000-01-01" GNPCA_20010115="1.0" GNPCA_20010215="1.0" GNPCA_20010315="1.5"/>
+		<observation date="2000-02-01" GNPCA_20010115="." GNPCA_20010215="2.0" GNPCA_20010315="2.0"/>
+	</observations>`
+	server := new_vintage_test_server(t, "2", js, xml)
+	defer server.Close()
+
+	jan, feb := Date(vintage_date(t, "2000-01-01")), Date(vintage_date(t, "2000-02-01"))
+	latest := Date(vintage_date(t, REALTIME_LATEST))
+	expect := []VintageDataPoint{
+		{DataPoint{jan, 1.0, true}, Date(vintage_date(t, "2001-01-15")), Date(vintage_date(t, "2001-03-14"))},
+		{DataPoint{jan, 1.5, true}, Date(vintage_date(t, "2001-03-15")), latest},
+		{DataPoint{feb, 2.0, true}, Date(vintage_date(t, "2001-02-15")), latest},
+	}
+
+	for _, format := range []ResponseFormat{JSON, XML} {
+		client, err := NewClient(API_KEY, format, WithBaseURL(server.URL))
+		if err != nil {
+			t.Fatalf("could not create client: %v", err)
+		}
+
+		res, api_err := client.SeriesVintageObservations(NewSeriesObservationsRequest(SERIES_GNP_ANNUAL, time.Time{}, time.Time{}))
+		if api_err != nil {
+			t.Fatal(api_err)
+		}
+		if res.Output != OutputByVintageAll {
+			t.Errorf("expected output type %d, got: %d", OutputByVintageAll, res.Output)
+		}
+		check_vintage_points(t, expect, res.Observations)
+
+		if !res.Observations[0].KnownOn(vintage_date(t, "2001-02-20")) || res.Observations[1].KnownOn(vintage_date(t, "2001-02-20")) {
+			t.Errorf("expected only the first vintage to be known on 2001-02-20")
+		}
+	}
+}
+
+func TestSeriesVintageObservations_ByVintageNew(t *testing.T) {
+	js := `{"output_type":3,"observations":[
+		{"date":"2000-01-01","GNPCA_20010115":"1.0","GNPCA_20010215":".","GNPCA_20010315":"1.5"}]}`
+	server := new_vintage_test_server(t, "3", js, "")
+	defer server.Close()
+
+	client, err := NewClient(API_KEY, JSON, WithBaseURL(server.URL))
+	if err != nil {
+		t.Fatalf("could not create client: %v", err)
+	}
+
+	req := NewSeriesObservationsRequest(SERIES_GNP_ANNUAL, time.Time{}, time.Time{})
+	req.Output = OutputByVintageNew
+	res, api_err := client.SeriesVintageObservations(req)
+	if api_err != nil {
+		t.Fatal(api_err)
+	}
+
+	// the unchanged vintage does not end the first value
+	jan := Date(vintage_date(t, "2000-01-01"))
+	check_vintage_points(t, []VintageDataPoint{
+		{DataPoint{jan, 1.0, true}, Date(vintage_date(t, "2001-01-15")), Date(vintage_date(t, "2001-03-14"))},
+		{DataPoint{jan, 1.5, true}, Date(vintage_date(t, "2001-03-15")), Date(vintage_date(t, REALTIME_LATEST))},
+	}, res.Observations)
+}
+
+func TestSeriesVintageObservations_ByRealtimePeriod(t *testing.T) {
+	js := `{"output_type":1,"observations":[
+		{"realtime_start":"2001-03-15","realtime_end":"9999-12-31","date":"2000-01-01","value":"1.5"},
+		{"realtime_start":"2001-01-15","realtime_end":"2001-03-14","date":"2000-01-01","value":"."}]}`
+	server := new_vintage_test_server(t, "1", js, "")
+	defer server.Close()
+
+	client, err := NewClient(API_KEY, JSON, WithBaseURL(server.URL))
+	if err != nil {
+		t.Fatalf("could not create client: %v", err)
+	}
+
+	req := NewSeriesObservationsRequest(SERIES_GNP_ANNUAL, time.Time{}, time.Time{})
+	req.Output = OutputByRealtimePeriod
+	res, api_err := client.SeriesVintageObservations(req)
+	if api_err != nil {
+		t.Fatal(api_err)
+	}
+
+	jan := Date(vintage_date(t, "2000-01-01"))
+	check_vintage_points(t, []VintageDataPoint{
+		{DataPoint{jan, 0, false}, Date(vintage_date(t, "2001-01-15")), Date(vintage_date(t, "2001-03-14"))},
+		{DataPoint{jan, 1.5, true}, Date(vintage_date(t, "2001-03-15")), Date(vintage_date(t, REALTIME_LATEST))},
+	}, res.Observations)
+}
+
+// Serves the vintage dates and realtime periods of a single observation revised on 2001-03-15.
+func new_as_of_test_server(t *testing.T) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		switch r.URL.Path {
+		case "/series/vintagedates":
+			w.Write([]byte(`{"count":2,"vintage_dates":["2001-01-15","2001-03-15"]}`))
+		case "/series/observations":
+			if q.Get("output_type") != "1" {
+				t.Errorf("expected realtime periods to be requested, got output_type=%s", q.Get("output_type"))
+			}
+			w.Write([]byte(`{"count":3,"output_type":1,"observations":[
+				{"realtime_start":"2001-01-15","realtime_end":"2001-03-14","date":"2000-01-01","value":"1.0"},
+				{"realtime_start":"2001-03-15","realtime_end":"9999-12-31","date":"2000-01-01","value":"1.5"},
+				{"realtime_start":"2001-03-15","realtime_end":"9999-12-31","date":"2000-02-01","value":"2.0"}]}`))
+		default:
+			t.Errorf("unexpected path: %s", r.URL.Path)
+		}
+	}))
+}
+
+func TestSeriesAsOf(t *testing.T) {
+	server := new_as_of_test_server(t)
+	defer server.Close()
+
+	client, err := NewClient(API_KEY, JSON, WithBaseURL(server.URL))
+	if err != nil {
+		t.Fatalf("could not create client: %v", err)
+	}
+
+	as_of := vintage_date(t, "2001-02-01")
+	snapshot, api_err := client.SeriesAsOf(context.Background(), SERIES_GNP_ANNUAL, as_of)
+	if api_err != nil {
+		t.Fatal(api_err)
+	}
+
+	expect := []DataPoint{{Date(vintage_date(t, "2000-01-01")), 1.0, true}}
+	if time.Time(snapshot.AsOf) != as_of || !reflect.DeepEqual(snapshot.Observations, expect) {
+		t.Errorf("expected snapshot as of %v:\n%+v\ngot:\n%+v", as_of, expect, snapshot)
+	}
+
+	if _, api_err := client.SeriesAsOf(context.Background(), SERIES_GNP_ANNUAL, time.Time{}); api_err == nil {
+		t.Errorf("expected an error without an as of date")
+	}
+}
+
+func TestSeriesAsOfRange(t *testing.T) {
+	server := new_as_of_test_server(t)
+	defer server.Close()
+
+	client, err := NewClient(API_KEY, JSON, WithBaseURL(server.URL))
+	if err != nil {
+		t.Fatalf("could not create client: %v", err)
+	}
+
+	snapshots, api_err := client.SeriesAsOfRange(context.Background(), SERIES_GNP_ANNUAL,
+		vintage_date(t, "2001-01-01"), vintage_date(t, "2001-12-31"))
+	if api_err != nil {
+		t.Fatal(api_err)
+	}
+
+	jan, feb := Date(vintage_date(t, "2000-01-01")), Date(vintage_date(t, "2000-02-01"))
+	expect := []SeriesSnapshot{
+		{Date(vintage_date(t, "2001-01-15")), []DataPoint{{jan, 1.0, true}}},
+		{Date(vintage_date(t, "2001-03-15")), []DataPoint{{jan, 1.5, true}, {feb, 2.0, true}}},
+	}
+	if !reflect.DeepEqual(snapshots, expect) {
+		t.Errorf("expected snapshots:\n%+v\ngot:\n%+v", expect, snapshots)
+	}
+}
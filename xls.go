@@ -0,0 +1,516 @@
+package gofred
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"strconv"
+	"unicode/utf16"
+)
+
+//==============================================================================
+// compound files
+//==============================================================================
+
+// Special sector numbers of a compound file's allocation tables.
+const (
+	cfb_free_sector   = 0xFFFFFFFF
+	cfb_end_of_chain  = 0xFFFFFFFE
+	cfb_header_size   = 512
+	cfb_dir_entry     = 128
+	cfb_difat_entries = 109
+)
+
+// A compound file, the container xls workbooks are stored in.
+type compoundFile struct {
+	data        []byte
+	sector_size int
+	mini_size   int
+	cutoff      uint32
+	fat         []uint32
+	mini_fat    []uint32
+	mini_stream []byte
+	dir         []byte
+}
+
+func open_compound_file(data []byte) (*compoundFile, error) {
+	if len(data) < cfb_header_size {
+		return nil, fmt.Errorf("compound file header is truncated")
+	}
+
+	le := binary.LittleEndian
+	f := &compoundFile{
+		data:        data,
+		sector_size: 1 << le.Uint16(data[0x1E:]),
+		mini_size:   1 << le.Uint16(data[0x20:]),
+		cutoff:      le.Uint32(data[0x38:]),
+	}
+	if f.sector_size != 512 && f.sector_size != 4096 {
+		return nil, fmt.Errorf("invalid sector size %d", f.sector_size)
+	}
+	if f.mini_size != 64 {
+		return nil, fmt.Errorf("invalid mini sector size %d", f.mini_size)
+	}
+
+	// the allocation table can't have more sectors than the file, the first of
+	// which holds the header
+	sectors := len(data)/f.sector_size - 1
+	fat_count := int(le.Uint32(data[0x2C:]))
+	if fat_count > sectors {
+		return nil, fmt.Errorf("allocation table has %d sectors, the file only %d", fat_count, sectors)
+	}
+
+	// the allocation table's sectors are listed in the header, then in a chain of
+	// sectors each ending with the next one
+	fat_sectors := []uint32{}
+	for i := 0; i < cfb_difat_entries; i++ {
+		fat_sectors = append(fat_sectors, le.Uint32(data[0x4C+4*i:]))
+	}
+	next := le.Uint32(data[0x44:])
+	seen := map[uint32]bool{}
+	for count := le.Uint32(data[0x48:]); count > 0 && len(fat_sectors) < fat_count && next != cfb_end_of_chain && next != cfb_free_sector; count-- {
+		if seen[next] || len(seen) >= sectors {
+			return nil, fmt.Errorf("broken allocation table list at sector %d", next)
+		}
+		seen[next] = true
+
+		sector, err := f.sector(next)
+		if err != nil {
+			return nil, err
+		}
+		per_sector := f.sector_size/4 - 1
+		for i := 0; i < per_sector; i++ {
+			fat_sectors = append(fat_sectors, le.Uint32(sector[4*i:]))
+		}
+		next = le.Uint32(sector[4*per_sector:])
+	}
+
+	if fat_count > len(fat_sectors) {
+		return nil, fmt.Errorf("allocation table has %d sectors, only %d are listed", fat_count, len(fat_sectors))
+	}
+	for _, n := range fat_sectors[:fat_count] {
+		sector, err := f.sector(n)
+		if err != nil {
+			return nil, err
+		}
+		f.fat = append(f.fat, read_uint32s(sector)...)
+	}
+
+	var err error
+	if f.dir, err = f.chain(f.fat, le.Uint32(data[0x30:]), f.sector); err != nil {
+		return nil, fmt.Errorf("could not read directory: %v", err)
+	}
+	if le.Uint32(data[0x40:]) > 0 {
+		raw, err := f.chain(f.fat, le.Uint32(data[0x3C:]), f.sector)
+		if err != nil {
+			return nil, fmt.Errorf("could not read mini allocation table: %v", err)
+		}
+		f.mini_fat = read_uint32s(raw)
+	}
+
+	// the root entry's stream holds the streams smaller than the cutoff
+	if len(f.dir) >= cfb_dir_entry {
+		start, size := f.entry_stream(0)
+		if f.mini_stream, err = f.chain(f.fat, start, f.sector); err != nil {
+			return nil, fmt.Errorf("could not read mini stream: %v", err)
+		}
+		if uint64(len(f.mini_stream)) > size {
+			f.mini_stream = f.mini_stream[:size]
+		}
+	}
+	return f, nil
+}
+
+func read_uint32s(raw []byte) []uint32 {
+	values := make([]uint32, len(raw)/4)
+	for i := range values {
+		values[i] = binary.LittleEndian.Uint32(raw[4*i:])
+	}
+	return values
+}
+
+func (f *compoundFile) sector(n uint32) ([]byte, error) {
+	start := (int(n) + 1) * f.sector_size
+	if n >= cfb_end_of_chain-1 || start+f.sector_size > len(f.data) {
+		return nil, fmt.Errorf("sector %d is out of bounds", n)
+	}
+	return f.data[start : start+f.sector_size], nil
+}
+
+func (f *compoundFile) mini_sector(n uint32) ([]byte, error) {
+	start := int(n) * f.mini_size
+	if start+f.mini_size > len(f.mini_stream) {
+		return nil, fmt.Errorf("mini sector %d is out of bounds", n)
+	}
+	return f.mini_stream[start : start+f.mini_size], nil
+}
+
+// Concatenates the sectors of the chain starting at `start`.
+func (f *compoundFile) chain(table []uint32, start uint32, read func(uint32) ([]byte, error)) ([]byte, error) {
+	result := []byte{}
+	for n, steps := start, 0; n != cfb_end_of_chain; steps++ {
+		if int(n) >= len(table) || steps > len(table) {
+			return nil, fmt.Errorf("broken sector chain at %d", n)
+		}
+		sector, err := read(n)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, sector...)
+		n = table[n]
+	}
+	return result, nil
+}
+
+// Start sector and size of the directory entry's stream.
+func (f *compoundFile) entry_stream(i int) (uint32, uint64) {
+	entry := f.dir[i*cfb_dir_entry:]
+	size := binary.LittleEndian.Uint64(entry[0x78:])
+	if f.sector_size == 512 {
+		size &= 0xFFFFFFFF // the high half is undefined in version 3 files
+	}
+	return binary.LittleEndian.Uint32(entry[0x74:]), size
+}
+
+// Contents of the first stream with one of the given names.
+func (f *compoundFile) stream(names ...string) ([]byte, error) {
+	for i := 0; (i+1)*cfb_dir_entry <= len(f.dir); i++ {
+		entry := f.dir[i*cfb_dir_entry:]
+		name_len := int(binary.LittleEndian.Uint16(entry[0x40:]))
+		if entry[0x42] != 2 || name_len < 2 || name_len > 64 {
+			continue // not a stream
+		}
+
+		units := make([]uint16, name_len/2-1) // without the terminating null
+		for j := range units {
+			units[j] = binary.LittleEndian.Uint16(entry[2*j:])
+		}
+		name := string(utf16.Decode(units))
+		for _, want := range names {
+			if name != want {
+				continue
+			}
+
+			start, size := f.entry_stream(i)
+			var data []byte
+			var err error
+			if size < uint64(f.cutoff) {
+				data, err = f.chain(f.mini_fat, start, f.mini_sector)
+			} else {
+				data, err = f.chain(f.fat, start, f.sector)
+			}
+			if err != nil {
+				return nil, fmt.Errorf("could not read stream %s: %v", name, err)
+			}
+			if uint64(len(data)) < size {
+				return nil, fmt.Errorf("stream %s is truncated", name)
+			}
+			return data[:size], nil
+		}
+	}
+	return nil, fmt.Errorf("no %v stream", names)
+}
+
+//==============================================================================
+// xls
+//==============================================================================
+
+// BIFF8 record types read from workbooks, every other record is skipped.
+const (
+	biff_bof      = 0x0809
+	biff_eof      = 0x000A
+	biff_sst      = 0x00FC
+	biff_continue = 0x003C
+	biff_labelsst = 0x00FD
+	biff_label    = 0x0204
+	biff_number   = 0x0203
+	biff_rk       = 0x027E
+	biff_mulrk    = 0x00BD
+	biff_formula  = 0x0006
+	biff_string   = 0x0207
+	biff_boolerr  = 0x0205
+
+	biff_worksheet = 0x0010 // BOF type of a worksheet's substream
+)
+
+// Reads every worksheet of a BIFF8 (Excel 97 to 2003) workbook, in order.
+func parse_xls(data []byte) ([]sheet, error) {
+	file, err := open_compound_file(data)
+	if err != nil {
+		return nil, err
+	}
+	stream, err := file.stream("Workbook", "Book")
+	if err != nil {
+		return nil, err
+	}
+
+	le := binary.LittleEndian
+	sheets := []sheet{}
+	var shared []string
+	var current *sheet
+	formula_row, formula_col := -1, -1 // awaiting the STRING record of a formula
+
+	for pos := 0; pos+4 <= len(stream); {
+		kind, size := le.Uint16(stream[pos:]), int(le.Uint16(stream[pos+2:]))
+		pos += 4
+		if pos+size > len(stream) {
+			return nil, fmt.Errorf("record %#04x is truncated", kind)
+		}
+		rec := stream[pos : pos+size]
+		pos += size
+
+		// cells are at least a row, column and format
+		if current != nil && size >= 6 {
+			row, col := int(le.Uint16(rec)), int(le.Uint16(rec[2:]))
+			switch kind {
+			case biff_labelsst:
+				if size < 10 || int(le.Uint32(rec[6:])) >= len(shared) {
+					return nil, fmt.Errorf("invalid shared string in cell %d,%d", row, col)
+				}
+				current.set(row, col, shared[le.Uint32(rec[6:])])
+			case biff_label:
+				text, err := read_biff_string(rec[6:])
+				if err != nil {
+					return nil, fmt.Errorf("cell %d,%d: %v", row, col, err)
+				}
+				current.set(row, col, text)
+			case biff_number:
+				if size >= 14 {
+					current.set(row, col, format_number(math.Float64frombits(le.Uint64(rec[6:]))))
+				}
+			case biff_rk:
+				if size >= 10 {
+					current.set(row, col, format_number(rk_number(le.Uint32(rec[6:]))))
+				}
+			case biff_mulrk:
+				for i := 0; 4+6*i+6 <= size-2; i++ {
+					current.set(row, col+i, format_number(rk_number(le.Uint32(rec[4+6*i+2:]))))
+				}
+			case biff_formula:
+				if size < 14 {
+					break
+				}
+				result := rec[6:14]
+				if le.Uint16(result[6:]) != 0xFFFF {
+					current.set(row, col, format_number(math.Float64frombits(le.Uint64(result))))
+					break
+				}
+				switch result[0] {
+				case 0:
+					formula_row, formula_col = row, col
+				case 1:
+					current.set(row, col, strconv.FormatBool(result[2] != 0))
+				case 2:
+					current.set(row, col, biff_error(result[2]))
+				}
+			case biff_boolerr:
+				if size < 8 {
+					break
+				}
+				if rec[7] != 0 {
+					current.set(row, col, biff_error(rec[6]))
+				} else {
+					current.set(row, col, strconv.FormatBool(rec[6] != 0))
+				}
+			}
+		}
+
+		switch kind {
+		case biff_bof:
+			if size >= 4 && le.Uint16(rec[2:]) == biff_worksheet {
+				sheets = append(sheets, sheet{})
+				current = &sheets[len(sheets)-1]
+			}
+		case biff_eof:
+			current = nil
+		case biff_sst:
+			segments := [][]byte{rec}
+			for pos+4 <= len(stream) && le.Uint16(stream[pos:]) == biff_continue {
+				next := int(le.Uint16(stream[pos+2:]))
+				if pos+4+next > len(stream) {
+					return nil, fmt.Errorf("continued shared strings are truncated")
+				}
+				segments = append(segments, stream[pos+4:pos+4+next])
+				pos += 4 + next
+			}
+			if shared, err = parse_sst(segments); err != nil {
+				return nil, fmt.Errorf("could not read shared strings: %v", err)
+			}
+		case biff_string:
+			if current != nil && formula_row >= 0 {
+				text, err := read_biff_string(rec)
+				if err != nil {
+					return nil, fmt.Errorf("cell %d,%d: %v", formula_row, formula_col, err)
+				}
+				current.set(formula_row, formula_col, text)
+				formula_row, formula_col = -1, -1
+			}
+		}
+	}
+	return sheets, nil
+}
+
+func format_number(value float64) string {
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
+
+// Decodes an RK number, a float or integer squeezed into 30 bits, possibly
+// multiplied by 100.
+func rk_number(rk uint32) float64 {
+	var value float64
+	if rk&0x02 != 0 {
+		value = float64(int32(rk) >> 2)
+	} else {
+		value = math.Float64frombits(uint64(rk&0xFFFFFFFC) << 32)
+	}
+	if rk&0x01 != 0 {
+		value /= 100
+	}
+	return value
+}
+
+func biff_error(code byte) string {
+	if code == 0x2A {
+		return "#N/A"
+	}
+	return "#ERROR"
+}
+
+// Decodes a string with a 16 bit length and flags, as in LABEL and STRING records.
+func read_biff_string(data []byte) (string, error) {
+	if len(data) < 3 {
+		return "", fmt.Errorf("string is truncated")
+	}
+	r := &sstReader{segments: [][]byte{data[3:]}}
+	return r.chars(int(binary.LittleEndian.Uint16(data)), data[2]&0x01 != 0)
+}
+
+// Reads the shared strings table, whose strings may be split across CONTINUE
+// records.
+func parse_sst(segments [][]byte) ([]string, error) {
+	r := &sstReader{segments: segments}
+	header, err := r.bytes(8)
+	if err != nil {
+		return nil, err
+	}
+
+	// the count is not trusted with an allocation, a corrupt one runs out of strings
+	count := binary.LittleEndian.Uint32(header[4:])
+	result := []string{}
+	for i := uint32(0); i < count; i++ {
+		header, err := r.bytes(3)
+		if err != nil {
+			return nil, err
+		}
+		length, flags := int(binary.LittleEndian.Uint16(header)), header[2]
+
+		runs, extended := 0, 0
+		if flags&0x08 != 0 {
+			raw, err := r.bytes(2)
+			if err != nil {
+				return nil, err
+			}
+			runs = int(binary.LittleEndian.Uint16(raw))
+		}
+		if flags&0x04 != 0 {
+			raw, err := r.bytes(4)
+			if err != nil {
+				return nil, err
+			}
+			extended = int(binary.LittleEndian.Uint32(raw))
+		}
+
+		text, err := r.chars(length, flags&0x01 != 0)
+		if err != nil {
+			return nil, err
+		}
+		if err := r.skip(4*runs + extended); err != nil {
+			return nil, err
+		}
+		result = append(result, text)
+	}
+	return result, nil
+}
+
+// Reads across the segments of a record and its CONTINUE records.
+type sstReader struct {
+	segments [][]byte
+	seg, pos int
+}
+
+// Whether the current segment is used up, moving to the next one if so.
+func (r *sstReader) next_segment() bool {
+	if r.pos < len(r.segments[r.seg]) {
+		return false
+	}
+	if r.seg+1 >= len(r.segments) {
+		return false
+	}
+	r.seg, r.pos = r.seg+1, 0
+	return true
+}
+
+func (r *sstReader) bytes(n int) ([]byte, error) {
+	result := make([]byte, 0, n)
+	err := r.read(n, func(data []byte) {
+		result = append(result, data...)
+	})
+	return result, err
+}
+
+// Skips `n` bytes, e.g. the formatting of a string, without holding them.
+func (r *sstReader) skip(n int) error {
+	return r.read(n, func([]byte) {})
+}
+
+// Hands the next `n` bytes to `fn`, one segment at a time.
+func (r *sstReader) read(n int, fn func([]byte)) error {
+	for n > 0 {
+		r.next_segment()
+		segment := r.segments[r.seg]
+		if r.pos >= len(segment) {
+			return fmt.Errorf("unexpected end of strings")
+		}
+
+		take := len(segment) - r.pos
+		if take > n {
+			take = n
+		}
+		fn(segment[r.pos : r.pos+take])
+		r.pos += take
+		n -= take
+	}
+	return nil
+}
+
+// Reads `n` characters, one or two bytes each. Characters continued in the next
+// segment are preceded by a new flags byte, which may change their width.
+func (r *sstReader) chars(n int, wide bool) (string, error) {
+	units := make([]uint16, 0, n)
+	for len(units) < n {
+		if r.next_segment() {
+			if len(r.segments[r.seg]) == 0 {
+				return "", fmt.Errorf("unexpected end of strings")
+			}
+			wide = r.segments[r.seg][0]&0x01 != 0
+			r.pos = 1
+		}
+		segment := r.segments[r.seg]
+
+		width := 1
+		if wide {
+			width = 2
+		}
+		if r.pos+width > len(segment) {
+			return "", fmt.Errorf("unexpected end of strings")
+		}
+		for ; len(units) < n && r.pos+width <= len(segment); r.pos += width {
+			if wide {
+				units = append(units, binary.LittleEndian.Uint16(segment[r.pos:]))
+			} else {
+				units = append(units, uint16(segment[r.pos]))
+			}
+		}
+	}
+	return string(utf16.Decode(units)), nil
+}
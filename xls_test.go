@@ -0,0 +1,272 @@
+package gofred
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"strings"
+	"testing"
+	"unicode/utf16"
+)
+
+func biff_record(kind uint16, data ...[]byte) []byte {
+	body := bytes.Join(data, nil)
+	rec := make([]byte, 4, 4+len(body))
+	binary.LittleEndian.PutUint16(rec, kind)
+	binary.LittleEndian.PutUint16(rec[2:], uint16(len(body)))
+	return append(rec, body...)
+}
+
+func le_bytes(values ...interface{}) []byte {
+	var buf bytes.Buffer
+	for _, v := range values {
+		binary.Write(&buf, binary.LittleEndian, v)
+	}
+	return buf.Bytes()
+}
+
+// A cell's row, column and format.
+func biff_cell(row, col uint16) []byte {
+	return le_bytes(row, col, uint16(0))
+}
+
+// An RK number holding an integer.
+func biff_rk_int(value int32) uint32 {
+	return uint32(value)<<2 | 0x02
+}
+
+// An RK number holding the value times 100.
+func biff_rk_cents(value float64) uint32 {
+	return uint32(int32(math.Round(value*100)))<<2 | 0x03
+}
+
+// A workbook with a readme sheet, then observations using every kind of cell.
+//
+// The shared strings are split in the middle of the series name, which is
+// continued as 16 bit characters. The stream is padded to `size`.
+func xls_workbook(size int) []byte {
+	sst := le_bytes(uint32(3), uint32(3),
+		uint16(16), uint8(0), []byte("observation_date"),
+		uint16(5), uint8(0), []byte("GN"))
+	sst_continued := le_bytes(uint8(1), utf16.Encode([]rune("PCA")),
+		uint16(4), uint8(0), []byte("Note"))
+
+	stream := bytes.Join([][]byte{
+		biff_record(biff_bof, le_bytes(uint16(0x0600), uint16(0x0005))),
+		biff_record(biff_sst, sst),
+		biff_record(biff_continue, sst_continued),
+		biff_record(biff_eof),
+
+		biff_record(biff_bof, le_bytes(uint16(0x0600), uint16(biff_worksheet))),
+		biff_record(biff_labelsst, biff_cell(0, 0), le_bytes(uint32(2))),
+		biff_record(biff_eof),
+
+		biff_record(biff_bof, le_bytes(uint16(0x0600), uint16(biff_worksheet))),
+		biff_record(biff_label, biff_cell(0, 0), le_bytes(uint16(5), uint8(0), []byte("Title"))),
+		biff_record(biff_labelsst, biff_cell(2, 0), le_bytes(uint32(0))),
+		biff_record(biff_labelsst, biff_cell(2, 1), le_bytes(uint32(1))),
+		biff_record(biff_number, biff_cell(3, 0), le_bytes(float64(10594))),
+		biff_record(biff_number, biff_cell(3, 1), le_bytes(1120.718)),
+		biff_record(biff_mulrk, le_bytes(uint16(4), uint16(0)),
+			le_bytes(uint16(0), biff_rk_int(10959), uint16(0), biff_rk_cents(1025.68)), le_bytes(uint16(1))),
+		biff_record(biff_rk, biff_cell(5, 0), le_bytes(biff_rk_int(11324))),
+		biff_record(biff_boolerr, biff_cell(5, 1), le_bytes(uint8(0x2A), uint8(1))),
+		biff_record(biff_label, biff_cell(6, 0), le_bytes(uint16(10), uint8(1), utf16.Encode([]rune("1932-01-01")))),
+		biff_record(biff_formula, biff_cell(6, 1), le_bytes(uint8(0), []byte{0, 0, 0, 0, 0}, uint16(0xFFFF)), make([]byte, 6)),
+		biff_record(biff_string, le_bytes(uint16(7), uint8(0), []byte("812.866"))),
+	}, nil)
+
+	// unknown records are skipped
+	for len(stream)+4+4 < size {
+		pad := size - len(stream) - 8
+		if pad > 1024 {
+			pad = 1024
+		}
+		stream = append(stream, biff_record(0x00EB, make([]byte, pad))...)
+	}
+	return append(stream, biff_record(biff_eof)...)
+}
+
+// Stores the stream as `Workbook` in a compound file with 512 byte sectors,
+// in the mini stream if it is under the cutoff.
+func compound_file(stream []byte) []byte {
+	const sector = 512
+	le := binary.LittleEndian
+	pad := func(data []byte, to int) []byte {
+		for len(data)%to != 0 {
+			data = append(data, 0)
+		}
+		return data
+	}
+
+	// sector 0 is the allocation table, 1 the directory, then any mini allocation
+	// table and the data
+	fat := make([]uint32, sector/4)
+	for i := range fat {
+		fat[i] = cfb_free_sector
+	}
+	fat[0], fat[1] = 0xFFFFFFFD, cfb_end_of_chain
+
+	mini := len(stream) < 4096
+	data := pad(append([]byte{}, stream...), sector)
+	first := uint32(2)
+	var mini_fat []byte
+	if mini {
+		table := make([]uint32, sector/4)
+		count := (len(stream) + 63) / 64
+		for i := range table {
+			switch {
+			case i < count-1:
+				table[i] = uint32(i + 1)
+			case i == count-1:
+				table[i] = cfb_end_of_chain
+			default:
+				table[i] = cfb_free_sector
+			}
+		}
+		mini_fat = le_bytes(table)
+		fat[2] = cfb_end_of_chain
+		first = 3
+	}
+	for i := 0; i < len(data)/sector; i++ {
+		fat[int(first)+i] = first + uint32(i) + 1
+	}
+	fat[int(first)+len(data)/sector-1] = cfb_end_of_chain
+
+	entry := func(name string, kind uint8, start uint32, size int) []byte {
+		e := make([]byte, cfb_dir_entry)
+		units := utf16.Encode([]rune(name))
+		for i, u := range units {
+			le.PutUint16(e[2*i:], u)
+		}
+		le.PutUint16(e[0x40:], uint16(2*len(units)+2))
+		e[0x42] = kind
+		le.PutUint32(e[0x44:], cfb_free_sector) // siblings and child
+		le.PutUint32(e[0x48:], cfb_free_sector)
+		le.PutUint32(e[0x4C:], cfb_free_sector)
+		le.PutUint32(e[0x74:], start)
+		le.PutUint32(e[0x78:], uint32(size))
+		return e
+	}
+	var dir []byte
+	if mini {
+		dir = append(entry("Root Entry", 5, first, len(pad(append([]byte{}, stream...), 64))), entry("Workbook", 2, 0, len(stream))...)
+	} else {
+		dir = append(entry("Root Entry", 5, cfb_end_of_chain, 0), entry("Workbook", 2, first, len(stream))...)
+	}
+	le.PutUint32(dir[0x4C:], 1) // the workbook is the root's child
+	dir = pad(append(dir, make([]byte, 2*cfb_dir_entry)...), sector)
+
+	header := make([]byte, cfb_header_size)
+	copy(header, cfb_signature)
+	le.PutUint16(header[0x18:], 0x3E)
+	le.PutUint16(header[0x1A:], 3)
+	le.PutUint16(header[0x1C:], 0xFFFE)
+	le.PutUint16(header[0x1E:], 9)
+	le.PutUint16(header[0x20:], 6)
+	le.PutUint32(header[0x2C:], 1)
+	le.PutUint32(header[0x30:], 1)
+	le.PutUint32(header[0x38:], 4096)
+	le.PutUint32(header[0x3C:], cfb_end_of_chain)
+	if mini {
+		le.PutUint32(header[0x3C:], 2)
+		le.PutUint32(header[0x40:], 1)
+	}
+	le.PutUint32(header[0x44:], cfb_end_of_chain)
+	for i := 0; i < cfb_difat_entries; i++ {
+		le.PutUint32(header[0x4C+4*i:], cfb_free_sector)
+	}
+	le.PutUint32(header[0x4C:], 0)
+
+	return bytes.Join([][]byte{header, le_bytes(fat), dir, mini_fat, data}, nil)
+}
+
+func TestDownloadRows_XLS(t *testing.T) {
+	expect := gnpca_points(t)
+	expect[1].Value = 1025.68 // RK numbers only keep the cents
+
+	for _, size := range []int{0, 10000} {
+		body := compound_file(xls_workbook(size))
+		sheets, err := download_sheets(body)
+		if err != nil {
+			t.Fatalf("%d byte workbook: %v", size, err)
+		}
+		if len(sheets) != 2 || sheets[0][0][0] != "Note" || sheets[1][0][0] != "Title" {
+			t.Fatalf("%d byte workbook: expected the readme then the observations, got: %v", size, sheets)
+		}
+
+		rows, err := download_rows(body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		points := make([]DataPoint, len(rows))
+		for i, row := range rows {
+			if points[i], err = parse_data_point(row.date, row.value); err != nil {
+				t.Fatal(err)
+			}
+		}
+		check_data_points(t, expect, points)
+	}
+}
+
+func TestRKNumber(t *testing.T) {
+	tests := []struct {
+		rk     uint32
+		expect float64
+	}{
+		{biff_rk_int(42), 42},
+		{biff_rk_int(-7), -7},
+		{biff_rk_cents(123.45), 123.45},
+		{uint32(math.Float64bits(1.5) >> 32), 1.5},
+		{uint32(math.Float64bits(25)>>32) | 0x01, 0.25},
+	}
+	for _, test := range tests {
+		if got := rk_number(test.rk); got != test.expect {
+			t.Errorf("%#08x: expected %v, got: %v", test.rk, test.expect, got)
+		}
+	}
+}
+
+func TestParseSST_Corrupt(t *testing.T) {
+	tests := map[string][][]byte{
+		"huge count": {le_bytes(uint32(1), uint32(0xFFFFFFFF), uint16(2), uint8(0), []byte("ok"))},
+		"empty continue": {
+			le_bytes(uint32(1), uint32(1), uint16(5), uint8(0), []byte("GN")),
+			{},
+		},
+		"huge extended data": {le_bytes(uint32(1), uint32(1), uint16(2), uint8(0x04), uint32(0xFFFFFFFF), []byte("ok"))},
+	}
+	for desc, segments := range tests {
+		if _, err := parse_sst(segments); err == nil || err.Error() != "unexpected end of strings" {
+			t.Errorf("%s: expected the strings to run out, got: %v", desc, err)
+		}
+	}
+}
+
+func TestOpenCompoundFile_MiniSectorSize(t *testing.T) {
+	for _, shift := range []uint16{5, 7, 64, 0xFFFF} {
+		file := compound_file(xls_workbook(0))
+		binary.LittleEndian.PutUint16(file[0x20:], shift)
+		if _, err := open_compound_file(file); err == nil {
+			t.Errorf("expected a mini sector shift of %d to be rejected", shift)
+		}
+	}
+}
+
+func TestOpenCompoundFile_DIFATLoop(t *testing.T) {
+	// the list of allocation table sectors continues in a sector pointing back to itself
+	file := compound_file(xls_workbook(0))
+	file = append(file, make([]byte, 300*512)...)
+	binary.LittleEndian.PutUint32(file[0x44:], 0)
+	binary.LittleEndian.PutUint32(file[0x48:], 0xFFFFFFFF)
+	binary.LittleEndian.PutUint32(file[512+508:], 0)
+	binary.LittleEndian.PutUint32(file[0x2C:], 300)
+	if _, err := open_compound_file(file); err == nil || !strings.Contains(err.Error(), "broken allocation table list") {
+		t.Errorf("expected the loop to be detected, got: %v", err)
+	}
+
+	binary.LittleEndian.PutUint32(file[0x2C:], 0xFFFFFFFF)
+	if _, err := open_compound_file(file); err == nil {
+		t.Errorf("expected more allocation table sectors than the file has to be rejected")
+	}
+}
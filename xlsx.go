@@ -0,0 +1,169 @@
+package gofred
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"fmt"
+	"io/ioutil"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+//==============================================================================
+// xlsx
+//==============================================================================
+
+// Text of a shared or inline string, either whole or in rich text runs.
+type xlsxText struct {
+	Text string `xml:"t"`
+	Runs []struct {
+		Text string `xml:"t"`
+	} `xml:"r"`
+}
+
+func (t xlsxText) String() string {
+	text := t.Text
+	for _, run := range t.Runs {
+		text += run.Text
+	}
+	return text
+}
+
+type xlsxSharedStrings struct {
+	Items []xlsxText `xml:"si"`
+}
+
+type xlsxCell struct {
+	Ref    string   `xml:"r,attr"`
+	Type   string   `xml:"t,attr"`
+	Value  string   `xml:"v"`
+	Inline xlsxText `xml:"is"`
+}
+
+type xlsxWorksheet struct {
+	Rows []struct {
+		Ref   int        `xml:"r,attr"` // one-based, 0 for the row after the previous
+		Cells []xlsxCell `xml:"c"`
+	} `xml:"sheetData>row"`
+}
+
+// Size of the largest worksheet Excel allows.
+const (
+	xlsx_max_rows    = 1048576
+	xlsx_max_columns = 16384
+)
+
+// Reads every worksheet of the workbook, in order.
+func parse_xlsx(archive *zip.Reader) ([]sheet, error) {
+	var shared xlsxSharedStrings
+	worksheets := []*zip.File{}
+	for _, file := range archive.File {
+		switch {
+		case file.Name == "xl/sharedStrings.xml":
+			if err := read_xlsx_part(file, &shared); err != nil {
+				return nil, err
+			}
+		case strings.HasPrefix(file.Name, "xl/worksheets/") && strings.HasSuffix(file.Name, ".xml"):
+			worksheets = append(worksheets, file)
+		}
+	}
+
+	// sheet2.xml before sheet10.xml
+	sort.Slice(worksheets, func(i, j int) bool {
+		a, b := worksheets[i].Name, worksheets[j].Name
+		if len(a) != len(b) {
+			return len(a) < len(b)
+		}
+		return a < b
+	})
+
+	sheets := []sheet{}
+	for _, file := range worksheets {
+		var worksheet xlsxWorksheet
+		if err := read_xlsx_part(file, &worksheet); err != nil {
+			return nil, err
+		}
+
+		var s sheet
+		row := -1
+		for _, cells := range worksheet.Rows {
+			row++
+			if cells.Ref != 0 {
+				row = cells.Ref - 1
+			}
+			if row < 0 || row >= xlsx_max_rows {
+				return nil, fmt.Errorf("%s: invalid row %d", file.Name, cells.Ref)
+			}
+
+			for col, cell := range cells.Cells {
+				if cell.Ref != "" {
+					col = xlsx_column(cell.Ref)
+				}
+				if col < 0 || col >= xlsx_max_columns {
+					return nil, fmt.Errorf("%s: invalid cell reference '%s'", file.Name, cell.Ref)
+				}
+				value, err := cell.text(shared)
+				if err != nil {
+					return nil, fmt.Errorf("%s, cell %s: %v", file.Name, cell.Ref, err)
+				}
+				s.set(row, col, value)
+			}
+		}
+		sheets = append(sheets, s)
+	}
+	return sheets, nil
+}
+
+func read_xlsx_part(file *zip.File, into interface{}) error {
+	r, err := file.Open()
+	if err != nil {
+		return fmt.Errorf("could not open %s: %v", file.Name, err)
+	}
+	defer r.Close()
+
+	body, err := ioutil.ReadAll(r)
+	if err != nil {
+		return fmt.Errorf("could not read %s: %v", file.Name, err)
+	}
+	if err := xml.Unmarshal(body, into); err != nil {
+		return fmt.Errorf("could not parse %s: %v", file.Name, err)
+	}
+	return nil
+}
+
+// Zero-based column of a cell reference, e.g. 27 for `AB12`, -1 if it has no
+// column. Columns past the largest Excel allows are all `xlsx_max_columns`.
+func xlsx_column(ref string) int {
+	col := 0
+	for _, c := range strings.ToUpper(ref) {
+		if c < 'A' || c > 'Z' {
+			break
+		}
+		col = col*26 + int(c-'A') + 1
+		if col > xlsx_max_columns {
+			return xlsx_max_columns
+		}
+	}
+	return col - 1
+}
+
+// The cell's value as text, errors such as `#N/A` included.
+func (c xlsxCell) text(shared xlsxSharedStrings) (string, error) {
+	switch c.Type {
+	case "s":
+		i, err := strconv.Atoi(c.Value)
+		if err != nil || i < 0 || i >= len(shared.Items) {
+			return "", fmt.Errorf("invalid shared string '%s'", c.Value)
+		}
+		return shared.Items[i].String(), nil
+	case "inlineStr":
+		return c.Inline.String(), nil
+	case "b":
+		if c.Value == "1" {
+			return "TRUE", nil
+		}
+		return "FALSE", nil
+	}
+	return c.Value, nil
+}
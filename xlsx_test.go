@@ -0,0 +1,100 @@
+package gofred
+
+import (
+	"testing"
+)
+
+const XLSX_SHARED_STRINGS = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<sst xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" count="4" uniqueCount="4">
+<si><t>observation_date</t></si>
+<si><t>GNPCA</t></si>
+<si><r><t>Real Gross</t></r><r><t xml:space="preserve"> National Product</t></r></si>
+<si><t>Source: U.S. Bureau of Economic Analysis</t></si>
+</sst>`
+
+// The readme sheet comes second in the archive but first in the workbook.
+const XLSX_README = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>
+<row r="1"><c r="A1" t="s"><v>2</v></c></row>
+<row r="2"><c r="A2" t="inlineStr"><is><t>Date Range: 1929-01-01 to 1932-01-01</t></is></c></row>
+</sheetData></worksheet>`
+
+// Dates as serials, a missing value as an error and a gap in the columns.
+const XLSX_OBSERVATIONS = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>
+<row r="1"><c r="A1" t="s"><v>2</v></c></row>
+<row r="3"><c r="A3" t="s"><v>0</v></c><c r="C3" t="s"><v>1</v></c></row>
+<row r="4"><c r="A4" s="1"><v>10594</v></c><c r="C4"><v>1120.718</v></c></row>
+<row r="5"><c r="A5" s="1"><v>10959</v></c><c r="C5"><v>1025.678</v></c></row>
+<row r="6"><c r="A6" s="1"><v>11324</v></c><c r="C6" t="e"><v>#N/A</v></c></row>
+<row r="7"><c r="A7" t="str"><v>1932-01-01</v></c><c r="C7" t="str"><v>812.866</v></c></row>
+<row r="9"><c r="A9" t="s"><v>3</v></c></row>
+</sheetData></worksheet>`
+
+func TestDownloadRows_XLSX(t *testing.T) {
+	body := zip_files(t,
+		"[Content_Types].xml", `<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types"/>`,
+		"xl/workbook.xml", `<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"/>`,
+		"xl/sharedStrings.xml", XLSX_SHARED_STRINGS,
+		"xl/worksheets/sheet2.xml", XLSX_OBSERVATIONS,
+		"xl/worksheets/sheet1.xml", XLSX_README)
+
+	sheets, err := download_sheets(body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sheets) != 2 || sheets[0][0][0] != "Real Gross National Product" {
+		t.Fatalf("expected the readme then the observations, got: %v", sheets)
+	}
+
+	rows, err := download_rows(body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	points := make([]DataPoint, len(rows))
+	for i, row := range rows {
+		if points[i], err = parse_data_point(row.date, row.value); err != nil {
+			t.Fatal(err)
+		}
+	}
+	check_data_points(t, gnpca_points(t), points)
+}
+
+func TestDownloadRows_XLSXInvalid(t *testing.T) {
+	sheet := func(rows string) []byte {
+		return zip_files(t, "xl/workbook.xml", `<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"/>`,
+			"xl/worksheets/sheet1.xml", `<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`+
+				rows+`</sheetData></worksheet>`)
+	}
+
+	invalid := map[string]string{
+		"no column":        `<row r="1"><c r="1" t="str"><v>x</v></c></row>`,
+		"too many columns": `<row r="1"><c r="ZZZZZZZZZZZZZZZZ1" t="str"><v>x</v></c></row>`,
+		"too many rows":    `<row r="1048577"><c t="str"><v>x</v></c></row>`,
+		"negative row":     `<row r="-1"><c t="str"><v>x</v></c></row>`,
+	}
+	for desc, rows := range invalid {
+		if sheets, err := download_sheets(sheet(rows)); err == nil {
+			t.Errorf("%s: expected an error, got: %v", desc, sheets)
+		}
+	}
+
+	// rows are placed by their number, those without one after the previous
+	sheets, err := download_sheets(sheet(`<row r="2"><c t="str"><v>a</v></c></row><row r="5"><c t="str"><v>b</v></c></row>` +
+		`<row><c t="str"><v>c</v></c></row>`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := sheets[0]; len(s) != 6 || len(s[0]) != 0 || s[1][0] != "a" || s[4][0] != "b" || s[5][0] != "c" {
+		t.Errorf("expected rows 2, 5 and 6, got: %q", s)
+	}
+}
+
+func TestXLSXColumn(t *testing.T) {
+	tests := map[string]int{"A1": 0, "C3": 2, "Z10": 25, "AA1": 26, "AB12": 27, "BA2": 52, "12": -1, "XFD1": 16383, "ZZZZZZZZZZZZZZZ1": 16384}
+	for ref, expect := range tests {
+		if got := xlsx_column(ref); got != expect {
+			t.Errorf("%s: expected column %d, got: %d", ref, expect, got)
+		}
+	}
+}